@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"log"
 
 	"github.com/aws/aws-lambda-go/events"
@@ -11,10 +12,19 @@ import (
 	"github.com/jobping/backend/internal/app"
 )
 
-var chiLambda *chiadapter.ChiLambda
+var (
+	chiLambda   *chiadapter.ChiLambda
+	appInstance *app.JobsAPIApp
+)
+
+// scheduledEvent is the payload of the EventBridge rules targeting this function
+type scheduledEvent struct {
+	Type string `json:"type"`
+}
 
 func init() {
-	appInstance, err := app.BuildJobsAPI()
+	var err error
+	appInstance, err = app.BuildJobsAPI()
 	if err != nil {
 		log.Fatalf("Failed to build application: %v", err)
 	}
@@ -27,12 +37,26 @@ func init() {
 	chiLambda = chiadapter.New(router)
 }
 
-func handler(ctx context.Context, event events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+// handler serves API Gateway requests, and the scheduled job lifecycle sweep
+func handler(ctx context.Context, payload json.RawMessage) (interface{}, error) {
+	var scheduled scheduledEvent
+	if err := json.Unmarshal(payload, &scheduled); err == nil && scheduled.Type == "sweep_jobs" {
+		result, err := appInstance.Lifecycle.Sweep(ctx)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Job lifecycle sweep: %d expired, %d of %d checked closed, %d archived, %d locations parsed",
+			result.Expired, result.Closed, result.Checked, result.Archived, result.LocationsParsed)
+		return nil, nil
+	}
+
+	var event events.APIGatewayProxyRequest
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
 	return chiLambda.ProxyWithContext(ctx, event)
 }
 
 func main() {
 	lambda.Start(handler)
 }
-
-
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/jobping/backend/internal/app"
 	"github.com/jobping/backend/internal/config"
@@ -22,6 +24,11 @@ func main() {
 		log.Printf("Migration warning: %v", err)
 	}
 
+	go app.EventBroker.Run(context.Background())
+	go app.Privacy.RunPurges(context.Background(), time.Hour)
+	go app.LoginGuard.RunPrunes(context.Background(), time.Hour)
+	go app.JobLifecycle.RunSweeps(context.Background(), time.Hour)
+
 	log.Printf("🚀 Server starting on http://localhost:%s", cfg.Port)
 	log.Printf("📝 Environment: %s", cfg.Environment)
 
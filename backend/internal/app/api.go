@@ -2,17 +2,33 @@ package app
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/jobping/backend/internal/audit"
+	"github.com/jobping/backend/internal/clientip"
 	"github.com/jobping/backend/internal/config"
 	"github.com/jobping/backend/internal/database"
+	adminhandler "github.com/jobping/backend/internal/features/admin/handler"
+	adminsvc "github.com/jobping/backend/internal/features/admin/service"
+	"github.com/jobping/backend/internal/features/job/posting"
+	jobrepo "github.com/jobping/backend/internal/features/job/repository"
+	jobanalysissvc "github.com/jobping/backend/internal/features/job_analysis/service"
 	userhandler "github.com/jobping/backend/internal/features/user/handler"
+	"github.com/jobping/backend/internal/features/user/jwtkeys"
+	"github.com/jobping/backend/internal/features/user/oidc"
 	userrepo "github.com/jobping/backend/internal/features/user/repository"
 	usersvc "github.com/jobping/backend/internal/features/user/service"
+	useranalysissvc "github.com/jobping/backend/internal/features/user_analysis/service"
+	"github.com/jobping/backend/internal/mailer"
 	"github.com/jobping/backend/internal/server"
 )
 
 type APIApp struct {
 	Router http.Handler
+	// Privacy purges accounts whose deletion grace period has ended, on a scheduled event
+	Privacy *usersvc.PrivacyService
+	// LoginGuard prunes old login and registration attempts on the same event
+	LoginGuard *usersvc.LoginGuard
 }
 
 func BuildAPI() (*APIApp, error) {
@@ -29,16 +45,89 @@ func BuildAPI() (*APIApp, error) {
 	userRepo := userrepo.NewUserRepository(db)
 	prefRepo := userrepo.NewPreferenceRepository(db)
 	matchRepo := userrepo.NewUserJobMatchRepository(db)
-	userService := usersvc.NewUserService(userRepo, prefRepo, matchRepo)
-	auth := userhandler.NewAuthMiddleware(cfg.JWTSecret, cfg.JWTExpiry)
-	userHandler := userhandler.NewUserHandler(userService, auth)
+	searchRepo := userrepo.NewSavedSearchRepository(db)
+	sessionRepo := userrepo.NewSessionRepository(db)
+	tokenRepo := userrepo.NewTokenRepository(db)
+	identityRepo := userrepo.NewIdentityRepository(db)
+	attemptRepo := userrepo.NewAttemptRepository(db)
+	passwordPolicy, err := usersvc.NewPasswordPolicy(cfg.PasswordMinLength, cfg.BreachedPasswordsFile)
+	if err != nil {
+		return nil, err
+	}
+	auditRecorder := audit.NewPostgresRecorder(db)
+	loginGuard := usersvc.NewLoginGuard(attemptRepo, auditRecorder, loginLimits(cfg))
+	userService := usersvc.NewUserService(userRepo, prefRepo, matchRepo, searchRepo, loginGuard, passwordPolicy)
+	sessionService := usersvc.NewSessionService(sessionRepo, time.Duration(cfg.RefreshTokenExpiry)*24*time.Hour)
+	mail := mailer.New(cfg)
+	accountService := usersvc.NewAccountService(userRepo, tokenRepo, sessionService, passwordPolicy, mail, cfg.AppBaseURL)
+	oidcProviders, err := oidc.NewProviders(cfg.OIDCProviders)
+	if err != nil {
+		return nil, err
+	}
+	oidcService := usersvc.NewOIDCService(userRepo, identityRepo, oidcProviders, cfg.OIDCRedirectURL)
+	apiTokenRepo := userrepo.NewAPITokenRepository(db)
+	apiTokenService := usersvc.NewAPITokenService(apiTokenRepo, userRepo)
+	privacyService := usersvc.NewPrivacyService(userRepo, userrepo.NewUserDataRepository(db), apiTokenRepo, sessionService, mail, auditRecorder, time.Duration(cfg.AccountDeletionGraceDays)*24*time.Hour)
+	savedSearchService := usersvc.NewSavedSearchService(searchRepo, userRepo, matchRepo)
+	jobRepo := jobrepo.NewJobRepository(db)
+	analysisQueue, err := usersvc.NewSQSAnalysisQueue(cfg.UserAnalysisQueueURL)
+	if err != nil {
+		return nil, err
+	}
+	rescoreService := usersvc.NewRescoreService(searchRepo, matchRepo, userrepo.NewRescoreRepository(db), jobRepo, analysisQueue)
+	pipelineEventRepo := jobrepo.NewPipelineEventRepository(db)
+	explainService := usersvc.NewExplainService(jobRepo, pipelineEventRepo, searchRepo, matchRepo)
+	resumeRepo := userrepo.NewResumeRepository(db)
+	evaluateService := usersvc.NewEvaluateService(jobRepo, pipelineEventRepo, searchRepo, matchRepo, resumeRepo, userrepo.NewEvaluationRepository(db),
+		posting.NewFetcher(), jobanalysissvc.NewAIClient(), useranalysissvc.NewAIClient(), evaluationLimits(cfg))
+	resumeService := usersvc.NewResumeService(resumeRepo, usersvc.NewResumeAnalyzer(), userService)
+	jwtKeys, err := jwtkeys.Load(cfg)
+	if err != nil {
+		return nil, err
+	}
+	auth := userhandler.NewAuthMiddleware(jwtKeys, time.Duration(cfg.JWTExpiry)*time.Minute, apiTokenService)
+	userHandler := userhandler.NewUserHandler(userService, sessionService, accountService, oidcService, apiTokenService, privacyService, savedSearchService, rescoreService, resumeService, explainService, evaluateService, auth)
 
-	// 4. Build router (user routes only)
-	router := server.NewUserRouter(userHandler, auth)
+	// 4. Build admin feature dependencies
+	pipeline, err := adminsvc.NewSQSPipeline(pipelineQueues(cfg))
+	if err != nil {
+		return nil, err
+	}
+	adminService := adminsvc.NewAdminService(userRepo, sessionService, jobRepo, pipeline, auditRecorder)
+	adminHandler := adminhandler.NewHTTPHandler(adminService)
+
+	// 5. Build router (user and admin routes)
+	router := server.NewUserRouter(userHandler, auth, clientip.NewResolver(cfg.TrustedProxies), adminHandler)
 
 	return &APIApp{
-		Router: router,
+		Router:     router,
+		Privacy:    privacyService,
+		LoginGuard: loginGuard,
 	}, nil
 }
 
+func evaluationLimits(cfg *config.Config) usersvc.EvaluationLimits {
+	return usersvc.EvaluationLimits{
+		MaxPerHour: cfg.EvaluateMaxPerHour,
+		Timeout:    time.Duration(cfg.EvaluateTimeoutSeconds) * time.Second,
+	}
+}
 
+func loginLimits(cfg *config.Config) usersvc.LoginLimits {
+	return usersvc.LoginLimits{
+		MaxFailuresPerUser: cfg.LoginMaxFailuresPerUser,
+		MaxFailuresPerIP:   cfg.LoginMaxFailuresPerIP,
+		RegisterPerIP:      cfg.RegisterMaxPerIP,
+		Window:             time.Duration(cfg.LoginWindow) * time.Minute,
+		Lockout:            time.Duration(cfg.LoginLockout) * time.Second,
+	}
+}
+
+func pipelineQueues(cfg *config.Config) map[string]string {
+	return map[string]string{
+		adminsvc.QueueJobAnalysis:  cfg.JobAnalysisQueueURL,
+		adminsvc.QueueUserFanout:   cfg.UserFanoutQueueURL,
+		adminsvc.QueueUserAnalysis: cfg.UserAnalysisQueueURL,
+		adminsvc.QueueNotification: cfg.NotificationQueueURL,
+	}
+}
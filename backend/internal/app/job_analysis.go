@@ -6,6 +6,7 @@ import (
 	jobrepo "github.com/jobping/backend/internal/features/job/repository"
 	jobanalysishandler "github.com/jobping/backend/internal/features/job_analysis/handler"
 	jobanalysissvc "github.com/jobping/backend/internal/features/job_analysis/service"
+	"github.com/jobping/backend/internal/salary"
 )
 
 type JobAnalysisApp struct {
@@ -25,7 +26,11 @@ func BuildJobAnalysis() (*JobAnalysisApp, error) {
 	// 3. Build job analysis feature dependencies
 	jobRepo := jobrepo.NewJobRepository(db)
 	aiClient := jobanalysissvc.NewAIClient()
-	jobAnalysisService := jobanalysissvc.NewJobAnalysisService(jobRepo, aiClient)
+	salaries, err := salary.NewNormalizer(cfg.SalaryBaseCurrency, cfg.SalaryRates)
+	if err != nil {
+		return nil, err
+	}
+	jobAnalysisService := jobanalysissvc.NewJobAnalysisService(jobRepo, jobrepo.NewPipelineEventRepository(db), aiClient, salaries)
 	sqsHandler := jobanalysishandler.NewSQSHandler(jobAnalysisService)
 
 	return &JobAnalysisApp{
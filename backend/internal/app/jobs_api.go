@@ -2,6 +2,7 @@ package app
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/jobping/backend/internal/config"
 	"github.com/jobping/backend/internal/database"
@@ -9,14 +10,20 @@ import (
 	jobrepo "github.com/jobping/backend/internal/features/job/repository"
 	jobsvc "github.com/jobping/backend/internal/features/job/service"
 	notificationhandler "github.com/jobping/backend/internal/features/notification/handler"
+	"github.com/jobping/backend/internal/features/notification/render"
 	notificationrepo "github.com/jobping/backend/internal/features/notification/repository"
 	notificationsvc "github.com/jobping/backend/internal/features/notification/service"
+	userhandler "github.com/jobping/backend/internal/features/user/handler"
+	"github.com/jobping/backend/internal/features/user/jwtkeys"
 	userrepo "github.com/jobping/backend/internal/features/user/repository"
+	usersvc "github.com/jobping/backend/internal/features/user/service"
 	"github.com/jobping/backend/internal/server"
 )
 
 type JobsAPIApp struct {
 	Router http.Handler
+	// Lifecycle expires, closes and archives jobs, on a scheduled event
+	Lifecycle *jobsvc.LifecycleService
 }
 
 func BuildJobsAPI() (*JobsAPIApp, error) {
@@ -31,22 +38,49 @@ func BuildJobsAPI() (*JobsAPIApp, error) {
 
 	// 3. Build job feature dependencies
 	jobRepo := jobrepo.NewJobRepository(db)
-	// Create minimal service for GetJobs (only needs repo)
-	jobService := jobsvc.NewJobService(jobRepo, nil, nil, nil)
+	eventRepo := jobrepo.NewPipelineEventRepository(db)
+	matchRepo := userrepo.NewUserJobMatchRepository(db)
+	// Create minimal service for listing, job detail and the timeline (only needs repos)
+	jobService := jobsvc.NewJobService(jobRepo, eventRepo, nil, matchRepo)
 	jobHandler := jobhandler.NewJobHandler(jobService)
+	lifecycleService := jobLifecycle(cfg, jobRepo)
 
 	// 4. Build notification feature dependencies
 	notifRepo := notificationrepo.NewNotificationRepository(db)
 	userRepo := userrepo.NewUserRepository(db)
-	matchRepo := userrepo.NewUserJobMatchRepository(db)
-	notificationService := notificationsvc.NewNotificationService(jobRepo, userRepo, matchRepo, notifRepo)
-	notificationHandler := notificationhandler.NewHTTPHandler(notificationService)
+	searchRepo := userrepo.NewSavedSearchRepository(db)
+	templateRepo := notificationrepo.NewTemplateRepository(db)
+	renderer, err := render.NewRenderer()
+	if err != nil {
+		return nil, err
+	}
+	templateService := notificationsvc.NewTemplateService(jobRepo, userRepo, matchRepo, templateRepo, renderer)
+	// Read-only here: notifications are delivered by the notifier worker, so no channel senders
+	notificationService := notificationsvc.NewNotificationService(jobRepo, eventRepo, userRepo, matchRepo, searchRepo, notifRepo, templateService, nil)
+	notificationHandler := notificationhandler.NewHTTPHandler(notificationService, templateService)
 
-	// 5. Build router (job + notification routes)
-	router := server.NewJobsRouter(jobHandler, notificationHandler)
+	// 5. Build auth middleware (validates access tokens issued by the api Lambda and API tokens)
+	jwtKeys, err := jwtkeys.Load(cfg)
+	if err != nil {
+		return nil, err
+	}
+	apiTokenService := usersvc.NewAPITokenService(userrepo.NewAPITokenRepository(db), userRepo)
+	auth := userhandler.NewAuthMiddleware(jwtKeys, time.Duration(cfg.JWTExpiry)*time.Minute, apiTokenService)
+
+	// 6. Build router (job + notification routes)
+	router := server.NewJobsRouter(jobHandler, notificationHandler, auth)
 
 	return &JobsAPIApp{
-		Router: router,
+		Router:    router,
+		Lifecycle: lifecycleService,
 	}, nil
 }
 
+// jobLifecycle builds the lifecycle sweeper, checking posting URLs only when enabled
+func jobLifecycle(cfg *config.Config, jobRepo jobrepo.JobRepository) *jobsvc.LifecycleService {
+	var checker jobsvc.URLChecker
+	if cfg.JobURLCheckEnabled {
+		checker = jobsvc.NewURLChecker(&http.Client{Timeout: 5 * time.Second})
+	}
+	return jobsvc.NewLifecycleService(jobRepo, checker, cfg.JobExpiryDays, cfg.JobArchiveDays)
+}
@@ -5,9 +5,11 @@ import (
 	"github.com/jobping/backend/internal/database"
 	jobrepo "github.com/jobping/backend/internal/features/job/repository"
 	notificationhandler "github.com/jobping/backend/internal/features/notification/handler"
+	"github.com/jobping/backend/internal/features/notification/render"
 	notificationrepo "github.com/jobping/backend/internal/features/notification/repository"
 	notificationsvc "github.com/jobping/backend/internal/features/notification/service"
 	userrepo "github.com/jobping/backend/internal/features/user/repository"
+	"github.com/jobping/backend/internal/mailer"
 )
 
 type NotifierApp struct {
@@ -28,8 +30,15 @@ func BuildNotifier() (*NotifierApp, error) {
 	jobRepo := jobrepo.NewJobRepository(db)
 	userRepo := userrepo.NewUserRepository(db)
 	matchRepo := userrepo.NewUserJobMatchRepository(db)
+	searchRepo := userrepo.NewSavedSearchRepository(db)
 	notifRepo := notificationrepo.NewNotificationRepository(db)
-	notificationService := notificationsvc.NewNotificationService(jobRepo, userRepo, matchRepo, notifRepo)
+	renderer, err := render.NewRenderer()
+	if err != nil {
+		return nil, err
+	}
+	templateService := notificationsvc.NewTemplateService(jobRepo, userRepo, matchRepo, notificationrepo.NewTemplateRepository(db), renderer)
+	notificationService := notificationsvc.NewNotificationService(jobRepo, jobrepo.NewPipelineEventRepository(db), userRepo, matchRepo, searchRepo, notifRepo,
+		templateService, notificationsvc.NewChannelSenders(mailer.New(cfg)))
 	sqsHandler := notificationhandler.NewSQSHandler(notificationService)
 
 	return &NotifierApp{
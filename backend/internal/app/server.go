@@ -2,23 +2,47 @@ package app
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/jobping/backend/internal/audit"
+	"github.com/jobping/backend/internal/clientip"
 	"github.com/jobping/backend/internal/config"
 	"github.com/jobping/backend/internal/database"
+	adminhandler "github.com/jobping/backend/internal/features/admin/handler"
+	adminsvc "github.com/jobping/backend/internal/features/admin/service"
+	eventhandler "github.com/jobping/backend/internal/features/event/handler"
+	eventrepo "github.com/jobping/backend/internal/features/event/repository"
+	eventsvc "github.com/jobping/backend/internal/features/event/service"
 	jobhandler "github.com/jobping/backend/internal/features/job/handler"
+	"github.com/jobping/backend/internal/features/job/posting"
 	jobrepo "github.com/jobping/backend/internal/features/job/repository"
 	jobsvc "github.com/jobping/backend/internal/features/job/service"
+	jobanalysissvc "github.com/jobping/backend/internal/features/job_analysis/service"
 	notificationhandler "github.com/jobping/backend/internal/features/notification/handler"
+	"github.com/jobping/backend/internal/features/notification/render"
 	notificationrepo "github.com/jobping/backend/internal/features/notification/repository"
 	notificationsvc "github.com/jobping/backend/internal/features/notification/service"
 	userhandler "github.com/jobping/backend/internal/features/user/handler"
+	"github.com/jobping/backend/internal/features/user/jwtkeys"
+	"github.com/jobping/backend/internal/features/user/oidc"
+	"github.com/jobping/backend/internal/features/user/oidc/oidctest"
 	userrepo "github.com/jobping/backend/internal/features/user/repository"
 	usersvc "github.com/jobping/backend/internal/features/user/service"
+	useranalysissvc "github.com/jobping/backend/internal/features/user_analysis/service"
+	"github.com/jobping/backend/internal/mailer"
 	"github.com/jobping/backend/internal/server"
 )
 
 type ServerApp struct {
 	Router http.Handler
+	// EventBroker must be started with Run for the SSE stream to receive live events
+	EventBroker *eventsvc.Broker
+	// Privacy.RunPurges deletes accounts whose deletion grace period has ended
+	Privacy *usersvc.PrivacyService
+	// LoginGuard.RunPrunes deletes login and registration attempts that left the throttling window
+	LoginGuard *usersvc.LoginGuard
+	// JobLifecycle.RunSweeps expires, closes and archives jobs
+	JobLifecycle *jobsvc.LifecycleService
 }
 
 // BuildServer builds the combined app for local server development
@@ -32,29 +56,106 @@ func BuildServer() (*ServerApp, error) {
 		return nil, err
 	}
 
+	// Local-only mock identity provider, offered as the "mock" login
+	var mockOIDC *oidctest.Provider
+	if cfg.OIDCMock {
+		mockOIDC = oidctest.New("http://localhost:"+cfg.Port+"/mock-oidc", "jobping-local", "jobping-local-secret")
+		cfg.OIDCProviders = append(cfg.OIDCProviders, config.OIDCProvider{
+			Name:         "mock",
+			Kind:         "oidc",
+			Issuer:       mockOIDC.Issuer,
+			ClientID:     mockOIDC.ClientID,
+			ClientSecret: mockOIDC.ClientSecret,
+		})
+	}
+
 	// 3. Build user feature dependencies
 	userRepo := userrepo.NewUserRepository(db)
 	prefRepo := userrepo.NewPreferenceRepository(db)
 	matchRepo := userrepo.NewUserJobMatchRepository(db)
-	userService := usersvc.NewUserService(userRepo, prefRepo, matchRepo)
-	auth := userhandler.NewAuthMiddleware(cfg.JWTSecret, cfg.JWTExpiry)
-	userHandler := userhandler.NewUserHandler(userService, auth)
+	searchRepo := userrepo.NewSavedSearchRepository(db)
+	sessionRepo := userrepo.NewSessionRepository(db)
+	tokenRepo := userrepo.NewTokenRepository(db)
+	identityRepo := userrepo.NewIdentityRepository(db)
+	attemptRepo := userrepo.NewAttemptRepository(db)
+	passwordPolicy, err := usersvc.NewPasswordPolicy(cfg.PasswordMinLength, cfg.BreachedPasswordsFile)
+	if err != nil {
+		return nil, err
+	}
+	auditRecorder := audit.NewPostgresRecorder(db)
+	loginGuard := usersvc.NewLoginGuard(attemptRepo, auditRecorder, loginLimits(cfg))
+	userService := usersvc.NewUserService(userRepo, prefRepo, matchRepo, searchRepo, loginGuard, passwordPolicy)
+	sessionService := usersvc.NewSessionService(sessionRepo, time.Duration(cfg.RefreshTokenExpiry)*24*time.Hour)
+	mail := mailer.New(cfg)
+	accountService := usersvc.NewAccountService(userRepo, tokenRepo, sessionService, passwordPolicy, mail, cfg.AppBaseURL)
+	oidcProviders, err := oidc.NewProviders(cfg.OIDCProviders)
+	if err != nil {
+		return nil, err
+	}
+	oidcService := usersvc.NewOIDCService(userRepo, identityRepo, oidcProviders, cfg.OIDCRedirectURL)
+	apiTokenRepo := userrepo.NewAPITokenRepository(db)
+	apiTokenService := usersvc.NewAPITokenService(apiTokenRepo, userRepo)
+	privacyService := usersvc.NewPrivacyService(userRepo, userrepo.NewUserDataRepository(db), apiTokenRepo, sessionService, mail, auditRecorder, time.Duration(cfg.AccountDeletionGraceDays)*24*time.Hour)
+	savedSearchService := usersvc.NewSavedSearchService(searchRepo, userRepo, matchRepo)
+	jobRepo := jobrepo.NewJobRepository(db)
+	analysisQueue, err := usersvc.NewSQSAnalysisQueue(cfg.UserAnalysisQueueURL)
+	if err != nil {
+		return nil, err
+	}
+	rescoreService := usersvc.NewRescoreService(searchRepo, matchRepo, userrepo.NewRescoreRepository(db), jobRepo, analysisQueue)
+	pipelineEventRepo := jobrepo.NewPipelineEventRepository(db)
+	explainService := usersvc.NewExplainService(jobRepo, pipelineEventRepo, searchRepo, matchRepo)
+	resumeRepo := userrepo.NewResumeRepository(db)
+	evaluateService := usersvc.NewEvaluateService(jobRepo, pipelineEventRepo, searchRepo, matchRepo, resumeRepo, userrepo.NewEvaluationRepository(db),
+		posting.NewFetcher(), jobanalysissvc.NewAIClient(), useranalysissvc.NewAIClient(), evaluationLimits(cfg))
+	resumeService := usersvc.NewResumeService(resumeRepo, usersvc.NewResumeAnalyzer(), userService)
+	jwtKeys, err := jwtkeys.Load(cfg)
+	if err != nil {
+		return nil, err
+	}
+	auth := userhandler.NewAuthMiddleware(jwtKeys, time.Duration(cfg.JWTExpiry)*time.Minute, apiTokenService)
+	userHandler := userhandler.NewUserHandler(userService, sessionService, accountService, oidcService, apiTokenService, privacyService, savedSearchService, rescoreService, resumeService, explainService, evaluateService, auth)
 
 	// 4. Build job feature dependencies
-	jobRepo := jobrepo.NewJobRepository(db)
-	jobService := jobsvc.NewJobService(jobRepo, nil, nil, nil)
+	jobService := jobsvc.NewJobService(jobRepo, pipelineEventRepo, nil, matchRepo)
 	jobHandler := jobhandler.NewJobHandler(jobService)
 
 	// 5. Build notification feature dependencies
 	notifRepo := notificationrepo.NewNotificationRepository(db)
-	notificationService := notificationsvc.NewNotificationService(jobRepo, userRepo, matchRepo, notifRepo)
-	notificationHandler := notificationhandler.NewHTTPHandler(notificationService)
+	templateRepo := notificationrepo.NewTemplateRepository(db)
+	renderer, err := render.NewRenderer()
+	if err != nil {
+		return nil, err
+	}
+	templateService := notificationsvc.NewTemplateService(jobRepo, userRepo, matchRepo, templateRepo, renderer)
+	notificationService := notificationsvc.NewNotificationService(jobRepo, pipelineEventRepo, userRepo, matchRepo, searchRepo, notifRepo,
+		templateService, notificationsvc.NewChannelSenders(mail))
+	notificationHandler := notificationhandler.NewHTTPHandler(notificationService, templateService)
 
-	// 6. Build router (combined for local dev)
-	router := server.NewRouterWithNotification(userHandler, auth, jobHandler, notificationHandler)
+	// 6. Build event stream dependencies
+	eventRepo := eventrepo.NewEventRepository(db)
+	eventBroker := eventsvc.NewBroker(db, eventRepo)
+	sseHandler := eventhandler.NewSSEHandler(eventBroker)
+
+	// 7. Build admin feature dependencies
+	pipeline, err := adminsvc.NewSQSPipeline(pipelineQueues(cfg))
+	if err != nil {
+		return nil, err
+	}
+	adminService := adminsvc.NewAdminService(userRepo, sessionService, jobRepo, pipeline, auditRecorder)
+	adminHandler := adminhandler.NewHTTPHandler(adminService)
+
+	// 8. Build router (combined for local dev)
+	router := server.NewRouterWithNotification(userHandler, auth, clientip.NewResolver(cfg.TrustedProxies), adminHandler, jobHandler, notificationHandler, sseHandler)
+	if mockOIDC != nil {
+		router.Mount("/mock-oidc", http.StripPrefix("/mock-oidc", mockOIDC))
+	}
 
 	return &ServerApp{
-		Router: router,
+		Router:       router,
+		EventBroker:  eventBroker,
+		Privacy:      privacyService,
+		LoginGuard:   loginGuard,
+		JobLifecycle: jobLifecycle(cfg, jobRepo),
 	}, nil
 }
-
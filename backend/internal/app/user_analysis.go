@@ -27,8 +27,11 @@ func BuildUserAnalysis() (*UserAnalysisApp, error) {
 	jobRepo := jobrepo.NewJobRepository(db)
 	userRepo := userrepo.NewUserRepository(db)
 	matchRepo := userrepo.NewUserJobMatchRepository(db)
+	searchRepo := userrepo.NewSavedSearchRepository(db)
+	rescoreRepo := userrepo.NewRescoreRepository(db)
+	resumeRepo := userrepo.NewResumeRepository(db)
 	aiClient := useranalysissvc.NewAIClient()
-	userAnalysisService := useranalysissvc.NewUserAnalysisService(jobRepo, userRepo, matchRepo, aiClient)
+	userAnalysisService := useranalysissvc.NewUserAnalysisService(jobRepo, jobrepo.NewPipelineEventRepository(db), userRepo, matchRepo, searchRepo, rescoreRepo, resumeRepo, aiClient)
 	sqsHandler := useranalysishandler.NewSQSHandler(userAnalysisService)
 
 	return &UserAnalysisApp{
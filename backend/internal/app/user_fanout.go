@@ -3,6 +3,7 @@ package app
 import (
 	"github.com/jobping/backend/internal/config"
 	"github.com/jobping/backend/internal/database"
+	jobrepo "github.com/jobping/backend/internal/features/job/repository"
 	userfanouthandler "github.com/jobping/backend/internal/features/user_fanout/handler"
 	userfanoutsvc "github.com/jobping/backend/internal/features/user_fanout/service"
 	userrepo "github.com/jobping/backend/internal/features/user/repository"
@@ -23,8 +24,8 @@ func BuildUserFanout() (*UserFanoutApp, error) {
 	}
 
 	// 3. Build user fanout feature dependencies
-	userRepo := userrepo.NewUserRepository(db)
-	fanoutService := userfanoutsvc.NewFanoutService(userRepo)
+	searchRepo := userrepo.NewSavedSearchRepository(db)
+	fanoutService := userfanoutsvc.NewFanoutService(searchRepo, jobrepo.NewJobRepository(db), jobrepo.NewPipelineEventRepository(db))
 	sqsHandler := userfanouthandler.NewSQSHandler(fanoutService)
 
 	return &UserFanoutApp{
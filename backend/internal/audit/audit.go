@@ -0,0 +1,34 @@
+// Package audit records security-relevant actions (failed logins, admin operations) in the
+// audit_log table. Recording is best effort: callers log a failed Record and carry on. Operations
+// that must not happen unaudited write their entry in their own transaction with Write.
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ActionLoginFailed     = "auth.login_failed"
+	ActionLoginLocked     = "auth.login_locked"
+	ActionRegisterLimited = "auth.register_rate_limited"
+)
+
+// Entry is one audit record. ActorID is nil for anonymous actions such as failed logins.
+type Entry struct {
+	ID         uuid.UUID
+	ActorID    *uuid.UUID
+	Action     string
+	TargetType string
+	TargetID   string
+	IPAddress  string
+	UserAgent  string
+	Metadata   map[string]interface{}
+	CreatedAt  time.Time
+}
+
+type Recorder interface {
+	Record(ctx context.Context, entry *Entry) error
+}
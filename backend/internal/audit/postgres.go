@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Execer is satisfied by *pgxpool.Pool and pgx.Tx
+type Execer interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+}
+
+type postgresRecorder struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRecorder(db *pgxpool.Pool) Recorder {
+	return &postgresRecorder{db: db}
+}
+
+func (r *postgresRecorder) Record(ctx context.Context, entry *Entry) error {
+	return Write(ctx, r.db, entry)
+}
+
+// Write inserts the entry with db. An operation that must not happen without its audit entry
+// passes its transaction, so the entry commits or rolls back with it.
+func Write(ctx context.Context, db Execer, entry *Entry) error {
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO audit_log (id, actor_id, action, target_type, target_id, ip_address, user_agent, metadata, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := db.Exec(ctx, query,
+		entry.ID, entry.ActorID, entry.Action, entry.TargetType, entry.TargetID,
+		entry.IPAddress, entry.UserAgent, entry.Metadata, entry.CreatedAt,
+	)
+	return err
+}
@@ -0,0 +1,104 @@
+// Package clientip finds the address of the client behind a request, for rate limits, sessions and
+// the audit log. X-Forwarded-For is set by the client and only appended to by proxies, so it is
+// only read when the request came through a configured trusted proxy.
+package clientip
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/awslabs/aws-lambda-go-api-proxy/core"
+)
+
+type contextKey struct{}
+
+// Resolver resolves client addresses once per request in its Middleware
+type Resolver struct {
+	trusted []*net.IPNet
+}
+
+// NewResolver trusts X-Forwarded-For hops added by the given proxies, as IPs or CIDR ranges.
+// Invalid entries are logged and ignored.
+func NewResolver(trustedProxies []string) *Resolver {
+	r := &Resolver{}
+	for _, entry := range trustedProxies {
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			log.Printf("Ignoring invalid trusted proxy %q: %v", entry, err)
+			continue
+		}
+		r.trusted = append(r.trusted, network)
+	}
+	return r
+}
+
+// Middleware stores the client address in the request context for FromRequest
+func (r *Resolver) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := context.WithValue(req.Context(), contextKey{}, r.resolve(req))
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+// FromRequest returns the address Middleware resolved, or the connection's peer without it
+func FromRequest(req *http.Request) string {
+	if ip, ok := req.Context().Value(contextKey{}).(string); ok {
+		return ip
+	}
+	return remoteHost(req)
+}
+
+// resolve prefers the source IP API Gateway saw. Otherwise X-Forwarded-For is walked from the
+// right past trusted proxies, so the result is the last hop no trusted proxy vouches for.
+func (r *Resolver) resolve(req *http.Request) string {
+	if gateway, ok := core.GetAPIGatewayContextFromContext(req.Context()); ok && gateway.Identity.SourceIP != "" {
+		return gateway.Identity.SourceIP
+	}
+
+	peer := remoteHost(req)
+	if !r.isTrusted(peer) {
+		return peer
+	}
+	hops := strings.Split(req.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		if !r.isTrusted(hop) {
+			return hop
+		}
+		peer = hop
+	}
+	return peer
+}
+
+func (r *Resolver) isTrusted(address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, network := range r.trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func remoteHost(req *http.Request) string {
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		return host
+	}
+	return req.RemoteAddr
+}
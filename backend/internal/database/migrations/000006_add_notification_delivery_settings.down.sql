@@ -0,0 +1,14 @@
+-- Remove notification delivery tracking
+DROP INDEX IF EXISTS idx_notifications_user_delivery_created;
+ALTER TABLE notifications DROP COLUMN IF EXISTS delivery;
+
+-- Remove match deferral
+DROP INDEX IF EXISTS idx_user_job_matches_deferred;
+ALTER TABLE user_job_matches DROP COLUMN IF EXISTS deferred_reason;
+ALTER TABLE user_job_matches DROP COLUMN IF EXISTS deferred_at;
+
+-- Remove user delivery settings
+ALTER TABLE users DROP COLUMN IF EXISTS max_notifications_per_hour;
+ALTER TABLE users DROP COLUMN IF EXISTS quiet_hours_end;
+ALTER TABLE users DROP COLUMN IF EXISTS quiet_hours_start;
+ALTER TABLE users DROP COLUMN IF EXISTS timezone;
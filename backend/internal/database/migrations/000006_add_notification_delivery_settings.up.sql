@@ -0,0 +1,14 @@
+-- Per-user delivery settings for instant notifications
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS quiet_hours_start SMALLINT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS quiet_hours_end SMALLINT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS max_notifications_per_hour INTEGER;
+
+-- Matches held back from instant delivery until the next digest
+ALTER TABLE user_job_matches ADD COLUMN IF NOT EXISTS deferred_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE user_job_matches ADD COLUMN IF NOT EXISTS deferred_reason VARCHAR(20);
+CREATE INDEX IF NOT EXISTS idx_user_job_matches_deferred ON user_job_matches(user_id) WHERE deferred_at IS NOT NULL AND notified = FALSE;
+
+-- How a notification was delivered (instant or digest)
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS delivery VARCHAR(20) NOT NULL DEFAULT 'instant';
+CREATE INDEX IF NOT EXISTS idx_notifications_user_delivery_created ON notifications(user_id, delivery, created_at DESC);
@@ -0,0 +1,3 @@
+-- Drop notification template overrides
+DROP INDEX IF EXISTS idx_notification_templates_user_id;
+DROP TABLE IF EXISTS notification_templates;
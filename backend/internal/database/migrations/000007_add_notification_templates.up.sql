@@ -0,0 +1,14 @@
+-- Per-user notification template overrides (one per channel)
+CREATE TABLE IF NOT EXISTS notification_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel VARCHAR(20) NOT NULL,
+    subject TEXT,
+    body TEXT,
+    text_body TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(user_id, channel)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_templates_user_id ON notification_templates(user_id);
@@ -0,0 +1,4 @@
+-- Drop user event log
+DROP INDEX IF EXISTS idx_user_events_created_at;
+DROP INDEX IF EXISTS idx_user_events_user_id_id;
+DROP TABLE IF EXISTS user_events;
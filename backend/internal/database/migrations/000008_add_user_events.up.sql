@@ -0,0 +1,11 @@
+-- Append-only log of per-user events streamed over SSE (ids double as Last-Event-ID)
+CREATE TABLE IF NOT EXISTS user_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_events_user_id_id ON user_events(user_id, id);
+CREATE INDEX IF NOT EXISTS idx_user_events_created_at ON user_events(created_at);
@@ -0,0 +1,3 @@
+-- Remove notification read state
+DROP INDEX IF EXISTS idx_notifications_user_unread;
+ALTER TABLE notifications DROP COLUMN IF EXISTS read_at;
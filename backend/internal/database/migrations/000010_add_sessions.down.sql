@@ -0,0 +1,4 @@
+-- Drop refresh token sessions
+DROP INDEX IF EXISTS idx_sessions_user_active;
+DROP INDEX IF EXISTS idx_sessions_family_id;
+DROP TABLE IF EXISTS sessions;
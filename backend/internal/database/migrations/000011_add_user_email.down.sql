@@ -0,0 +1,6 @@
+-- Drop email tokens and user email columns
+DROP INDEX IF EXISTS idx_user_tokens_user_purpose;
+DROP TABLE IF EXISTS user_tokens;
+DROP INDEX IF EXISTS idx_users_email;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
+ALTER TABLE users DROP COLUMN IF EXISTS email;
@@ -0,0 +1,7 @@
+-- Drop audit log and login throttling
+DROP INDEX IF EXISTS idx_audit_log_action;
+DROP INDEX IF EXISTS idx_audit_log_created_at;
+DROP TABLE IF EXISTS audit_log;
+DROP INDEX IF EXISTS idx_auth_attempts_ip;
+DROP INDEX IF EXISTS idx_auth_attempts_username;
+DROP TABLE IF EXISTS auth_attempts;
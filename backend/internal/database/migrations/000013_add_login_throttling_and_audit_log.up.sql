@@ -0,0 +1,28 @@
+-- Recent login failures and registrations, used for sliding-window rate limits.
+-- Rows older than the window are pruned by the application.
+CREATE TABLE IF NOT EXISTS auth_attempts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind VARCHAR(20) NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    ip_address TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_attempts_username ON auth_attempts(kind, username, created_at);
+CREATE INDEX IF NOT EXISTS idx_auth_attempts_ip ON auth_attempts(kind, ip_address, created_at);
+
+-- Security-relevant actions (failed logins, lockouts, admin operations)
+CREATE TABLE IF NOT EXISTS audit_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(100) NOT NULL,
+    target_type VARCHAR(50),
+    target_id VARCHAR(255),
+    ip_address TEXT,
+    user_agent TEXT,
+    metadata JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_action ON audit_log(action, created_at DESC);
@@ -0,0 +1,2 @@
+-- Remove personal API tokens
+DROP TABLE IF EXISTS api_tokens;
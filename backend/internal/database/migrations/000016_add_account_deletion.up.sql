@@ -0,0 +1,6 @@
+-- Accounts scheduled for deletion are purged once deletion_scheduled_at has passed;
+-- until then the user can sign in and cancel
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_scheduled_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled_at ON users(deletion_scheduled_at)
+    WHERE deletion_scheduled_at IS NOT NULL;
@@ -0,0 +1,12 @@
+-- Remove saved searches; matches of non-default searches are dropped
+ALTER TABLE notifications DROP COLUMN IF EXISTS channels;
+
+DELETE FROM user_job_matches m USING saved_searches s
+WHERE s.id = m.saved_search_id AND NOT s.is_default;
+
+DROP INDEX IF EXISTS idx_user_job_matches_user_job;
+ALTER TABLE user_job_matches DROP CONSTRAINT IF EXISTS user_job_matches_saved_search_id_job_id_key;
+ALTER TABLE user_job_matches DROP COLUMN IF EXISTS saved_search_id;
+ALTER TABLE user_job_matches ADD CONSTRAINT user_job_matches_user_id_job_id_key UNIQUE (user_id, job_id);
+
+DROP TABLE IF EXISTS saved_searches;
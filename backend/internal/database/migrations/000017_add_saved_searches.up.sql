@@ -0,0 +1,42 @@
+-- Named saved searches: each has its own prompt, filters, threshold and notification channels
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    ai_prompt TEXT NOT NULL,
+    filters JSONB NOT NULL DEFAULT '{}',
+    notify_threshold INTEGER NOT NULL DEFAULT 70,
+    channels TEXT[] NOT NULL DEFAULT '{discord}',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    -- The default search mirrors the legacy users.ai_prompt and users.notify_threshold
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_user_id ON saved_searches(user_id);
+CREATE INDEX IF NOT EXISTS idx_saved_searches_active ON saved_searches(user_id) WHERE active;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_saved_searches_default ON saved_searches(user_id) WHERE is_default;
+
+-- Existing prompts (and users who already have matches) become the default search
+INSERT INTO saved_searches (user_id, name, ai_prompt, notify_threshold, active, is_default, created_at, updated_at)
+SELECT u.id, 'Default', COALESCE(u.ai_prompt, ''), COALESCE(u.notify_threshold, 70),
+       COALESCE(u.ai_prompt, '') <> '', TRUE, u.created_at, NOW()
+FROM users u
+WHERE COALESCE(u.ai_prompt, '') <> ''
+   OR EXISTS (SELECT 1 FROM user_job_matches m WHERE m.user_id = u.id)
+ON CONFLICT DO NOTHING;
+
+-- Matches are keyed by saved search instead of (user_id, job_id)
+ALTER TABLE user_job_matches ADD COLUMN IF NOT EXISTS saved_search_id UUID REFERENCES saved_searches(id) ON DELETE CASCADE;
+UPDATE user_job_matches m SET saved_search_id = s.id
+FROM saved_searches s
+WHERE s.user_id = m.user_id AND s.is_default AND m.saved_search_id IS NULL;
+ALTER TABLE user_job_matches ALTER COLUMN saved_search_id SET NOT NULL;
+
+ALTER TABLE user_job_matches DROP CONSTRAINT IF EXISTS user_job_matches_user_id_job_id_key;
+ALTER TABLE user_job_matches ADD CONSTRAINT user_job_matches_saved_search_id_job_id_key UNIQUE (saved_search_id, job_id);
+CREATE INDEX IF NOT EXISTS idx_user_job_matches_user_job ON user_job_matches(user_id, job_id);
+
+-- Channels the notification is for, copied from the saved search when it was created
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS channels TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,5 @@
+-- Remove prompt history and re-scoring runs
+DROP TABLE IF EXISTS rescore_runs;
+ALTER TABLE user_job_matches DROP COLUMN IF EXISTS prompt_revision_id;
+ALTER TABLE saved_searches DROP COLUMN IF EXISTS prompt_revision_id;
+DROP TABLE IF EXISTS prompt_revisions;
@@ -0,0 +1,42 @@
+-- Every prompt a saved search has had, so matches can record which one they were scored against
+CREATE TABLE IF NOT EXISTS prompt_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    saved_search_id UUID NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    ai_prompt TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_prompt_revisions_search ON prompt_revisions(saved_search_id, created_at DESC);
+
+-- The search's current revision (the newest row above)
+ALTER TABLE saved_searches ADD COLUMN IF NOT EXISTS prompt_revision_id UUID;
+
+-- Current prompts become the first revision. Existing matches keep a NULL revision:
+-- it is unknown which prompt they were scored against.
+INSERT INTO prompt_revisions (saved_search_id, user_id, ai_prompt, created_at)
+SELECT s.id, s.user_id, s.ai_prompt, s.updated_at
+FROM saved_searches s
+WHERE s.ai_prompt <> '';
+
+UPDATE saved_searches s SET prompt_revision_id = r.id
+FROM prompt_revisions r
+WHERE r.saved_search_id = s.id;
+
+ALTER TABLE user_job_matches ADD COLUMN IF NOT EXISTS prompt_revision_id UUID REFERENCES prompt_revisions(id) ON DELETE SET NULL;
+
+-- Re-scoring runs: recent jobs re-queued to user analysis after a prompt change
+CREATE TABLE IF NOT EXISTS rescore_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    saved_search_id UUID NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    prompt_revision_id UUID REFERENCES prompt_revisions(id) ON DELETE SET NULL,
+    days INTEGER NOT NULL,
+    total INTEGER NOT NULL DEFAULT 0,
+    completed INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_rescore_runs_user ON rescore_runs(user_id, created_at DESC);
@@ -0,0 +1,2 @@
+-- Remove resume profiles
+DROP TABLE IF EXISTS resume_profiles;
@@ -0,0 +1,16 @@
+-- Profile derived from an uploaded resume, one per user; a new upload replaces it
+CREATE TABLE IF NOT EXISTS resume_profiles (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL DEFAULT '',
+    format VARCHAR(10) NOT NULL,
+    resume_text TEXT NOT NULL,
+    skills TEXT[] NOT NULL DEFAULT '{}',
+    seniority VARCHAR(20) NOT NULL DEFAULT '',
+    locations TEXT[] NOT NULL DEFAULT '{}',
+    salary_min INTEGER,
+    salary_max INTEGER,
+    salary_currency VARCHAR(3) NOT NULL DEFAULT '',
+    suggested_prompt TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
@@ -0,0 +1,12 @@
+-- Remove structured job attributes
+DROP INDEX IF EXISTS idx_jobs_tech_stack;
+DROP INDEX IF EXISTS idx_jobs_required_skills;
+DROP INDEX IF EXISTS idx_jobs_seniority;
+
+ALTER TABLE jobs DROP COLUMN IF EXISTS attributes_extracted_at;
+ALTER TABLE jobs DROP COLUMN IF EXISTS attribute_sources;
+ALTER TABLE jobs DROP COLUMN IF EXISTS tech_stack;
+ALTER TABLE jobs DROP COLUMN IF EXISTS visa_sponsorship;
+ALTER TABLE jobs DROP COLUMN IF EXISTS years_experience;
+ALTER TABLE jobs DROP COLUMN IF EXISTS required_skills;
+ALTER TABLE jobs DROP COLUMN IF EXISTS seniority;
@@ -0,0 +1,13 @@
+-- Structured attributes extracted from the job description during job analysis
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS seniority VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS required_skills TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS years_experience INTEGER;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS visa_sponsorship BOOLEAN;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS tech_stack TEXT[] NOT NULL DEFAULT '{}';
+-- Which extractor supplied each attribute ("llm" or "heuristic"), for debugging bad extractions
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS attribute_sources JSONB;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS attributes_extracted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_jobs_seniority ON jobs(seniority);
+CREATE INDEX IF NOT EXISTS idx_jobs_required_skills ON jobs USING GIN (required_skills);
+CREATE INDEX IF NOT EXISTS idx_jobs_tech_stack ON jobs USING GIN (tech_stack);
@@ -0,0 +1,7 @@
+-- Remove salary currency, interval and normalized values
+DROP INDEX IF EXISTS idx_jobs_salary_normalized;
+
+ALTER TABLE jobs DROP COLUMN IF EXISTS max_salary_normalized;
+ALTER TABLE jobs DROP COLUMN IF EXISTS min_salary_normalized;
+ALTER TABLE jobs DROP COLUMN IF EXISTS salary_interval;
+ALTER TABLE jobs DROP COLUMN IF EXISTS salary_currency;
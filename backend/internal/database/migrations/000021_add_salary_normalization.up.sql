@@ -0,0 +1,14 @@
+-- Currency and pay interval of the posted salary, and the salary normalized to a yearly amount in
+-- the base currency (SALARY_BASE_CURRENCY) by job analysis. min_salary/max_salary keep the raw values.
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS salary_currency VARCHAR(3) NOT NULL DEFAULT '';
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS salary_interval VARCHAR(10) NOT NULL DEFAULT '';
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS min_salary_normalized DECIMAL(12, 2);
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS max_salary_normalized DECIMAL(12, 2);
+
+-- Existing jobs were scraped from US boards without currency or interval. Treat amounts that can
+-- only be yearly pay as yearly USD, the default base currency; leave the rest unnormalized rather than guess.
+UPDATE jobs
+SET min_salary_normalized = min_salary, max_salary_normalized = max_salary
+WHERE COALESCE(min_salary, max_salary) >= 10000;
+
+CREATE INDEX IF NOT EXISTS idx_jobs_salary_normalized ON jobs((COALESCE(max_salary_normalized, min_salary_normalized)) DESC NULLS LAST);
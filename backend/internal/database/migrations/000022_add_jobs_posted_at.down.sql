@@ -0,0 +1,3 @@
+-- Remove the parsed posting timestamp; date_posted keeps the original string
+DROP INDEX IF EXISTS idx_jobs_posted_at;
+ALTER TABLE jobs DROP COLUMN IF EXISTS posted_at;
@@ -0,0 +1,42 @@
+-- When the job was posted, parsed from the scraper's free-text date_posted by job analysis.
+-- New rows default to their ingestion time until analysis parses the string.
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS posted_at TIMESTAMP WITH TIME ZONE;
+
+-- Backfill ISO dates ("2024-05-01", "2024-05-01T10:00:00Z"). Rows are cast one by one so an
+-- invalid date falls through to the defaults below instead of failing the migration.
+DO $$
+DECLARE
+    r RECORD;
+BEGIN
+    FOR r IN SELECT id, date_posted FROM jobs WHERE date_posted ~ '^\d{4}-\d{2}-\d{2}' LOOP
+        BEGIN
+            UPDATE jobs SET posted_at = LEAST(r.date_posted::timestamptz, created_at) WHERE id = r.id;
+        EXCEPTION WHEN others THEN
+            BEGIN
+                UPDATE jobs SET posted_at = LEAST(LEFT(r.date_posted, 10)::date::timestamptz, created_at) WHERE id = r.id;
+            EXCEPTION WHEN others THEN
+                NULL;
+            END;
+        END;
+    END LOOP;
+END $$;
+
+-- Backfill relative strings ("3 days ago", "30+ days ago") counting back from ingestion
+UPDATE jobs
+SET posted_at = created_at - (
+    substring(lower(date_posted) FROM '^(\d+)\+?\s*(?:minute|hour|day|week|month|year)s?\s+ago$')
+    || ' ' ||
+    substring(lower(date_posted) FROM '^\d+\+?\s*(minute|hour|day|week|month|year)s?\s+ago$')
+)::interval
+WHERE posted_at IS NULL
+  AND lower(date_posted) ~ '^\d+\+?\s*(minute|hour|day|week|month|year)s?\s+ago$';
+
+UPDATE jobs SET posted_at = created_at - INTERVAL '1 day' WHERE posted_at IS NULL AND lower(date_posted) = 'yesterday';
+
+-- Anything else, including empty values, counts as posted when it was ingested
+UPDATE jobs SET posted_at = COALESCE(created_at, NOW()) WHERE posted_at IS NULL;
+
+ALTER TABLE jobs ALTER COLUMN posted_at SET DEFAULT NOW();
+ALTER TABLE jobs ALTER COLUMN posted_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_jobs_posted_at ON jobs(posted_at DESC);
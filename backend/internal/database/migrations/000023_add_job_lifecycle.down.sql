@@ -0,0 +1,7 @@
+-- Remove the job lifecycle and the archive. Archived jobs are dropped, not restored.
+DROP TABLE IF EXISTS jobs_archive;
+
+DROP INDEX IF EXISTS idx_jobs_lifecycle;
+ALTER TABLE jobs DROP COLUMN IF EXISTS url_checked_at;
+ALTER TABLE jobs DROP COLUMN IF EXISTS lifecycle_changed_at;
+ALTER TABLE jobs DROP COLUMN IF EXISTS lifecycle;
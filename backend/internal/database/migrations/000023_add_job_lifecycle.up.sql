@@ -0,0 +1,17 @@
+-- Job lifecycle, separate from the analysis status: active postings, expired (too old), closed
+-- (the posting URL is gone) and, in jobs_archive, archived
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS lifecycle VARCHAR(20) NOT NULL DEFAULT 'active';
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS lifecycle_changed_at TIMESTAMP WITH TIME ZONE;
+-- Last time the sweeper checked that job_url still resolves
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS url_checked_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_jobs_lifecycle ON jobs(lifecycle, lifecycle_changed_at);
+
+-- Expired and closed jobs are moved here after a while, so jobs stays small. Same columns as jobs,
+-- in the same order, plus archived_at; migrations adding jobs columns must add them here too.
+CREATE TABLE IF NOT EXISTS jobs_archive (LIKE jobs INCLUDING DEFAULTS);
+ALTER TABLE jobs_archive ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+ALTER TABLE jobs_archive ADD PRIMARY KEY (id);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_archive_archived_at ON jobs_archive(archived_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_archive_job_url ON jobs_archive(job_url);
@@ -0,0 +1,4 @@
+-- Remove the job source
+DROP INDEX IF EXISTS idx_jobs_source;
+ALTER TABLE jobs_archive DROP COLUMN IF EXISTS source;
+ALTER TABLE jobs DROP COLUMN IF EXISTS source;
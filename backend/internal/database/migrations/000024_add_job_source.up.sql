@@ -0,0 +1,16 @@
+-- Job board a job was scraped from (indeed, linkedin, ...), so bulk operations can target one source
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS source VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE jobs_archive ADD COLUMN IF NOT EXISTS source VARCHAR(50) NOT NULL DEFAULT '';
+
+-- Existing jobs: derive the source from the posting URL's host
+UPDATE jobs SET source = CASE
+    WHEN job_url ~* '^https?://([^/]*\.)?indeed\.' THEN 'indeed'
+    WHEN job_url ~* '^https?://([^/]*\.)?linkedin\.' THEN 'linkedin'
+    WHEN job_url ~* '^https?://([^/]*\.)?glassdoor\.' THEN 'glassdoor'
+    WHEN job_url ~* '^https?://([^/]*\.)?ziprecruiter\.' THEN 'zip_recruiter'
+    WHEN job_url ~* '^https?://([^/]*\.)?google\.' THEN 'google'
+    ELSE ''
+END
+WHERE source = '';
+
+CREATE INDEX IF NOT EXISTS idx_jobs_source ON jobs(source);
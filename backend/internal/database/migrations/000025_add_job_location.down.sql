@@ -0,0 +1,20 @@
+-- Remove the parsed job location
+DROP INDEX IF EXISTS idx_jobs_location_unparsed;
+DROP INDEX IF EXISTS idx_jobs_workplace_type;
+DROP INDEX IF EXISTS idx_jobs_coordinates;
+
+ALTER TABLE jobs_archive DROP COLUMN IF EXISTS location_parsed_at;
+ALTER TABLE jobs_archive DROP COLUMN IF EXISTS longitude;
+ALTER TABLE jobs_archive DROP COLUMN IF EXISTS latitude;
+ALTER TABLE jobs_archive DROP COLUMN IF EXISTS workplace_type;
+ALTER TABLE jobs_archive DROP COLUMN IF EXISTS location_country;
+ALTER TABLE jobs_archive DROP COLUMN IF EXISTS location_region;
+ALTER TABLE jobs_archive DROP COLUMN IF EXISTS location_city;
+
+ALTER TABLE jobs DROP COLUMN IF EXISTS location_parsed_at;
+ALTER TABLE jobs DROP COLUMN IF EXISTS longitude;
+ALTER TABLE jobs DROP COLUMN IF EXISTS latitude;
+ALTER TABLE jobs DROP COLUMN IF EXISTS workplace_type;
+ALTER TABLE jobs DROP COLUMN IF EXISTS location_country;
+ALTER TABLE jobs DROP COLUMN IF EXISTS location_region;
+ALTER TABLE jobs DROP COLUMN IF EXISTS location_city;
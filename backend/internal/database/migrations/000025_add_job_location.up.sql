@@ -0,0 +1,23 @@
+-- Parsed job location: city, region and country, workplace type (remote, hybrid, onsite) and the
+-- city's coordinates from the embedded gazetteer. Filled in by job analysis; older rows are parsed
+-- by the lifecycle sweep, which picks up rows where location_parsed_at is NULL.
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS location_city VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS location_region VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS location_country VARCHAR(2) NOT NULL DEFAULT '';
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS workplace_type VARCHAR(10) NOT NULL DEFAULT '';
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS location_parsed_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE jobs_archive ADD COLUMN IF NOT EXISTS location_city VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE jobs_archive ADD COLUMN IF NOT EXISTS location_region VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE jobs_archive ADD COLUMN IF NOT EXISTS location_country VARCHAR(2) NOT NULL DEFAULT '';
+ALTER TABLE jobs_archive ADD COLUMN IF NOT EXISTS workplace_type VARCHAR(10) NOT NULL DEFAULT '';
+ALTER TABLE jobs_archive ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+ALTER TABLE jobs_archive ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+ALTER TABLE jobs_archive ADD COLUMN IF NOT EXISTS location_parsed_at TIMESTAMP WITH TIME ZONE;
+
+-- Radius searches narrow by a bounding box on these before computing distances
+CREATE INDEX IF NOT EXISTS idx_jobs_coordinates ON jobs(latitude, longitude) WHERE latitude IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_jobs_workplace_type ON jobs(workplace_type);
+CREATE INDEX IF NOT EXISTS idx_jobs_location_unparsed ON jobs(created_at) WHERE location_parsed_at IS NULL;
@@ -0,0 +1,2 @@
+-- Drop the job pipeline timeline
+DROP TABLE IF EXISTS job_pipeline_events;
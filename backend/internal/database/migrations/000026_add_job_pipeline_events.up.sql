@@ -0,0 +1,21 @@
+-- One row per pipeline stage a job went through, so "why wasn't I notified" can be answered
+-- without searching the logs of four workers. Per-user stages carry user_id and saved_search_id.
+CREATE TABLE IF NOT EXISTS job_pipeline_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    saved_search_id UUID REFERENCES saved_searches(id) ON DELETE CASCADE,
+    stage VARCHAR(30) NOT NULL,
+    outcome VARCHAR(30) NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_pipeline_events_job_id ON job_pipeline_events(job_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_job_pipeline_events_user_id ON job_pipeline_events(user_id, job_id) WHERE user_id IS NOT NULL;
+
+-- Existing jobs start their timeline at ingestion
+INSERT INTO job_pipeline_events (job_id, stage, outcome, details, created_at)
+SELECT id, 'ingested', 'created', jsonb_build_object('source', source), created_at
+FROM jobs;
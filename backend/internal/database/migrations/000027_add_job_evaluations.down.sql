@@ -0,0 +1,2 @@
+-- Drop the job evaluation rate limit table
+DROP TABLE IF EXISTS job_evaluations;
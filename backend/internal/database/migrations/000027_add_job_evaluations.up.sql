@@ -0,0 +1,9 @@
+-- On-demand job evaluations, counted for the per-user hourly limit.
+-- Rows older than the window are pruned by the application.
+CREATE TABLE IF NOT EXISTS job_evaluations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_evaluations_user_id ON job_evaluations(user_id, created_at);
@@ -0,0 +1,2 @@
+-- Drop the duplicate listing index
+DROP INDEX IF EXISTS idx_jobs_company_title;
@@ -0,0 +1,3 @@
+-- Postings of the same job on several boards share a title and company; the job detail
+-- endpoint lists them as duplicate sources.
+CREATE INDEX IF NOT EXISTS idx_jobs_company_title ON jobs(LOWER(company), LOWER(title));
@@ -0,0 +1,2 @@
+-- Drop the Slack webhook
+ALTER TABLE users DROP COLUMN IF EXISTS slack_webhook;
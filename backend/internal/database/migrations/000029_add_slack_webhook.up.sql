@@ -0,0 +1,2 @@
+-- Slack incoming webhook for saved searches that deliver to the slack channel
+ALTER TABLE users ADD COLUMN IF NOT EXISTS slack_webhook TEXT;
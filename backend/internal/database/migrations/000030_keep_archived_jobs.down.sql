@@ -0,0 +1,14 @@
+-- Put the archived texts back on their jobs and restore the full-row jobs_archive, empty.
+-- Archived jobs stay in jobs.
+UPDATE jobs j SET description = a.description, company_info = a.company_info
+FROM jobs_archive a
+WHERE a.job_id = j.id;
+
+DROP TABLE IF EXISTS jobs_archive;
+
+CREATE TABLE IF NOT EXISTS jobs_archive (LIKE jobs INCLUDING DEFAULTS);
+ALTER TABLE jobs_archive ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+ALTER TABLE jobs_archive ADD PRIMARY KEY (id);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_archive_archived_at ON jobs_archive(archived_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_archive_job_url ON jobs_archive(job_url);
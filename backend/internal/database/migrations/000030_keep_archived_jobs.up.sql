@@ -0,0 +1,44 @@
+-- Archived jobs stay in jobs, so the matches and notifications that reference them survive;
+-- jobs_archive now only holds the bulky columns taken off the archived rows
+ALTER TABLE jobs_archive RENAME TO jobs_archive_old;
+DROP INDEX IF EXISTS idx_jobs_archive_archived_at;
+DROP INDEX IF EXISTS idx_jobs_archive_job_url;
+
+CREATE TABLE IF NOT EXISTS jobs_archive (
+    job_id UUID PRIMARY KEY REFERENCES jobs(id) ON DELETE CASCADE,
+    description TEXT,
+    company_info JSONB,
+    archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_archive_archived_at ON jobs_archive(archived_at);
+
+-- Bring rows archived under the old scheme back into jobs, their texts in jobs_archive. Their
+-- matches and notifications are gone.
+INSERT INTO jobs (
+    id, title, company, location,
+    location_city, location_region, location_country, workplace_type, latitude, longitude, location_parsed_at,
+    job_url, source, description, job_type, is_remote,
+    min_salary, max_salary, salary_currency, salary_interval, min_salary_normalized, max_salary_normalized, date_posted, posted_at,
+    ai_score, ai_analysis, company_info_updated_at,
+    seniority, required_skills, years_experience, visa_sponsorship, tech_stack, attribute_sources, attributes_extracted_at,
+    status, lifecycle, lifecycle_changed_at, url_checked_at, created_at, updated_at
+)
+SELECT
+    id, title, company, location,
+    location_city, location_region, location_country, workplace_type, latitude, longitude, location_parsed_at,
+    job_url, source, '', job_type, is_remote,
+    min_salary, max_salary, salary_currency, salary_interval, min_salary_normalized, max_salary_normalized, date_posted, posted_at,
+    ai_score, ai_analysis, company_info_updated_at,
+    seniority, required_skills, years_experience, visa_sponsorship, tech_stack, attribute_sources, attributes_extracted_at,
+    status, 'archived', lifecycle_changed_at, url_checked_at, created_at, updated_at
+FROM jobs_archive_old
+ON CONFLICT DO NOTHING;
+
+INSERT INTO jobs_archive (job_id, description, company_info, archived_at)
+SELECT o.id, o.description, o.company_info, o.archived_at
+FROM jobs_archive_old o
+JOIN jobs j ON j.id = o.id
+ON CONFLICT DO NOTHING;
+
+DROP TABLE jobs_archive_old;
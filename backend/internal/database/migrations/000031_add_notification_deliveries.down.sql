@@ -0,0 +1,2 @@
+-- Drop the notification delivery log; the hourly limit counts notifications again
+DROP TABLE IF EXISTS notification_deliveries;
@@ -0,0 +1,14 @@
+-- Instant notifications sent, counted for the per-user hourly limit. Unlike notifications, users
+-- cannot delete these rows. The application prunes a user's rows older than the window.
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_user_id ON notification_deliveries(user_id, created_at);
+
+-- Carry over the current window
+INSERT INTO notification_deliveries (user_id, created_at)
+SELECT user_id, created_at FROM notifications
+WHERE delivery = 'instant' AND created_at >= NOW() - INTERVAL '1 hour';
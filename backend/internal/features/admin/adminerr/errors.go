@@ -0,0 +1,14 @@
+package adminerr
+
+import "errors"
+
+var (
+	ErrUserNotFound          = errors.New("user not found")
+	ErrJobNotFound           = errors.New("job not found")
+	ErrCannotModifySelf      = errors.New("admins cannot disable or demote themselves")
+	ErrInvalidRole           = errors.New("invalid role")
+	ErrEmptyFilter           = errors.New("at least one filter is required")
+	ErrUnknownQueue          = errors.New("unknown queue")
+	ErrPipelineNotConfigured = errors.New("pipeline queues are not configured")
+	ErrNoDeadLetterQueue     = errors.New("queue has no dead-letter queue")
+)
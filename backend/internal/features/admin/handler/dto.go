@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/admin/service"
+	usermodel "github.com/jobping/backend/internal/features/user/model"
+)
+
+type AdminUserResponse struct {
+	ID            uuid.UUID `json:"id"`
+	Username      string    `json:"username"`
+	Email         string    `json:"email,omitempty"`
+	EmailVerified bool      `json:"email_verified"`
+	Role          string    `json:"role"`
+	Disabled      bool      `json:"disabled"`
+	DisabledAt    *string   `json:"disabled_at,omitempty"`
+	CreatedAt     string    `json:"created_at"`
+}
+
+type AdminUsersResponse struct {
+	Users  []AdminUserResponse `json:"users"`
+	Total  int                 `json:"total"`
+	Limit  int                 `json:"limit"`
+	Offset int                 `json:"offset"`
+}
+
+type SetRoleRequest struct {
+	Role string `json:"role"`
+}
+
+type DeleteJobsResponse struct {
+	Deleted               int64 `json:"deleted"`
+	MatchesDeleted        int64 `json:"matches_deleted"`
+	NotificationsDeleted  int64 `json:"notifications_deleted"`
+	PipelineEventsDeleted int64 `json:"pipeline_events_deleted"`
+	DryRun                bool  `json:"dry_run"`
+}
+
+type QueueStatusResponse struct {
+	Name           string `json:"name"`
+	Messages       int    `json:"messages"`
+	InFlight       int    `json:"in_flight"`
+	DeadLetters    int    `json:"dead_letters"`
+	HasDeadLetters bool   `json:"has_dead_letter_queue"`
+}
+
+type QueuesResponse struct {
+	Queues []QueueStatusResponse `json:"queues"`
+}
+
+type RedriveResponse struct {
+	Queue      string `json:"queue"`
+	TaskHandle string `json:"task_handle"`
+}
+
+func ToAdminUserResponse(u usermodel.User) AdminUserResponse {
+	resp := AdminUserResponse{
+		ID:            u.ID,
+		Username:      u.Username,
+		EmailVerified: u.EmailVerifiedAt != nil,
+		Role:          u.Role,
+		Disabled:      u.DisabledAt != nil,
+		CreatedAt:     u.CreatedAt.Format(time.RFC3339),
+	}
+	if u.Email != nil {
+		resp.Email = *u.Email
+	}
+	if u.DisabledAt != nil {
+		disabledAt := u.DisabledAt.Format(time.RFC3339)
+		resp.DisabledAt = &disabledAt
+	}
+	return resp
+}
+
+func ToQueuesResponse(statuses []service.QueueStatus) QueuesResponse {
+	resp := QueuesResponse{Queues: make([]QueueStatusResponse, 0, len(statuses))}
+	for _, s := range statuses {
+		resp.Queues = append(resp.Queues, QueueStatusResponse{
+			Name:           s.Name,
+			Messages:       s.Messages,
+			InFlight:       s.InFlight,
+			DeadLetters:    s.DeadLetters,
+			HasDeadLetters: s.HasDeadLetters,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/clientip"
+	"github.com/jobping/backend/internal/features/admin/adminerr"
+	"github.com/jobping/backend/internal/features/admin/service"
+	jobmodel "github.com/jobping/backend/internal/features/job/model"
+	userhandler "github.com/jobping/backend/internal/features/user/handler"
+	usermodel "github.com/jobping/backend/internal/features/user/model"
+)
+
+type HTTPHandler struct {
+	service *service.AdminService
+}
+
+func NewHTTPHandler(svc *service.AdminService) *HTTPHandler {
+	return &HTTPHandler{service: svc}
+}
+
+// ListUsers pages through users. ?q= matches username or email, ?role= filters by role.
+func (h *HTTPHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	filter := usermodel.UserFilter{
+		Query:  strings.TrimSpace(r.URL.Query().Get("q")),
+		Role:   r.URL.Query().Get("role"),
+		Limit:  parseInt(r, "limit", 50, 1, 200),
+		Offset: parseInt(r, "offset", 0, 0, -1),
+	}
+
+	users, total, err := h.service.ListUsers(r.Context(), filter)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response := AdminUsersResponse{
+		Users:  make([]AdminUserResponse, 0, len(users)),
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}
+	for _, u := range users {
+		response.Users = append(response.Users, ToAdminUserResponse(u))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *HTTPHandler) DisableUser(w http.ResponseWriter, r *http.Request) {
+	h.setDisabled(w, r, true)
+}
+
+func (h *HTTPHandler) EnableUser(w http.ResponseWriter, r *http.Request) {
+	h.setDisabled(w, r, false)
+}
+
+func (h *HTTPHandler) SetUserRole(w http.ResponseWriter, r *http.Request) {
+	actor, ok := actorFromRequest(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	var req SetRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := h.service.SetRole(r.Context(), actor, userID, req.Role)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, ToAdminUserResponse(*user))
+}
+
+func (h *HTTPHandler) DeleteJob(w http.ResponseWriter, r *http.Request) {
+	actor, ok := actorFromRequest(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	jobID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid job id")
+		return
+	}
+
+	if err := h.service.DeleteJob(r.Context(), actor, jobID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteJobs deletes the jobs matching ?status=, ?company=, ?source= and ?older_than=. At least one
+// is required. older_than is a number of days ("30d") or an RFC 3339 timestamp or date.
+// ?dry_run=true returns the counts without deleting anything.
+func (h *HTTPHandler) DeleteJobs(w http.ResponseWriter, r *http.Request) {
+	actor, ok := actorFromRequest(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := jobmodel.JobFilter{
+		Status:  jobmodel.JobStatus(query.Get("status")),
+		Company: strings.TrimSpace(query.Get("company")),
+		Source:  strings.ToLower(strings.TrimSpace(query.Get("source"))),
+	}
+	switch filter.Status {
+	case "", jobmodel.JobStatusPending, jobmodel.JobStatusProcessed, jobmodel.JobStatusFailed:
+	default:
+		writeError(w, http.StatusBadRequest, "invalid status")
+		return
+	}
+	if olderThan := query.Get("older_than"); olderThan != "" {
+		before, err := parseOlderThan(olderThan, time.Now())
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid older_than")
+			return
+		}
+		filter.CreatedBefore = &before
+	}
+	var dryRun bool
+	if value := query.Get("dry_run"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid dry_run")
+			return
+		}
+	}
+
+	counts, err := h.service.DeleteJobs(r.Context(), actor, filter, dryRun)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, DeleteJobsResponse{
+		Deleted:               counts.Jobs,
+		MatchesDeleted:        counts.Matches,
+		NotificationsDeleted:  counts.Notifications,
+		PipelineEventsDeleted: counts.PipelineEvents,
+		DryRun:                dryRun,
+	})
+}
+
+// ReprocessJob queues a job for analysis and matching again
+func (h *HTTPHandler) ReprocessJob(w http.ResponseWriter, r *http.Request) {
+	actor, ok := actorFromRequest(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	jobID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid job id")
+		return
+	}
+
+	if err := h.service.ReprocessJob(r.Context(), actor, jobID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *HTTPHandler) GetQueues(w http.ResponseWriter, r *http.Request) {
+	statuses, err := h.service.QueueStatus(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, ToQueuesResponse(statuses))
+}
+
+// RedriveQueue starts moving a queue's dead letters back onto it. The move runs in SQS
+// after the response; the queue listing shows its progress.
+func (h *HTTPHandler) RedriveQueue(w http.ResponseWriter, r *http.Request) {
+	actor, ok := actorFromRequest(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	name := chi.URLParam(r, "name")
+
+	taskHandle, err := h.service.RedriveQueue(r.Context(), actor, name)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, RedriveResponse{Queue: name, TaskHandle: taskHandle})
+}
+
+func (h *HTTPHandler) setDisabled(w http.ResponseWriter, r *http.Request, disabled bool) {
+	actor, ok := actorFromRequest(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	user, err := h.service.SetDisabled(r.Context(), actor, userID, disabled)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, ToAdminUserResponse(*user))
+}
+
+func actorFromRequest(r *http.Request) (service.Actor, bool) {
+	userID, ok := userhandler.UserIDFromContext(r.Context())
+	if !ok {
+		return service.Actor{}, false
+	}
+	return service.Actor{
+		UserID:    userID,
+		IPAddress: clientip.FromRequest(r),
+		UserAgent: r.UserAgent(),
+	}, true
+}
+
+// parseOlderThan accepts "<days>d", an RFC 3339 timestamp or a YYYY-MM-DD date
+func parseOlderThan(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return time.Time{}, errors.New("invalid day count")
+		}
+		return now.AddDate(0, 0, -n), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+// parseInt reads a query parameter, falling back to def and clamping to [min, max] (max < 0: no upper bound)
+func parseInt(r *http.Request, key string, def, min, max int) int {
+	value := def
+	if s := r.URL.Query().Get(key); s != "" {
+		if parsed, err := strconv.Atoi(s); err == nil {
+			value = parsed
+		}
+	}
+	if value < min {
+		value = min
+	}
+	if max >= 0 && value > max {
+		value = max
+	}
+	return value
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, adminerr.ErrUserNotFound), errors.Is(err, adminerr.ErrJobNotFound), errors.Is(err, adminerr.ErrUnknownQueue):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, adminerr.ErrInvalidRole), errors.Is(err, adminerr.ErrEmptyFilter):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, adminerr.ErrCannotModifySelf), errors.Is(err, adminerr.ErrNoDeadLetterQueue):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, adminerr.ErrPipelineNotConfigured):
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+	default:
+		log.Printf("Admin operation failed: %v", err)
+		writeError(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{"code": status, "message": message})
+}
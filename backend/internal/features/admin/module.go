@@ -0,0 +1,30 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jobping/backend/internal/features/admin/handler"
+)
+
+// RegisterRoutes registers the /admin routes. Every route requires an authenticated admin.
+func RegisterRoutes(r chi.Router, adminHandler *handler.HTTPHandler, authenticate, requireAdmin func(http.Handler) http.Handler) {
+	r.Group(func(r chi.Router) {
+		r.Use(authenticate, requireAdmin)
+
+		// Users
+		r.Get("/admin/users", adminHandler.ListUsers)
+		r.Post("/admin/users/{id}/disable", adminHandler.DisableUser)
+		r.Post("/admin/users/{id}/enable", adminHandler.EnableUser)
+		r.Put("/admin/users/{id}/role", adminHandler.SetUserRole)
+
+		// Jobs
+		r.Delete("/admin/jobs", adminHandler.DeleteJobs)
+		r.Delete("/admin/jobs/{id}", adminHandler.DeleteJob)
+		r.Post("/admin/jobs/{id}/reprocess", adminHandler.ReprocessJob)
+
+		// Pipeline
+		r.Get("/admin/pipeline/queues", adminHandler.GetQueues)
+		r.Post("/admin/pipeline/queues/{name}/redrive", adminHandler.RedriveQueue)
+	})
+}
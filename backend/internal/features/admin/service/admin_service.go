@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jobping/backend/internal/audit"
+	"github.com/jobping/backend/internal/features/admin/adminerr"
+	jobmodel "github.com/jobping/backend/internal/features/job/model"
+	jobrepo "github.com/jobping/backend/internal/features/job/repository"
+	usermodel "github.com/jobping/backend/internal/features/user/model"
+	userrepo "github.com/jobping/backend/internal/features/user/repository"
+	usersvc "github.com/jobping/backend/internal/features/user/service"
+)
+
+// Audit actions for admin operations
+const (
+	ActionUserDisabled    = "admin.user_disabled"
+	ActionUserEnabled     = "admin.user_enabled"
+	ActionUserRoleChanged = "admin.user_role_changed"
+	ActionJobDeleted      = "admin.job_deleted"
+	ActionJobsDeleted     = "admin.jobs_deleted"
+	ActionQueueRedriven   = "admin.queue_redriven"
+	ActionJobReprocessed  = "admin.job_reprocessed"
+)
+
+// Actor is the admin performing an operation, recorded in the audit log
+type Actor struct {
+	UserID    uuid.UUID
+	IPAddress string
+	UserAgent string
+}
+
+// AdminService implements the cross-user operations behind /api/admin. Every change is audited.
+type AdminService struct {
+	userRepo userrepo.UserRepository
+	sessions *usersvc.SessionService
+	jobRepo  jobrepo.JobRepository
+	pipeline Pipeline
+	audit    audit.Recorder
+}
+
+func NewAdminService(
+	userRepo userrepo.UserRepository,
+	sessions *usersvc.SessionService,
+	jobRepo jobrepo.JobRepository,
+	pipeline Pipeline,
+	recorder audit.Recorder,
+) *AdminService {
+	return &AdminService{
+		userRepo: userRepo,
+		sessions: sessions,
+		jobRepo:  jobRepo,
+		pipeline: pipeline,
+		audit:    recorder,
+	}
+}
+
+func (s *AdminService) ListUsers(ctx context.Context, filter usermodel.UserFilter) ([]usermodel.User, int, error) {
+	if filter.Role != "" && !usermodel.ValidRole(filter.Role) {
+		return nil, 0, adminerr.ErrInvalidRole
+	}
+	return s.userRepo.ListUsers(ctx, filter)
+}
+
+// SetDisabled disables or re-enables a user. Disabling also revokes all of their sessions,
+// so they are signed out once their current access token expires.
+func (s *AdminService) SetDisabled(ctx context.Context, actor Actor, userID uuid.UUID, disabled bool) (*usermodel.User, error) {
+	if userID == actor.UserID {
+		return nil, adminerr.ErrCannotModifySelf
+	}
+
+	var disabledAt *time.Time
+	action := ActionUserEnabled
+	if disabled {
+		now := time.Now()
+		disabledAt = &now
+		action = ActionUserDisabled
+	}
+
+	updated, err := s.userRepo.SetDisabled(ctx, userID, disabledAt)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, adminerr.ErrUserNotFound
+	}
+
+	metadata := map[string]interface{}{}
+	if disabled {
+		revoked, err := s.sessions.LogoutAll(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		metadata["sessions_revoked"] = revoked
+	}
+	s.record(ctx, actor, action, "user", userID.String(), metadata)
+
+	return s.getUser(ctx, userID)
+}
+
+// SetRole changes a user's role. It takes effect on their next token refresh.
+func (s *AdminService) SetRole(ctx context.Context, actor Actor, userID uuid.UUID, role string) (*usermodel.User, error) {
+	if !usermodel.ValidRole(role) {
+		return nil, adminerr.ErrInvalidRole
+	}
+	if userID == actor.UserID && role != usermodel.RoleAdmin {
+		return nil, adminerr.ErrCannotModifySelf
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == role {
+		return user, nil
+	}
+
+	updated, err := s.userRepo.UpdateRole(ctx, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, adminerr.ErrUserNotFound
+	}
+	s.record(ctx, actor, ActionUserRoleChanged, "user", userID.String(), map[string]interface{}{
+		"from": user.Role,
+		"to":   role,
+	})
+
+	user.Role = role
+	return user, nil
+}
+
+// DeleteJob deletes a job along with its matches and notifications
+func (s *AdminService) DeleteJob(ctx context.Context, actor Actor, jobID uuid.UUID) error {
+	deleted, err := s.jobRepo.DeleteByID(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return adminerr.ErrJobNotFound
+	}
+	s.record(ctx, actor, ActionJobDeleted, "job", jobID.String(), nil)
+	return nil
+}
+
+// DeleteJobs deletes every job matching filter, which must not be empty, with its matches,
+// notifications and pipeline events. The audit entry is written in the same transaction: if it
+// fails, nothing is deleted. A dry run only counts them and is not audited.
+func (s *AdminService) DeleteJobs(ctx context.Context, actor Actor, filter jobmodel.JobFilter, dryRun bool) (jobmodel.JobDeleteCounts, error) {
+	if filter.IsEmpty() {
+		return jobmodel.JobDeleteCounts{}, adminerr.ErrEmptyFilter
+	}
+	if dryRun {
+		return s.jobRepo.DeleteMatching(ctx, filter, true, nil)
+	}
+
+	return s.jobRepo.DeleteMatching(ctx, filter, false, func(ctx context.Context, tx pgx.Tx, counts jobmodel.JobDeleteCounts) error {
+		metadata := map[string]interface{}{
+			"deleted":                 counts.Jobs,
+			"matches_deleted":         counts.Matches,
+			"notifications_deleted":   counts.Notifications,
+			"pipeline_events_deleted": counts.PipelineEvents,
+		}
+		if filter.Status != "" {
+			metadata["status"] = filter.Status
+		}
+		if filter.Company != "" {
+			metadata["company"] = filter.Company
+		}
+		if filter.Source != "" {
+			metadata["source"] = filter.Source
+		}
+		if filter.CreatedBefore != nil {
+			metadata["created_before"] = filter.CreatedBefore.Format(time.RFC3339)
+		}
+		return audit.Write(ctx, tx, s.entry(actor, ActionJobsDeleted, "job", "", metadata))
+	})
+}
+
+func (s *AdminService) QueueStatus(ctx context.Context) ([]QueueStatus, error) {
+	return s.pipeline.Queues(ctx)
+}
+
+// RedriveQueue moves a queue's dead letters back onto it
+func (s *AdminService) RedriveQueue(ctx context.Context, actor Actor, name string) (string, error) {
+	taskHandle, err := s.pipeline.Redrive(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	s.record(ctx, actor, ActionQueueRedriven, "queue", name, map[string]interface{}{"task_handle": taskHandle})
+	return taskHandle, nil
+}
+
+// ReprocessJob sends an existing job back through analysis and matching
+func (s *AdminService) ReprocessJob(ctx context.Context, actor Actor, jobID uuid.UUID) error {
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return adminerr.ErrJobNotFound
+	}
+
+	if err := s.pipeline.EnqueueJobAnalysis(ctx, jobID); err != nil {
+		return err
+	}
+	s.record(ctx, actor, ActionJobReprocessed, "job", jobID.String(), nil)
+	return nil
+}
+
+func (s *AdminService) getUser(ctx context.Context, userID uuid.UUID) (*usermodel.User, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, adminerr.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// record writes an audit entry. The operation has already happened, so failures are only logged.
+func (s *AdminService) record(ctx context.Context, actor Actor, action, targetType, targetID string, metadata map[string]interface{}) {
+	if err := s.audit.Record(ctx, s.entry(actor, action, targetType, targetID, metadata)); err != nil {
+		log.Printf("Failed to write audit entry %s: %v", action, err)
+	}
+}
+
+// entry builds the audit entry of an admin action
+func (s *AdminService) entry(actor Actor, action, targetType, targetID string, metadata map[string]interface{}) *audit.Entry {
+	return &audit.Entry{
+		ActorID:    &actor.UserID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		IPAddress:  actor.IPAddress,
+		UserAgent:  actor.UserAgent,
+		Metadata:   metadata,
+	}
+}
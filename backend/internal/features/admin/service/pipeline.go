@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/admin/adminerr"
+)
+
+// Pipeline queue names, as used in the admin API
+const (
+	QueueJobAnalysis  = "job_analysis"
+	QueueUserFanout   = "user_fanout"
+	QueueUserAnalysis = "user_analysis"
+	QueueNotification = "notification"
+)
+
+// QueueStatus is the backlog of a pipeline queue and its dead-letter queue
+type QueueStatus struct {
+	Name           string
+	Messages       int
+	InFlight       int
+	DeadLetters    int
+	HasDeadLetters bool
+}
+
+// Pipeline inspects and re-drives the SQS queues between the pipeline workers
+type Pipeline interface {
+	Queues(ctx context.Context) ([]QueueStatus, error)
+	// Redrive moves the queue's dead letters back onto it and returns the move task handle
+	Redrive(ctx context.Context, name string) (string, error)
+	// EnqueueJobAnalysis sends a job through analysis and matching again
+	EnqueueJobAnalysis(ctx context.Context, jobID uuid.UUID) error
+}
+
+type sqsPipeline struct {
+	client *sqs.Client
+	names  []string          // configured queues, in display order
+	queues map[string]string // queue URL by name
+}
+
+// NewSQSPipeline returns a Pipeline over the configured queue URLs, keyed by queue name.
+// Queues with an empty URL are left out; with none configured every call fails with
+// adminerr.ErrPipelineNotConfigured.
+func NewSQSPipeline(queueURLs map[string]string) (Pipeline, error) {
+	p := &sqsPipeline{queues: make(map[string]string)}
+	for _, name := range []string{QueueJobAnalysis, QueueUserFanout, QueueUserAnalysis, QueueNotification} {
+		if url := queueURLs[name]; url != "" {
+			p.names = append(p.names, name)
+			p.queues[name] = url
+		}
+	}
+	if len(p.names) == 0 {
+		return p, nil
+	}
+
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(context.Background(), awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	// Override endpoint for LocalStack
+	if endpointURL := os.Getenv("AWS_ENDPOINT_URL"); endpointURL != "" {
+		cfg.BaseEndpoint = aws.String(endpointURL)
+	}
+	p.client = sqs.NewFromConfig(cfg)
+	return p, nil
+}
+
+func (p *sqsPipeline) Queues(ctx context.Context) ([]QueueStatus, error) {
+	if p.client == nil {
+		return nil, adminerr.ErrPipelineNotConfigured
+	}
+
+	statuses := make([]QueueStatus, 0, len(p.names))
+	for _, name := range p.names {
+		attrs, err := p.attributes(ctx, p.queues[name])
+		if err != nil {
+			return nil, fmt.Errorf("queue %s: %w", name, err)
+		}
+		status := QueueStatus{
+			Name:     name,
+			Messages: atoi(attrs[string(types.QueueAttributeNameApproximateNumberOfMessages)]),
+			InFlight: atoi(attrs[string(types.QueueAttributeNameApproximateNumberOfMessagesNotVisible)]),
+		}
+
+		dlqARN := deadLetterARN(attrs)
+		if dlqARN != "" {
+			dlqURL, err := p.queueURL(ctx, dlqARN)
+			if err != nil {
+				return nil, fmt.Errorf("queue %s: %w", name, err)
+			}
+			dlqAttrs, err := p.attributes(ctx, dlqURL)
+			if err != nil {
+				return nil, fmt.Errorf("queue %s: %w", name, err)
+			}
+			status.HasDeadLetters = true
+			status.DeadLetters = atoi(dlqAttrs[string(types.QueueAttributeNameApproximateNumberOfMessages)])
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (p *sqsPipeline) Redrive(ctx context.Context, name string) (string, error) {
+	if p.client == nil {
+		return "", adminerr.ErrPipelineNotConfigured
+	}
+	url, ok := p.queues[name]
+	if !ok {
+		return "", adminerr.ErrUnknownQueue
+	}
+
+	attrs, err := p.attributes(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	dlqARN := deadLetterARN(attrs)
+	if dlqARN == "" {
+		return "", adminerr.ErrNoDeadLetterQueue
+	}
+
+	// Without a destination SQS returns each message to the queue it was dead-lettered from
+	out, err := p.client.StartMessageMoveTask(ctx, &sqs.StartMessageMoveTaskInput{
+		SourceArn: aws.String(dlqARN),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(out.TaskHandle), nil
+}
+
+func (p *sqsPipeline) EnqueueJobAnalysis(ctx context.Context, jobID uuid.UUID) error {
+	if p.client == nil {
+		return adminerr.ErrPipelineNotConfigured
+	}
+	url, ok := p.queues[QueueJobAnalysis]
+	if !ok {
+		return adminerr.ErrPipelineNotConfigured
+	}
+
+	body, err := json.Marshal(map[string]string{"job_id": jobID.String()})
+	if err != nil {
+		return err
+	}
+	_, err = p.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:    aws.String(url),
+		MessageBody: aws.String(string(body)),
+	})
+	return err
+}
+
+func (p *sqsPipeline) attributes(ctx context.Context, url string) (map[string]string, error) {
+	out, err := p.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl: aws.String(url),
+		AttributeNames: []types.QueueAttributeName{
+			types.QueueAttributeNameApproximateNumberOfMessages,
+			types.QueueAttributeNameApproximateNumberOfMessagesNotVisible,
+			types.QueueAttributeNameRedrivePolicy,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Attributes, nil
+}
+
+// queueURL resolves a queue ARN (arn:aws:sqs:region:account:name) to its URL
+func (p *sqsPipeline) queueURL(ctx context.Context, arn string) (string, error) {
+	parts := strings.Split(arn, ":")
+	if len(parts) != 6 {
+		return "", fmt.Errorf("invalid queue ARN %q", arn)
+	}
+	out, err := p.client.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{
+		QueueName:              aws.String(parts[5]),
+		QueueOwnerAWSAccountId: aws.String(parts[4]),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(out.QueueUrl), nil
+}
+
+// deadLetterARN reads the DLQ ARN from a queue's RedrivePolicy attribute
+func deadLetterARN(attrs map[string]string) string {
+	policy := attrs[string(types.QueueAttributeNameRedrivePolicy)]
+	if policy == "" {
+		return ""
+	}
+	var parsed struct {
+		DeadLetterTargetArn string `json:"deadLetterTargetArn"`
+	}
+	if err := json.Unmarshal([]byte(policy), &parsed); err != nil {
+		return ""
+	}
+	return parsed.DeadLetterTargetArn
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jobping/backend/internal/features/event/repository"
+	"github.com/jobping/backend/internal/features/event/service"
+	userhandler "github.com/jobping/backend/internal/features/user/handler"
+)
+
+// heartbeatInterval also paces catch-up reads for events that committed late or were dropped
+const heartbeatInterval = 25 * time.Second
+
+type SSEHandler struct {
+	broker *service.Broker
+}
+
+func NewSSEHandler(broker *service.Broker) *SSEHandler {
+	return &SSEHandler{broker: broker}
+}
+
+// Stream sends the caller's new matches and notifications as Server-Sent Events.
+// Clients resume with the Last-Event-ID header (sent automatically by EventSource on reconnect)
+// or a last_event_id query parameter; missed events are replayed before live ones. Events from
+// the last few seconds may be sent again after a reconnect, so clients should dedupe by id.
+func (h *SSEHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userhandler.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	lastID, err := lastEventID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid Last-Event-ID")
+		return
+	}
+
+	// Subscribe before replaying so nothing created in between is missed
+	events, unsubscribe := h.broker.Subscribe(userID)
+	defer unsubscribe()
+
+	cursor, err := h.broker.NewCursor(r.Context(), userID, lastID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load events")
+		return
+	}
+	backlog, err := h.broker.CatchUp(r.Context(), cursor)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load events")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 3000\n\n")
+
+	if err := writeEvents(w, cursor, backlog); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			missed, err := h.broker.CatchUp(r.Context(), cursor)
+			if err != nil {
+				log.Printf("Failed to catch up events for user %s: %v", userID, err)
+			}
+			if err := writeEvents(w, cursor, missed); err != nil {
+				return
+			}
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event := <-events:
+			if !cursor.Pending(event) {
+				continue // already sent during a catch-up
+			}
+			if err := writeEvents(w, cursor, []repository.Event{event}); err != nil {
+				log.Printf("Failed to write event to user %s: %v", userID, err)
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func lastEventID(r *http.Request) (int64, error) {
+	raw := r.Header.Get("Last-Event-ID")
+	if raw == "" {
+		raw = r.URL.Query().Get("last_event_id")
+	}
+	if raw == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(raw, 10, 64)
+}
+
+func writeEvents(w http.ResponseWriter, cursor *service.Cursor, events []repository.Event) error {
+	for _, event := range events {
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Payload); err != nil {
+			return err
+		}
+		cursor.MarkSent(event)
+	}
+	return nil
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{"code": status, "message": message})
+}
@@ -0,0 +1,13 @@
+package event
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jobping/backend/internal/features/event/handler"
+)
+
+// RegisterRoutes registers the per-user event stream. API tokens need the read:matches scope.
+func RegisterRoutes(r chi.Router, sseHandler *handler.SSEHandler, authenticate, requireMatches func(http.Handler) http.Handler) {
+	r.With(authenticate, requireMatches).Get("/users/me/events", sseHandler.Stream)
+}
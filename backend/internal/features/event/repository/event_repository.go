@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Channel is the Postgres NOTIFY channel carrying {"id": ..., "user_id": ...} for each new event
+const Channel = "user_events"
+
+// Event types
+const (
+	TypeMatchCreated        = "match.created"
+	TypeNotificationCreated = "notification.created"
+)
+
+// Event is a single entry in a user's event stream
+type Event struct {
+	ID        int64
+	UserID    uuid.UUID
+	Type      string
+	Payload   json.RawMessage
+	CreatedAt time.Time
+}
+
+// Execer is satisfied by *pgxpool.Pool and pgx.Tx, so events can be published inside the writer's transaction
+type Execer interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+}
+
+// Publish appends an event to the user's log and notifies listeners.
+// When db is a transaction the NOTIFY is only delivered if it commits.
+func Publish(ctx context.Context, db Execer, userID uuid.UUID, eventType string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		WITH e AS (
+			INSERT INTO user_events (user_id, type, payload)
+			VALUES ($1, $2, $3)
+			RETURNING id, user_id
+		)
+		SELECT pg_notify('` + Channel + `', json_build_object('id', e.id, 'user_id', e.user_id)::text) FROM e
+	`
+	_, err = db.Exec(ctx, query, userID, eventType, body)
+	return err
+}
+
+type EventRepository interface {
+	GetByID(ctx context.Context, id int64) (*Event, error)
+	GetSince(ctx context.Context, userID uuid.UUID, afterID int64, limit int) ([]Event, error)
+	GetCreatedSince(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]Event, error)
+	GetLatestID(ctx context.Context, userID uuid.UUID) (int64, error)
+	DeleteOlderThan(ctx context.Context, before time.Time) (int64, error)
+}
+
+type postgresEventRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewEventRepository(db *pgxpool.Pool) EventRepository {
+	return &postgresEventRepository{db: db}
+}
+
+func (r *postgresEventRepository) GetByID(ctx context.Context, id int64) (*Event, error) {
+	query := `SELECT id, user_id, type, payload, created_at FROM user_events WHERE id = $1`
+	var e Event
+	err := r.db.QueryRow(ctx, query, id).Scan(&e.ID, &e.UserID, &e.Type, &e.Payload, &e.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *postgresEventRepository) GetSince(ctx context.Context, userID uuid.UUID, afterID int64, limit int) ([]Event, error) {
+	query := `
+		SELECT id, user_id, type, payload, created_at
+		FROM user_events
+		WHERE user_id = $1 AND id > $2
+		ORDER BY id
+		LIMIT $3
+	`
+	return r.queryEvents(ctx, query, userID, afterID, limit)
+}
+
+// GetCreatedSince returns the user's events created at or after since, whatever their id, ordered by id
+func (r *postgresEventRepository) GetCreatedSince(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]Event, error) {
+	query := `
+		SELECT id, user_id, type, payload, created_at
+		FROM user_events
+		WHERE user_id = $1 AND created_at >= $2
+		ORDER BY id
+		LIMIT $3
+	`
+	return r.queryEvents(ctx, query, userID, since, limit)
+}
+
+// GetLatestID returns the id of the user's newest event, or 0 if they have none
+func (r *postgresEventRepository) GetLatestID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var id int64
+	err := r.db.QueryRow(ctx, `SELECT COALESCE(MAX(id), 0) FROM user_events WHERE user_id = $1`, userID).Scan(&id)
+	return id, err
+}
+
+func (r *postgresEventRepository) queryEvents(ctx context.Context, query string, args ...interface{}) ([]Event, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var e Event
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Type, &e.Payload, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func (r *postgresEventRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM user_events WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
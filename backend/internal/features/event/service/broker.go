@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jobping/backend/internal/features/event/repository"
+)
+
+const (
+	// subscriberBuffer is how many events a slow client may lag before events are dropped for it;
+	// the client recovers them on reconnect via Last-Event-ID
+	subscriberBuffer = 32
+	retention        = 7 * 24 * time.Hour
+	pruneInterval    = time.Hour
+	reconnectDelay   = 5 * time.Second
+
+	// replayPageSize is how many events one catch-up query reads
+	replayPageSize = 500
+	// settleWindow is how long after creation an event is re-read. Ids are assigned at insert
+	// but become visible at commit, so a lower id can appear after a higher one has been sent.
+	settleWindow = 30 * time.Second
+)
+
+// Broker listens on the Postgres user_events channel and fans events out to per-user subscribers
+type Broker struct {
+	db        *pgxpool.Pool
+	eventRepo repository.EventRepository
+
+	mu   sync.Mutex
+	subs map[uuid.UUID]map[chan repository.Event]struct{}
+}
+
+func NewBroker(db *pgxpool.Pool, eventRepo repository.EventRepository) *Broker {
+	return &Broker{
+		db:        db,
+		eventRepo: eventRepo,
+		subs:      make(map[uuid.UUID]map[chan repository.Event]struct{}),
+	}
+}
+
+// Subscribe registers for a user's live events. The returned func must be called to unsubscribe.
+func (b *Broker) Subscribe(userID uuid.UUID) (<-chan repository.Event, func()) {
+	ch := make(chan repository.Event, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subs[userID] == nil {
+		b.subs[userID] = make(map[chan repository.Event]struct{})
+	}
+	b.subs[userID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		delete(b.subs[userID], ch)
+		if len(b.subs[userID]) == 0 {
+			delete(b.subs, userID)
+		}
+		b.mu.Unlock()
+	}
+}
+
+// Cursor tracks what one stream has sent. Ids are not a strict watermark: besides the highest
+// id sent it remembers recently sent ids, so events that commit late are still sent once.
+type Cursor struct {
+	userID uuid.UUID
+	lastID int64
+	floor  time.Time
+	sent   map[int64]time.Time
+}
+
+// NewCursor starts a stream after lastID, the client's Last-Event-ID. A new client (lastID 0)
+// starts at the user's newest event and only gets events created after it connected.
+func (b *Broker) NewCursor(ctx context.Context, userID uuid.UUID, lastID int64) (*Cursor, error) {
+	c := &Cursor{userID: userID, lastID: lastID, sent: make(map[int64]time.Time)}
+	if lastID == 0 {
+		latest, err := b.eventRepo.GetLatestID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		c.lastID = latest
+		c.floor = time.Now()
+	}
+	return c, nil
+}
+
+// Pending reports whether event has not been sent on the cursor's stream yet
+func (c *Cursor) Pending(event repository.Event) bool {
+	_, sent := c.sent[event.ID]
+	return !sent && (event.ID > c.lastID || event.CreatedAt.After(time.Now().Add(-settleWindow)))
+}
+
+// MarkSent records that event was written to the stream
+func (c *Cursor) MarkSent(event repository.Event) {
+	c.sent[event.ID] = event.CreatedAt
+	if event.ID > c.lastID {
+		c.lastID = event.ID
+	}
+}
+
+// CatchUp returns the events the cursor has not sent: every page after its highest id, plus
+// events from the settle window that committed behind it. Streams call it after subscribing
+// and again periodically, which also recovers events dropped for slow subscribers.
+func (b *Broker) CatchUp(ctx context.Context, c *Cursor) ([]repository.Event, error) {
+	now := time.Now()
+	for id, createdAt := range c.sent {
+		if now.Sub(createdAt) > 2*settleWindow {
+			delete(c.sent, id)
+		}
+	}
+
+	since := now.Add(-settleWindow)
+	if since.Before(c.floor) {
+		since = c.floor
+	}
+	recent, err := b.eventRepo.GetCreatedSince(ctx, c.userID, since, replayPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []repository.Event
+	seen := make(map[int64]bool)
+	for _, event := range recent {
+		if _, sent := c.sent[event.ID]; !sent && event.ID <= c.lastID {
+			pending = append(pending, event)
+			seen[event.ID] = true
+		}
+	}
+
+	afterID := c.lastID
+	for {
+		page, err := b.eventRepo.GetSince(ctx, c.userID, afterID, replayPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range page {
+			if _, sent := c.sent[event.ID]; !sent && !seen[event.ID] {
+				pending = append(pending, event)
+			}
+		}
+		if len(page) < replayPageSize {
+			return pending, nil
+		}
+		afterID = page[len(page)-1].ID
+	}
+}
+
+// Run listens for notifications until ctx is cancelled, reconnecting on connection errors
+func (b *Broker) Run(ctx context.Context) {
+	go b.prune(ctx)
+
+	for {
+		if err := b.listen(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Event listener error, reconnecting in %s: %v", reconnectDelay, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (b *Broker) listen(ctx context.Context) error {
+	conn, err := b.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+repository.Channel); err != nil {
+		return err
+	}
+	log.Printf("Listening for user events on channel %s", repository.Channel)
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var ref struct {
+			ID     int64     `json:"id"`
+			UserID uuid.UUID `json:"user_id"`
+		}
+		if err := json.Unmarshal([]byte(notification.Payload), &ref); err != nil {
+			log.Printf("Invalid user event notification: %v", err)
+			continue
+		}
+
+		if !b.hasSubscribers(ref.UserID) {
+			continue
+		}
+
+		event, err := b.eventRepo.GetByID(ctx, ref.ID)
+		if err != nil {
+			log.Printf("Failed to load user event %d: %v", ref.ID, err)
+			continue
+		}
+		if event != nil {
+			b.dispatch(*event)
+		}
+	}
+}
+
+func (b *Broker) hasSubscribers(userID uuid.UUID) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs[userID]) > 0
+}
+
+func (b *Broker) dispatch(event repository.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subs[event.UserID] {
+		select {
+		case ch <- event:
+		default:
+			log.Printf("Dropping event %d for slow subscriber of user %s", event.ID, event.UserID)
+		}
+	}
+}
+
+// prune deletes events past the retention window so the replay log stays small
+func (b *Broker) prune(ctx context.Context) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := b.eventRepo.DeleteOlderThan(ctx, time.Now().Add(-retention))
+			if err != nil {
+				log.Printf("Failed to prune user events: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Printf("Pruned %d user events", deleted)
+			}
+		}
+	}
+}
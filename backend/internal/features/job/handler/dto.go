@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/jobping/backend/internal/features/job/model"
+	"github.com/jobping/backend/internal/features/job/service"
+	usermodel "github.com/jobping/backend/internal/features/user/model"
 )
 
 type JobResponse struct {
@@ -11,13 +15,35 @@ type JobResponse struct {
 	Company    string    `json:"company"`
 	Location   string    `json:"location"`
 	JobURL     string    `json:"job_url"`
+	Source     string    `json:"source,omitempty"`
 	JobType    string    `json:"job_type"`
 	IsRemote   bool      `json:"is_remote"`
 	MinSalary  *float64  `json:"min_salary,omitempty"`
 	MaxSalary  *float64  `json:"max_salary,omitempty"`
 	DatePosted string    `json:"date_posted,omitempty"`
+	PostedAt   string    `json:"posted_at"`
 	AIScore    *int      `json:"ai_score,omitempty"`
 	AIAnalysis *string   `json:"ai_analysis,omitempty"`
+
+	SalaryCurrency      string   `json:"salary_currency,omitempty"`
+	SalaryInterval      string   `json:"salary_interval,omitempty"`
+	MinSalaryNormalized *float64 `json:"min_salary_normalized,omitempty"`
+	MaxSalaryNormalized *float64 `json:"max_salary_normalized,omitempty"`
+
+	Seniority       string   `json:"seniority,omitempty"`
+	RequiredSkills  []string `json:"required_skills"`
+	YearsExperience *int     `json:"years_experience,omitempty"`
+	VisaSponsorship *bool    `json:"visa_sponsorship,omitempty"`
+	TechStack       []string `json:"tech_stack"`
+
+	LocationCity    string   `json:"location_city,omitempty"`
+	LocationRegion  string   `json:"location_region,omitempty"`
+	LocationCountry string   `json:"location_country,omitempty"`
+	Workplace       string   `json:"workplace,omitempty"`
+	Latitude        *float64 `json:"latitude,omitempty"`
+	Longitude       *float64 `json:"longitude,omitempty"`
+
+	Lifecycle string `json:"lifecycle"`
 }
 
 type JobsResponse struct {
@@ -25,20 +51,44 @@ type JobsResponse struct {
 }
 
 func ToJobResponse(job model.Job) JobResponse {
-	return JobResponse{
+	response := JobResponse{
 		ID:         job.ID,
 		Title:      job.Title,
 		Company:    job.Company,
 		Location:   job.Location,
 		JobURL:     job.JobURL,
+		Source:     job.Source,
 		JobType:    job.JobType,
 		IsRemote:   job.IsRemote,
 		MinSalary:  job.MinSalary,
 		MaxSalary:  job.MaxSalary,
 		DatePosted: job.DatePosted,
+		PostedAt:   job.PostedAt.Format(time.RFC3339),
 		AIScore:    job.AIScore,
 		AIAnalysis: job.AIAnalysis,
+
+		SalaryCurrency:      job.SalaryCurrency,
+		SalaryInterval:      job.SalaryInterval,
+		MinSalaryNormalized: job.MinSalaryNormalized,
+		MaxSalaryNormalized: job.MaxSalaryNormalized,
+
+		Seniority:       job.Attributes.Seniority,
+		RequiredSkills:  model.NonNil(job.Attributes.RequiredSkills),
+		YearsExperience: job.Attributes.YearsExperience,
+		VisaSponsorship: job.Attributes.VisaSponsorship,
+		TechStack:       model.NonNil(job.Attributes.TechStack),
+
+		LocationCity:    job.Place.City,
+		LocationRegion:  job.Place.Region,
+		LocationCountry: job.Place.Country,
+		Workplace:       job.Place.Workplace,
+
+		Lifecycle: string(job.Lifecycle),
 	}
+	if c := job.Place.Coords; c != nil {
+		response.Latitude, response.Longitude = &c.Latitude, &c.Longitude
+	}
+	return response
 }
 
 func ToJobsResponse(jobs []model.Job) JobsResponse {
@@ -49,5 +99,129 @@ func ToJobsResponse(jobs []model.Job) JobsResponse {
 	return response
 }
 
+// JobDetailResponse is a job with everything known about it. Match is set only for signed-in
+// callers who matched the job.
+type JobDetailResponse struct {
+	JobResponse
+	Description          string                 `json:"description"`
+	CompanyInfo          map[string]interface{} `json:"company_info"`
+	CompanyInfoUpdatedAt string                 `json:"company_info_updated_at,omitempty"`
+	Duplicates           []JobDuplicateResponse `json:"duplicates"`
+	Match                *JobMatchResponse      `json:"match,omitempty"`
+}
+
+// JobDuplicateResponse is another posting of the job
+type JobDuplicateResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Source    string    `json:"source,omitempty"`
+	JobURL    string    `json:"job_url"`
+	Lifecycle string    `json:"lifecycle"`
+	CreatedAt string    `json:"created_at"`
+}
+
+// JobMatchResponse is the caller's best match for the job across their saved searches
+type JobMatchResponse struct {
+	ID            uuid.UUID `json:"id"`
+	SavedSearchID uuid.UUID `json:"saved_search_id"`
+	Score         int       `json:"score"`
+	Explanation   string    `json:"explanation"`
+	Pros          []string  `json:"pros"`
+	Cons          []string  `json:"cons"`
+	Notified      bool      `json:"notified"`
+	MatchedAt     string    `json:"matched_at"`
+}
+
+func ToJobDetailResponse(detail *service.JobDetail) JobDetailResponse {
+	job := detail.Job
+	response := JobDetailResponse{
+		JobResponse: ToJobResponse(*job),
+		Description: job.Description,
+		CompanyInfo: job.CompanyInfo,
+		Duplicates:  make([]JobDuplicateResponse, len(detail.Duplicates)),
+	}
+	if job.CompanyInfoUpdatedAt != nil {
+		response.CompanyInfoUpdatedAt = job.CompanyInfoUpdatedAt.Format(time.RFC3339)
+	}
+	for i, d := range detail.Duplicates {
+		response.Duplicates[i] = JobDuplicateResponse{
+			ID:        d.ID,
+			Source:    d.Source,
+			JobURL:    d.JobURL,
+			Lifecycle: string(d.Lifecycle),
+			CreatedAt: d.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	if detail.Match != nil {
+		response.Match = toJobMatchResponse(detail.Match)
+	}
+	return response
+}
+
+func toJobMatchResponse(match *usermodel.UserJobMatch) *JobMatchResponse {
+	explanation, _ := match.Analysis["explanation"].(string)
+	return &JobMatchResponse{
+		ID:            match.ID,
+		SavedSearchID: match.SavedSearchID,
+		Score:         match.Score,
+		Explanation:   explanation,
+		Pros:          stringSlice(match.Analysis["pros"]),
+		Cons:          stringSlice(match.Analysis["cons"]),
+		Notified:      match.Notified,
+		MatchedAt:     match.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// PipelineEventResponse is one stage of a job's timeline
+type PipelineEventResponse struct {
+	Stage         string                 `json:"stage"`
+	Outcome       string                 `json:"outcome"`
+	UserID        *uuid.UUID             `json:"user_id,omitempty"`
+	SavedSearchID *uuid.UUID             `json:"saved_search_id,omitempty"`
+	Details       map[string]interface{} `json:"details"`
+	Error         string                 `json:"error,omitempty"`
+	CreatedAt     string                 `json:"created_at"`
+}
 
+type TimelineResponse struct {
+	JobID     uuid.UUID               `json:"job_id"`
+	Title     string                  `json:"title"`
+	Company   string                  `json:"company"`
+	Status    string                  `json:"status"`
+	Lifecycle string                  `json:"lifecycle"`
+	Events    []PipelineEventResponse `json:"events"`
+}
 
+func ToTimelineResponse(job model.Job, events []model.PipelineEvent) TimelineResponse {
+	response := TimelineResponse{
+		JobID:     job.ID,
+		Title:     job.Title,
+		Company:   job.Company,
+		Status:    string(job.Status),
+		Lifecycle: string(job.Lifecycle),
+		Events:    make([]PipelineEventResponse, len(events)),
+	}
+	for i, event := range events {
+		response.Events[i] = PipelineEventResponse{
+			Stage:         event.Stage,
+			Outcome:       event.Outcome,
+			UserID:        event.UserID,
+			SavedSearchID: event.SavedSearchID,
+			Details:       event.Details,
+			Error:         event.Error,
+			CreatedAt:     event.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	return response
+}
+
+// stringSlice converts a JSONB array (decoded as []interface{}) to strings
+func stringSlice(v interface{}) []string {
+	items, _ := v.([]interface{})
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
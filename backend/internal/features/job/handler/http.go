@@ -3,14 +3,27 @@ package handler
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log"
+	"math"
 	"net/http"
+	"net/url"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/job/joberr"
+	"github.com/jobping/backend/internal/features/job/model"
 	"github.com/jobping/backend/internal/features/job/service"
+	userhandler "github.com/jobping/backend/internal/features/user/handler"
+	usermodel "github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/geo"
 )
 
 type JobHandler struct {
@@ -29,7 +42,10 @@ func NewJobHandler(svc *service.JobService) *JobHandler {
 	}
 }
 
-// GetJobs returns all processed jobs with AI analysis
+// GetJobs returns processed jobs with AI analysis. seniority, skills, tech, max_years_experience
+// and visa_sponsorship narrow the list by the attributes extracted during job analysis; min_salary
+// by the normalized yearly salary; posted_within_days by posting date. sort=salary lists the best
+// paid first, sort=posted the newest.
 func (h *JobHandler) GetJobs(w http.ResponseWriter, r *http.Request) {
 	limit := 20
 	if l := r.URL.Query().Get("limit"); l != "" {
@@ -38,7 +54,13 @@ func (h *JobHandler) GetJobs(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	jobs, err := h.service.GetJobs(r.Context(), limit)
+	filter, err := parseJobListFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	jobs, err := h.service.GetJobs(r.Context(), filter, limit)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "failed to fetch jobs")
 		return
@@ -47,6 +69,172 @@ func (h *JobHandler) GetJobs(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, ToJobsResponse(jobs))
 }
 
+// parseJobListFilter reads the attribute filters of GetJobs. skills and tech are comma-separated.
+func parseJobListFilter(r *http.Request) (model.JobListFilter, error) {
+	q := r.URL.Query()
+	filter := model.JobListFilter{
+		Seniority: strings.ToLower(strings.TrimSpace(q.Get("seniority"))),
+		Skills:    splitTerms(q.Get("skills")),
+		TechStack: splitTerms(q.Get("tech")),
+	}
+	if filter.Seniority != "" && !model.ValidSeniority(filter.Seniority) {
+		return filter, fmt.Errorf("%w: unknown seniority %q", joberr.ErrInvalidJobFilter, filter.Seniority)
+	}
+	if v := q.Get("max_years_experience"); v != "" {
+		years, err := strconv.Atoi(v)
+		if err != nil || years < 0 {
+			return filter, fmt.Errorf("%w: max_years_experience must be a non-negative number", joberr.ErrInvalidJobFilter)
+		}
+		filter.MaxYearsExperience = &years
+	}
+	if v := q.Get("visa_sponsorship"); v != "" {
+		sponsors, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, fmt.Errorf("%w: visa_sponsorship must be true or false", joberr.ErrInvalidJobFilter)
+		}
+		filter.VisaSponsorship = &sponsors
+	}
+	if v := q.Get("min_salary"); v != "" {
+		amount, err := strconv.ParseFloat(v, 64)
+		if err != nil || amount < 0 {
+			return filter, fmt.Errorf("%w: min_salary must be a non-negative number", joberr.ErrInvalidJobFilter)
+		}
+		filter.MinSalary = &amount
+	}
+	if v := q.Get("posted_within_days"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days <= 0 {
+			return filter, fmt.Errorf("%w: posted_within_days must be a positive number", joberr.ErrInvalidJobFilter)
+		}
+		after := time.Now().AddDate(0, 0, -days)
+		filter.PostedAfter = &after
+	}
+	switch filter.Sort = q.Get("sort"); filter.Sort {
+	case "", model.JobSortScore, model.JobSortSalary, model.JobSortPosted:
+	default:
+		return filter, fmt.Errorf("%w: sort must be %s, %s or %s", joberr.ErrInvalidJobFilter,
+			model.JobSortScore, model.JobSortSalary, model.JobSortPosted)
+	}
+	if filter.Workplace = strings.ToLower(q.Get("workplace")); filter.Workplace != "" && !geo.ValidWorkplace(filter.Workplace) {
+		return filter, fmt.Errorf("%w: workplace must be %s, %s or %s", joberr.ErrInvalidJobFilter,
+			geo.WorkplaceRemote, geo.WorkplaceHybrid, geo.WorkplaceOnsite)
+	}
+	if err := parseNearFilter(q, &filter); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+const (
+	defaultRadiusKm = 50
+	maxRadiusKm     = 500
+)
+
+// parseNearFilter reads the radius search: near is a place name such as "Austin, TX", or lat and
+// lon give the point directly. radius_km defaults to 50; include_remote also keeps remote jobs.
+func parseNearFilter(q url.Values, filter *model.JobListFilter) error {
+	switch near, lat, lon := strings.TrimSpace(q.Get("near")), q.Get("lat"), q.Get("lon"); {
+	case near != "":
+		point, ok := geo.Geocode(near)
+		if !ok {
+			return fmt.Errorf("%w: unknown location %q", joberr.ErrInvalidJobFilter, near)
+		}
+		filter.Near = &point
+	case lat != "" || lon != "":
+		latitude, latErr := strconv.ParseFloat(lat, 64)
+		longitude, lonErr := strconv.ParseFloat(lon, 64)
+		if latErr != nil || lonErr != nil || math.Abs(latitude) > 90 || math.Abs(longitude) > 180 {
+			return fmt.Errorf("%w: lat and lon must be valid coordinates", joberr.ErrInvalidJobFilter)
+		}
+		filter.Near = &geo.Point{Latitude: latitude, Longitude: longitude}
+	default:
+		return nil
+	}
+
+	filter.RadiusKm = defaultRadiusKm
+	if v := q.Get("radius_km"); v != "" {
+		radius, err := strconv.ParseFloat(v, 64)
+		if err != nil || radius <= 0 || radius > maxRadiusKm {
+			return fmt.Errorf("%w: radius_km must be between 0 and %d", joberr.ErrInvalidJobFilter, maxRadiusKm)
+		}
+		filter.RadiusKm = radius
+	}
+	if v := q.Get("include_remote"); v != "" {
+		include, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%w: include_remote must be true or false", joberr.ErrInvalidJobFilter)
+		}
+		filter.IncludeRemote = include
+	}
+	return nil
+}
+
+// splitTerms splits a comma-separated list into lower-case terms, matching how attributes are stored
+func splitTerms(v string) []string {
+	var terms []string
+	for _, term := range strings.Split(v, ",") {
+		if term = strings.ToLower(strings.TrimSpace(term)); term != "" {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// GetTimeline returns the pipeline stages a job went through, from ingestion to each user's
+// notification, for answering "why wasn't I notified about this job"
+func (h *JobHandler) GetTimeline(w http.ResponseWriter, r *http.Request) {
+	jobID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid job id")
+		return
+	}
+
+	job, events, err := h.service.GetTimeline(r.Context(), jobID)
+	if errors.Is(err, joberr.ErrJobNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to fetch job timeline")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ToTimelineResponse(*job, events))
+}
+
+// GetJob returns a job with its description, company research and other postings. Signed-in
+// callers also get their match; API tokens need the read:matches scope for it.
+func (h *JobHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	jobID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid job id")
+		return
+	}
+
+	var userID *uuid.UUID
+	if id, ok := userhandler.UserIDFromContext(r.Context()); ok && canReadMatches(r) {
+		userID = &id
+	}
+
+	detail, err := h.service.GetDetail(r.Context(), jobID, userID)
+	if errors.Is(err, joberr.ErrJobNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to fetch job")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ToJobDetailResponse(detail))
+}
+
+// canReadMatches reports whether the caller may see matches: sessions always, API tokens by scope
+func canReadMatches(r *http.Request) bool {
+	scopes, isToken := userhandler.ScopesFromContext(r.Context())
+	return !isToken || slices.Contains(scopes, usermodel.ScopeReadMatches)
+}
+
 // ProcessJob accepts a job via HTTP and runs AI analysis (for local pipeline testing)
 func (h *JobHandler) ProcessJob(w http.ResponseWriter, r *http.Request) {
 	var input service.JobInput
@@ -113,19 +301,6 @@ func (h *JobHandler) FetchJobs(w http.ResponseWriter, r *http.Request) {
 	w.Write(respBody)
 }
 
-// DeleteAllJobs removes all jobs from the database (for testing)
-func (h *JobHandler) DeleteAllJobs(w http.ResponseWriter, r *http.Request) {
-	if err := h.service.DeleteAllJobs(r.Context()); err != nil {
-		log.Printf("Failed to delete jobs: %v", err)
-		writeError(w, http.StatusInternalServerError, "failed to delete jobs: "+err.Error())
-		return
-	}
-
-	writeJSON(w, http.StatusOK, map[string]string{
-		"message": "All jobs deleted successfully",
-	})
-}
-
 func writeJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
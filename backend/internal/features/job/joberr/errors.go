@@ -7,5 +7,5 @@ var (
 	ErrAIAnalysisFailed = errors.New("AI analysis failed")
 )
 
-
-
+// ErrInvalidJobFilter is wrapped by the errors for malformed job listing filters
+var ErrInvalidJobFilter = errors.New("invalid job filter")
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/geo"
 )
 
 type Job struct {
@@ -11,22 +12,40 @@ type Job struct {
 	Title                string
 	Company              string
 	Location             string
+	Place                geo.Location // Location parsed and geocoded; see ParsePlace
+	LocationParsedAt     *time.Time
 	JobURL               string
+	Source               string // Job board it was scraped from, e.g. "indeed"; JobSourceUser if a user submitted it; empty if unknown
 	Description          string
 	JobType              string
 	IsRemote             bool
 	MinSalary            *float64
 	MaxSalary            *float64
+	SalaryCurrency       string
+	SalaryInterval       string
+	MinSalaryNormalized  *float64 // Yearly, in the base currency; set by job analysis
+	MaxSalaryNormalized  *float64
 	DatePosted           string
+	PostedAt             time.Time // Parsed from DatePosted; the ingestion time when it says nothing
 	AIScore              *int
 	AIAnalysis           *string
 	CompanyInfo          map[string]interface{}
 	CompanyInfoUpdatedAt *time.Time
+	Attributes           JobAttributes
 	Status               JobStatus
+	Lifecycle            JobLifecycle
+	LifecycleChangedAt   *time.Time
 	CreatedAt            time.Time
 	UpdatedAt            time.Time
 }
 
+// JobSourceUser is the Source of jobs a user submitted for evaluation. Pasted jobs without a URL
+// get a JobURL with PastedURLPrefix, so they can be de-duplicated like scraped ones.
+const (
+	JobSourceUser   = "user"
+	PastedURLPrefix = "urn:jobping:pasted:"
+)
+
 type JobStatus string
 
 const (
@@ -35,4 +54,160 @@ const (
 	JobStatusFailed    JobStatus = "failed"
 )
 
+// JobLifecycle is where a posting is in its life, independent of its analysis Status.
+// Only active jobs are fanned out to saved searches and listed.
+type JobLifecycle string
+
+const (
+	JobLifecycleActive  JobLifecycle = "active"
+	JobLifecycleExpired JobLifecycle = "expired" // Posted longer ago than the expiry age
+	JobLifecycleClosed  JobLifecycle = "closed"  // The posting URL no longer resolves
+	// Archived jobs keep their row, but their description and company info moved to jobs_archive
+	JobLifecycleArchived JobLifecycle = "archived"
+)
+
+// ParsePlace parses Location. The scraper's remote flag wins over a location that does not say
+// "remote", except for hybrid jobs.
+func (j Job) ParsePlace() geo.Location {
+	place := geo.Parse(j.Location)
+	if j.IsRemote && place.Workplace != geo.WorkplaceHybrid {
+		place.Workplace = geo.WorkplaceRemote
+	}
+	return place
+}
+
+// JobAttributes are the structured facts extracted from a job's description during job analysis.
+// Skills and stack entries are lower-case so they can be filtered on exactly.
+type JobAttributes struct {
+	Seniority       string
+	RequiredSkills  []string
+	YearsExperience *int // Minimum years of experience asked for
+	VisaSponsorship *bool
+	TechStack       []string
+	// Sources maps each extracted attribute to the extractor that supplied it
+	Sources     map[string]string
+	ExtractedAt *time.Time
+}
+
+const (
+	SeniorityIntern    = "intern"
+	SeniorityJunior    = "junior"
+	SeniorityMid       = "mid"
+	SenioritySenior    = "senior"
+	SeniorityLead      = "lead"
+	SeniorityPrincipal = "principal"
+)
+
+// ValidSeniority reports whether s is one of the seniority levels above
+func ValidSeniority(s string) bool {
+	switch s {
+	case SeniorityIntern, SeniorityJunior, SeniorityMid, SenioritySenior, SeniorityLead, SeniorityPrincipal:
+		return true
+	}
+	return false
+}
+
+// NonNil returns an empty slice for nil, so attribute lists encode as [] in JSON and satisfy
+// NOT NULL array columns
+func NonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// Orders of the public job listing
+const (
+	JobSortScore  = "score"
+	JobSortSalary = "salary"
+	JobSortPosted = "posted"
+)
+
+// JobListFilter narrows the public job listing by extracted attributes. Empty fields match everything.
+type JobListFilter struct {
+	Seniority          string
+	Skills             []string // Every skill must be required by the job
+	TechStack          []string // Every entry must be in the job's stack
+	MaxYearsExperience *int     // Jobs asking for at most this many years, or not saying
+	VisaSponsorship    *bool
+	// MinSalary is a yearly amount in the base currency; jobs without a normalized salary are left out
+	MinSalary   *float64
+	PostedAfter *time.Time
+	Sort        string // JobSortScore (default), JobSortSalary or JobSortPosted
+
+	Workplace string // geo.WorkplaceRemote, geo.WorkplaceHybrid or geo.WorkplaceOnsite
+	// Near and RadiusKm keep jobs within RadiusKm of Near. Jobs without coordinates are left out,
+	// and remote jobs too unless IncludeRemote.
+	Near          *geo.Point
+	RadiusKm      float64
+	IncludeRemote bool
+}
+
+// JobFilter selects jobs for admin bulk operations. Empty fields match everything,
+// but callers must set at least one.
+type JobFilter struct {
+	Status        JobStatus
+	Company       string
+	Source        string
+	CreatedBefore *time.Time
+}
+
+// IsEmpty reports whether the filter would match every job
+func (f JobFilter) IsEmpty() bool {
+	return f.Status == "" && f.Company == "" && f.Source == "" && f.CreatedBefore == nil
+}
+
+// JobDeleteCounts is what a bulk delete removed, or would remove in a dry run
+type JobDeleteCounts struct {
+	Jobs           int64
+	Matches        int64
+	Notifications  int64
+	PipelineEvents int64
+}
 
+// JobDuplicate is another posting of the same job, on another board or under another URL
+type JobDuplicate struct {
+	ID        uuid.UUID
+	Source    string
+	JobURL    string
+	Lifecycle JobLifecycle
+	CreatedAt time.Time
+}
+
+// PipelineEvent records one stage a job went through. Per-user stages (user analysis and
+// notification) set UserID and SavedSearchID. Details holds stage-specific facts such as the
+// score or the number of searches fanned out to.
+type PipelineEvent struct {
+	ID            uuid.UUID
+	JobID         uuid.UUID
+	UserID        *uuid.UUID
+	SavedSearchID *uuid.UUID
+	Stage         string
+	Outcome       string
+	Details       map[string]interface{}
+	Error         string
+	CreatedAt     time.Time
+}
+
+// Pipeline stages, in order
+const (
+	PipelineStageIngested        = "ingested"
+	PipelineStageCompanyResearch = "company_research"
+	PipelineStageFanout          = "fanout"
+	PipelineStageUserAnalysis    = "user_analysis"
+	PipelineStageNotification    = "notification"
+)
+
+// Pipeline stage outcomes
+const (
+	PipelineOutcomeCreated      = "created"       // ingested
+	PipelineOutcomePromoted     = "promoted"      // ingested: a job a user evaluated was scraped
+	PipelineOutcomeResearched   = "researched"    // company_research
+	PipelineOutcomeSkippedFresh = "skipped_fresh" // company_research: company info is less than 6 months old
+	PipelineOutcomeFannedOut    = "fanned_out"    // fanout
+	PipelineOutcomeAnalyzed     = "analyzed"      // user_analysis
+	PipelineOutcomeSent         = "sent"          // notification
+	PipelineOutcomeDeferred     = "deferred"      // notification: held for the digest, see the reason
+	PipelineOutcomeSkipped      = "skipped"       // any stage that had nothing to do, see the reason
+	PipelineOutcomeFailed       = "failed"        // any stage; see Error
+)
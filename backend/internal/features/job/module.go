@@ -1,21 +1,25 @@
 package job
 
 import (
+	"net/http"
 	"os"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jobping/backend/internal/features/job/handler"
 )
 
-// RegisterRoutes registers job-related HTTP routes
-func RegisterRoutes(r chi.Router, jobHandler *handler.JobHandler) {
+// RegisterRoutes registers job-related HTTP routes. Bulk deletes live under /api/admin/jobs.
+// A job's detail is public but includes the caller's match when signed in. Its pipeline timeline
+// is for admins, as it shows every user's matches.
+func RegisterRoutes(r chi.Router, jobHandler *handler.JobHandler, optionalAuth, authenticate, requireAdmin func(http.Handler) http.Handler) {
 	r.Get("/jobs", jobHandler.GetJobs)
+	r.With(optionalAuth).Get("/jobs/{id}", jobHandler.GetJob)
+	r.With(authenticate, requireAdmin).Get("/jobs/{id}/timeline", jobHandler.GetTimeline)
 
 	// Local development endpoints only
 	// In production, these go through Python Lambda + SQS
 	if os.Getenv("ENVIRONMENT") != "production" {
 		r.Post("/jobs/fetch", jobHandler.FetchJobs)    // Proxy to Python JobSpy service
 		r.Post("/jobs/process", jobHandler.ProcessJob) // Process real jobs from Python
-		r.Delete("/jobs", jobHandler.DeleteAllJobs)    // Delete all jobs (for testing)
 	}
 }
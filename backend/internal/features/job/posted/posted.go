@@ -0,0 +1,88 @@
+// Package posted turns the scraper's free-text posting dates into timestamps. Boards report
+// them as ISO dates, as relative strings ("3 days ago") or not at all.
+package posted
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// layouts are the absolute date formats seen in scraped postings; dates without a zone are UTC
+var layouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05-07:00",
+	"2006-01-02",
+	"01/02/2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"2 Jan 2006",
+	"2 January 2006",
+}
+
+// relativePattern matches "3 days ago", "30+ days ago", "an hour ago", "posted 2 weeks ago"
+var relativePattern = regexp.MustCompile(`^(?:posted\s+)?(?:about\s+)?(\d+|an?|one)\+?\s*(seconds?|secs?|minutes?|mins?|hours?|hrs?|h|days?|d|weeks?|wks?|w|months?|mo|years?|yrs?|y)\s+ago$`)
+
+// Parse returns when a job was posted. Relative strings count back from ingestedAt, and empty
+// or unrecognized values yield ingestedAt itself. Dates after ingestedAt are clamped to it.
+func Parse(raw string, ingestedAt time.Time) time.Time {
+	postedAt, ok := parse(strings.TrimSpace(raw), ingestedAt)
+	if !ok || postedAt.After(ingestedAt) {
+		return ingestedAt
+	}
+	return postedAt
+}
+
+func parse(raw string, ingestedAt time.Time) (time.Time, bool) {
+	lower := strings.ToLower(raw)
+	switch lower {
+	case "", "nan", "nat", "none", "null":
+		return time.Time{}, false
+	case "today", "just now", "just posted", "new":
+		return ingestedAt, true
+	case "yesterday":
+		return ingestedAt.AddDate(0, 0, -1), true
+	}
+
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t, true
+		}
+	}
+
+	// Unix timestamps, in seconds or milliseconds
+	if n, err := strconv.ParseInt(raw, 10, 64); err == nil && n > 0 {
+		if n > 1e12 {
+			return time.UnixMilli(n), true
+		}
+		return time.Unix(n, 0), true
+	}
+
+	m := relativePattern.FindStringSubmatch(lower)
+	if m == nil {
+		return time.Time{}, false
+	}
+	n := 1
+	if v, err := strconv.Atoi(m[1]); err == nil {
+		n = v
+	}
+	switch unit := m[2]; {
+	case strings.HasPrefix(unit, "s"):
+		return ingestedAt.Add(-time.Duration(n) * time.Second), true
+	case strings.HasPrefix(unit, "mi"):
+		return ingestedAt.Add(-time.Duration(n) * time.Minute), true
+	case strings.HasPrefix(unit, "h"):
+		return ingestedAt.Add(-time.Duration(n) * time.Hour), true
+	case strings.HasPrefix(unit, "d"):
+		return ingestedAt.AddDate(0, 0, -n), true
+	case strings.HasPrefix(unit, "w"):
+		return ingestedAt.AddDate(0, 0, -7*n), true
+	case strings.HasPrefix(unit, "mo"):
+		return ingestedAt.AddDate(0, -n, 0), true
+	default:
+		return ingestedAt.AddDate(-n, 0, 0), true
+	}
+}
@@ -0,0 +1,57 @@
+package posted
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	ingested := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		raw  string
+		want time.Time
+	}{
+		// Absolute dates
+		{"2026-03-01", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"2026-03-01T09:30:00Z", time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)},
+		{"2026-03-01T09:30:00", time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)},
+		{"2026-03-01 09:30:00", time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)},
+		{"03/01/2026", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"Mar 1, 2026", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"1 March 2026", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		// Unix timestamps in seconds and milliseconds
+		{"1772366400", time.Unix(1772366400, 0)},
+		{"1772366400000", time.UnixMilli(1772366400000)},
+		// Relative strings
+		{"today", ingested},
+		{"Just posted", ingested},
+		{"yesterday", ingested.AddDate(0, 0, -1)},
+		{"3 days ago", ingested.AddDate(0, 0, -3)},
+		{"30+ days ago", ingested.AddDate(0, 0, -30)},
+		{"Posted 2 weeks ago", ingested.AddDate(0, 0, -14)},
+		{"about an hour ago", ingested.Add(-time.Hour)},
+		{"5h ago", ingested.Add(-5 * time.Hour)},
+		{"45 mins ago", ingested.Add(-45 * time.Minute)},
+		{"2 months ago", ingested.AddDate(0, -2, 0)},
+		{"1 year ago", ingested.AddDate(-1, 0, 0)},
+		// Dates after ingestion are clamped
+		{"2026-04-01", ingested},
+		{"1893456000000", ingested},
+		// Missing or unrecognized values fall back to ingestion
+		{"", ingested},
+		{"NaN", ingested},
+		{"None", ingested},
+		{"sometime last spring", ingested},
+		{"0", ingested},
+		{"-1772366400", ingested},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if got := Parse(tt.raw, ingested); !got.Equal(tt.want) {
+				t.Errorf("Parse(%q) = %s, want %s", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
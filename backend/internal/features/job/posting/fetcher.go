@@ -0,0 +1,154 @@
+package posting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	// ErrInvalidURL is returned for URLs that are not absolute http(s) URLs
+	ErrInvalidURL = errors.New("job URL must be an absolute http or https URL")
+	// ErrUnreachable is returned when the page cannot be fetched, or resolves to a private address
+	ErrUnreachable = errors.New("could not fetch the job page")
+	// ErrNotFound is returned when the page has no readable posting
+	ErrNotFound = errors.New("no job posting found at the URL")
+
+	errBlockedAddress = errors.New("address is not public")
+)
+
+const (
+	maxPageSize  = 2 << 20
+	maxRedirects = 5
+)
+
+// Fetcher reads a job posting from its URL
+type Fetcher interface {
+	Fetch(ctx context.Context, rawURL string) (*Posting, error)
+}
+
+type httpFetcher struct {
+	client *http.Client
+}
+
+// NewFetcher returns a Fetcher that only connects to public addresses, so user-submitted URLs
+// cannot reach the VPC, the metadata service or localhost, including through redirects
+func NewFetcher() Fetcher {
+	return newFetcher(isPublic)
+}
+
+// newFetcher returns a Fetcher that only dials addresses allowed by allow. The check runs on the
+// resolved address of every connection, so DNS names and redirects cannot get around it.
+func newFetcher(allow func(net.IP) bool) Fetcher {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !allow(ip) {
+				return errBlockedAddress
+			}
+			return nil
+		},
+	}
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: 10 * time.Second,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+	return &httpFetcher{client: &http.Client{
+		Transport: transport,
+		Timeout:   15 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			return checkScheme(req.URL)
+		},
+	}}
+}
+
+// ParseURL validates a user-submitted job URL
+func ParseURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return nil, ErrInvalidURL
+	}
+	if err := checkScheme(u); err != nil {
+		return nil, err
+	}
+	u.Fragment = ""
+	return u, nil
+}
+
+func checkScheme(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ErrInvalidURL
+	}
+	return nil
+}
+
+func (f *httpFetcher) Fetch(ctx context.Context, rawURL string) (*Posting, error) {
+	u, err := ParseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, ErrInvalidURL
+	}
+	req.Header.Set("User-Agent", "JobPing job reader")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%w: %v", ErrUnreachable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrUnreachable, resp.StatusCode)
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, ErrNotFound
+	}
+
+	page, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnreachable, err)
+	}
+	posting := Extract(page)
+	if posting.Title == "" || posting.Description == "" {
+		return nil, ErrNotFound
+	}
+	return &posting, nil
+}
+
+// isPublic reports whether ip is a globally routable unicast address
+func isPublic(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	// Carrier-grade NAT, 100.64.0.0/10
+	if ip4 := ip.To4(); ip4 != nil && ip4[0] == 100 && ip4[1]&0xC0 == 64 {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package posting
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		ip     string
+		public bool
+	}{
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"0.0.0.0", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"172.31.255.255", false},
+		{"172.32.0.1", true},
+		{"192.168.1.1", false},
+		// Cloud metadata service
+		{"169.254.169.254", false},
+		// Carrier-grade NAT, 100.64.0.0/10
+		{"100.64.0.1", false},
+		{"100.127.255.255", false},
+		{"100.128.0.1", true},
+		// IPv6 unique local and link-local
+		{"fd00::1", false},
+		{"fc00::1", false},
+		{"fe80::1", false},
+		{"ff02::1", false},
+		// IPv4-mapped IPv6 is checked as IPv4
+		{"::ffff:10.0.0.1", false},
+		{"::ffff:169.254.169.254", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := isPublic(net.ParseIP(tt.ip)); got != tt.public {
+				t.Errorf("isPublic(%s) = %v, want %v", tt.ip, got, tt.public)
+			}
+		})
+	}
+}
+
+func TestFetcherRefusesLoopback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("fetcher reached a loopback server")
+	}))
+	defer srv.Close()
+
+	if _, err := NewFetcher().Fetch(context.Background(), srv.URL); !errors.Is(err, ErrUnreachable) {
+		t.Errorf("err = %v, want ErrUnreachable", err)
+	}
+}
+
+// listen starts a server on a given loopback address, so tests can tell two servers apart by IP
+func listen(t *testing.T, addr string, handler http.Handler) *httptest.Server {
+	t.Helper()
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("cannot listen on %s: %v", addr, err)
+	}
+	srv := httptest.NewUnstartedServer(handler)
+	srv.Listener = l
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetcherRefusesRedirectToPrivateAddress(t *testing.T) {
+	// 127.0.0.1 stands in for a public site and 127.0.0.2 for an internal one
+	internal := listen(t, "127.0.0.2:0", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("fetcher followed the redirect to the internal address")
+	}))
+	public := listen(t, "127.0.0.1:0", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, internal.URL+"/latest/meta-data/", http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Go Engineer</title><meta name="description" content="Build things"></head></html>`))
+	}))
+
+	fetcher := newFetcher(func(ip net.IP) bool { return ip.Equal(net.IPv4(127, 0, 0, 1)) })
+
+	// The stand-in public site is reachable, so a refusal below comes from the address check
+	posting, err := fetcher.Fetch(context.Background(), public.URL+"/job")
+	if err != nil || posting.Title != "Go Engineer" {
+		t.Fatalf("Fetch = %+v, %v; want the posting", posting, err)
+	}
+	if _, err := fetcher.Fetch(context.Background(), public.URL+"/redirect"); !errors.Is(err, ErrUnreachable) {
+		t.Errorf("redirect: err = %v, want ErrUnreachable", err)
+	}
+}
@@ -0,0 +1,220 @@
+// Package posting reads a job posting from its web page, for jobs users submit by URL. Most boards
+// embed a schema.org JobPosting as JSON-LD; pages without one fall back to their meta tags and text.
+package posting
+
+import (
+	"encoding/json"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// MaxDescriptionLength is how much of a description is kept, as for scraped jobs
+const MaxDescriptionLength = 5000
+
+// Posting is what could be read from a job page. Fields the page does not state are empty.
+type Posting struct {
+	Title       string
+	Company     string
+	Location    string
+	Description string
+	IsRemote    bool
+}
+
+var (
+	jsonLDPattern    = regexp.MustCompile(`(?is)<script[^>]*type\s*=\s*["']application/ld\+json["'][^>]*>(.*?)</script>`)
+	titlePattern     = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	metaPattern      = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	attrPattern      = regexp.MustCompile(`(?is)([a-z][a-z:_-]*)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	bodyPattern      = regexp.MustCompile(`(?is)<body[^>]*>(.*)</body>`)
+	hiddenPattern    = regexp.MustCompile(`(?is)<(script|style|noscript|svg|nav|header|footer)\b[^>]*>.*?</(?:script|style|noscript|svg|nav|header|footer)>`)
+	breakPattern     = regexp.MustCompile(`(?i)<br\s*/?>|</(?:p|div|li|h[1-6]|tr|section|article)>`)
+	tagPattern       = regexp.MustCompile(`(?s)<[^>]*>`)
+	spacePattern     = regexp.MustCompile(`[ \t\r\f\v\x{00a0}]+`)
+	blankLinePattern = regexp.MustCompile(`\n{3,}`)
+)
+
+// Extract reads the posting from a page's HTML
+func Extract(page []byte) Posting {
+	doc := string(page)
+	p := fromJSONLD(doc)
+
+	meta := metaTags(doc)
+	if p.Title == "" {
+		p.Title = firstNonEmpty(meta["og:title"], meta["twitter:title"], pageTitle(doc))
+	}
+	if p.Company == "" {
+		p.Company = meta["og:site_name"]
+	}
+	if p.Description == "" {
+		p.Description = bodyText(doc)
+		if p.Description == "" {
+			p.Description = firstNonEmpty(meta["og:description"], meta["description"])
+		}
+	}
+	p.Description = Truncate(p.Description)
+	return p
+}
+
+// Truncate caps a description at MaxDescriptionLength bytes without splitting a character
+func Truncate(description string) string {
+	if len(description) <= MaxDescriptionLength {
+		return description
+	}
+	cut := MaxDescriptionLength
+	for cut > 0 && !isRuneStart(description[cut]) {
+		cut--
+	}
+	return description[:cut]
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// fromJSONLD returns the first JobPosting in the page's JSON-LD blocks
+func fromJSONLD(doc string) Posting {
+	for _, block := range jsonLDPattern.FindAllStringSubmatch(doc, -1) {
+		var data interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(block[1])), &data); err != nil {
+			continue
+		}
+		if job := findJobPosting(data); job != nil {
+			return Posting{
+				Title:       text(job["title"]),
+				Company:     name(job["hiringOrganization"]),
+				Location:    location(job["jobLocation"]),
+				Description: HTMLToText(text(job["description"])),
+				IsRemote:    strings.EqualFold(text(job["jobLocationType"]), "TELECOMMUTE"),
+			}
+		}
+	}
+	return Posting{}
+}
+
+// findJobPosting walks arrays and @graph lists for an object typed JobPosting
+func findJobPosting(data interface{}) map[string]interface{} {
+	switch v := data.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if job := findJobPosting(item); job != nil {
+				return job
+			}
+		}
+	case map[string]interface{}:
+		if isType(v["@type"], "JobPosting") {
+			return v
+		}
+		if graph, ok := v["@graph"]; ok {
+			return findJobPosting(graph)
+		}
+	}
+	return nil
+}
+
+func isType(value interface{}, want string) bool {
+	switch v := value.(type) {
+	case string:
+		return v == want
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok && s == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// name reads an organization or place that is either a string or an object with a name
+func name(value interface{}) string {
+	if obj, ok := value.(map[string]interface{}); ok {
+		return text(obj["name"])
+	}
+	return text(value)
+}
+
+// location joins locality, region and country of the first jobLocation, e.g. "Austin, TX, US"
+func location(value interface{}) string {
+	if list, ok := value.([]interface{}); ok {
+		if len(list) == 0 {
+			return ""
+		}
+		value = list[0]
+	}
+	place, ok := value.(map[string]interface{})
+	if !ok {
+		return text(value)
+	}
+	address, ok := place["address"].(map[string]interface{})
+	if !ok {
+		return firstNonEmpty(text(place["address"]), name(place))
+	}
+	var parts []string
+	for _, key := range []string{"addressLocality", "addressRegion", "addressCountry"} {
+		if part := name(address[key]); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func text(value interface{}) string {
+	s, _ := value.(string)
+	return strings.TrimSpace(html.UnescapeString(s))
+}
+
+// metaTags maps meta names and properties, lowercased, to their content
+func metaTags(doc string) map[string]string {
+	tags := make(map[string]string)
+	for _, tag := range metaPattern.FindAllString(doc, -1) {
+		attrs := make(map[string]string)
+		for _, attr := range attrPattern.FindAllStringSubmatch(tag, -1) {
+			attrs[strings.ToLower(attr[1])] = attr[2] + attr[3]
+		}
+		key := strings.ToLower(firstNonEmpty(attrs["property"], attrs["name"]))
+		if key != "" && tags[key] == "" {
+			tags[key] = strings.TrimSpace(html.UnescapeString(attrs["content"]))
+		}
+	}
+	return tags
+}
+
+func pageTitle(doc string) string {
+	if m := titlePattern.FindStringSubmatch(doc); m != nil {
+		return strings.TrimSpace(html.UnescapeString(m[1]))
+	}
+	return ""
+}
+
+// bodyText is the visible text of the page body, without scripts and navigation
+func bodyText(doc string) string {
+	body := doc
+	if m := bodyPattern.FindStringSubmatch(doc); m != nil {
+		body = m[1]
+	}
+	return HTMLToText(hiddenPattern.ReplaceAllString(body, ""))
+}
+
+// HTMLToText turns an HTML fragment into plain text, keeping paragraph and line breaks
+func HTMLToText(fragment string) string {
+	s := breakPattern.ReplaceAllString(fragment, "\n")
+	s = html.UnescapeString(tagPattern.ReplaceAllString(s, ""))
+	s = spacePattern.ReplaceAllString(s, " ")
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	s = blankLinePattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(s)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
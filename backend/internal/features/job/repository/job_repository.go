@@ -3,24 +3,80 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jobping/backend/internal/features/job/model"
+	"github.com/jobping/backend/internal/geo"
 )
 
 type JobRepository interface {
 	Create(ctx context.Context, job *model.Job) error
 	GetByID(ctx context.Context, id uuid.UUID) (*model.Job, error)
 	GetByURL(ctx context.Context, url string) (*model.Job, error)
+	GetDuplicates(ctx context.Context, job *model.Job, limit int) ([]model.JobDuplicate, error)
 	GetAll(ctx context.Context, limit int) ([]model.Job, error)
-	GetProcessed(ctx context.Context, limit int) ([]model.Job, error)
+	GetProcessed(ctx context.Context, filter model.JobListFilter, limit int) ([]model.Job, error)
+	GetProcessedSince(ctx context.Context, since time.Time, after *model.Job, limit int) ([]model.Job, error)
 	Update(ctx context.Context, job *model.Job) error
+	Promote(ctx context.Context, job *model.Job) (bool, error)
 	UpdateCompanyInfo(ctx context.Context, id uuid.UUID, companyInfo map[string]interface{}) error
+	UpdateAttributes(ctx context.Context, id uuid.UUID, attrs model.JobAttributes) error
+	UpdateNormalizedSalary(ctx context.Context, id uuid.UUID, minYearly, maxYearly *float64) error
+	UpdatePostedAt(ctx context.Context, id uuid.UUID, postedAt time.Time) error
+	UpdatePlace(ctx context.Context, id uuid.UUID, place geo.Location) error
+	GetUnparsedLocations(ctx context.Context, limit int) ([]model.Job, error)
 	ExistsByURL(ctx context.Context, url string) (bool, error)
 	IsCompanyInfoFresh(ctx context.Context, id uuid.UUID) (bool, error)
-	DeleteAll(ctx context.Context) error
+	DeleteByID(ctx context.Context, id uuid.UUID) (bool, error)
+	DeleteMatching(ctx context.Context, filter model.JobFilter, dryRun bool, hook DeleteHook) (model.JobDeleteCounts, error)
+	// Lifecycle sweeps
+	ExpirePostedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	GetForURLCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]model.Job, error)
+	RecordURLCheck(ctx context.Context, id uuid.UUID, closed bool) error
+	ArchiveEndedBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+}
+
+// DeleteHook runs inside DeleteMatching's transaction once the rows are gone. An error rolls the
+// delete back.
+type DeleteHook func(ctx context.Context, tx pgx.Tx, counts model.JobDeleteCounts) error
+
+// jobColumns lists the jobs columns in the order scanJob reads them
+const jobColumns = `id, title, company, location,
+	location_city, location_region, location_country, workplace_type, latitude, longitude, location_parsed_at,
+	job_url, source, description, job_type, is_remote,
+	min_salary, max_salary, salary_currency, salary_interval, min_salary_normalized, max_salary_normalized, date_posted, posted_at,
+	ai_score, ai_analysis, company_info, company_info_updated_at,
+	seniority, required_skills, years_experience, visa_sponsorship, tech_stack, attribute_sources, attributes_extracted_at,
+	status, lifecycle, lifecycle_changed_at, created_at, updated_at`
+
+func scanJob(row pgx.Row, job *model.Job) error {
+	var latitude, longitude *float64
+	err := row.Scan(
+		&job.ID, &job.Title, &job.Company, &job.Location,
+		&job.Place.City, &job.Place.Region, &job.Place.Country, &job.Place.Workplace, &latitude, &longitude, &job.LocationParsedAt,
+		&job.JobURL, &job.Source, &job.Description,
+		&job.JobType, &job.IsRemote,
+		&job.MinSalary, &job.MaxSalary, &job.SalaryCurrency, &job.SalaryInterval, &job.MinSalaryNormalized, &job.MaxSalaryNormalized, &job.DatePosted, &job.PostedAt,
+		&job.AIScore, &job.AIAnalysis, &job.CompanyInfo, &job.CompanyInfoUpdatedAt,
+		&job.Attributes.Seniority, &job.Attributes.RequiredSkills, &job.Attributes.YearsExperience, &job.Attributes.VisaSponsorship,
+		&job.Attributes.TechStack, &job.Attributes.Sources, &job.Attributes.ExtractedAt,
+		&job.Status, &job.Lifecycle, &job.LifecycleChangedAt, &job.CreatedAt, &job.UpdatedAt,
+	)
+	if latitude != nil && longitude != nil {
+		job.Place.Coords = &geo.Point{Latitude: *latitude, Longitude: *longitude}
+	}
+	return err
+}
+
+// coordinates splits a point into the nullable latitude and longitude columns
+func coordinates(p *geo.Point) (latitude, longitude *float64) {
+	if p == nil {
+		return nil, nil
+	}
+	return &p.Latitude, &p.Longitude
 }
 
 type postgresJobRepository struct {
@@ -33,28 +89,34 @@ func NewJobRepository(db *pgxpool.Pool) JobRepository {
 
 func (r *postgresJobRepository) Create(ctx context.Context, job *model.Job) error {
 	query := `
-		INSERT INTO jobs (id, title, company, location, job_url, description, job_type, is_remote, min_salary, max_salary, date_posted, ai_score, ai_analysis, company_info, company_info_updated_at, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		INSERT INTO jobs (` + jobColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25,
+			$26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39, $40)
 	`
+	if job.Lifecycle == "" {
+		job.Lifecycle = model.JobLifecycleActive
+	}
+	attrs := job.Attributes
+	latitude, longitude := coordinates(job.Place.Coords)
 	_, err := r.db.Exec(ctx, query,
-		job.ID, job.Title, job.Company, job.Location, job.JobURL, job.Description,
-		job.JobType, job.IsRemote, job.MinSalary, job.MaxSalary, job.DatePosted,
-		job.AIScore, job.AIAnalysis, job.CompanyInfo, job.CompanyInfoUpdatedAt, job.Status, job.CreatedAt, job.UpdatedAt,
+		job.ID, job.Title, job.Company, job.Location,
+		job.Place.City, job.Place.Region, job.Place.Country, job.Place.Workplace, latitude, longitude, job.LocationParsedAt,
+		job.JobURL, job.Source, job.Description, job.JobType, job.IsRemote,
+		job.MinSalary, job.MaxSalary, job.SalaryCurrency, job.SalaryInterval, job.MinSalaryNormalized, job.MaxSalaryNormalized, job.DatePosted, job.PostedAt,
+		job.AIScore, job.AIAnalysis, job.CompanyInfo, job.CompanyInfoUpdatedAt,
+		attrs.Seniority, model.NonNil(attrs.RequiredSkills), attrs.YearsExperience, attrs.VisaSponsorship, model.NonNil(attrs.TechStack), attrs.Sources, attrs.ExtractedAt,
+		job.Status, job.Lifecycle, job.LifecycleChangedAt, job.CreatedAt, job.UpdatedAt,
 	)
 	return err
 }
 
 func (r *postgresJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Job, error) {
 	query := `
-		SELECT id, title, company, location, job_url, description, job_type, is_remote, min_salary, max_salary, date_posted, ai_score, ai_analysis, company_info, company_info_updated_at, status, created_at, updated_at
+		SELECT ` + jobColumns + `
 		FROM jobs WHERE id = $1
 	`
 	var job model.Job
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&job.ID, &job.Title, &job.Company, &job.Location, &job.JobURL, &job.Description,
-		&job.JobType, &job.IsRemote, &job.MinSalary, &job.MaxSalary, &job.DatePosted,
-		&job.AIScore, &job.AIAnalysis, &job.CompanyInfo, &job.CompanyInfoUpdatedAt, &job.Status, &job.CreatedAt, &job.UpdatedAt,
-	)
+	err := scanJob(r.db.QueryRow(ctx, query, id), &job)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
 	}
@@ -66,15 +128,11 @@ func (r *postgresJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*mod
 
 func (r *postgresJobRepository) GetByURL(ctx context.Context, url string) (*model.Job, error) {
 	query := `
-		SELECT id, title, company, location, job_url, description, job_type, is_remote, min_salary, max_salary, date_posted, ai_score, ai_analysis, company_info, company_info_updated_at, status, created_at, updated_at
+		SELECT ` + jobColumns + `
 		FROM jobs WHERE job_url = $1
 	`
 	var job model.Job
-	err := r.db.QueryRow(ctx, query, url).Scan(
-		&job.ID, &job.Title, &job.Company, &job.Location, &job.JobURL, &job.Description,
-		&job.JobType, &job.IsRemote, &job.MinSalary, &job.MaxSalary, &job.DatePosted,
-		&job.AIScore, &job.AIAnalysis, &job.CompanyInfo, &job.CompanyInfoUpdatedAt, &job.Status, &job.CreatedAt, &job.UpdatedAt,
-	)
+	err := scanJob(r.db.QueryRow(ctx, query, url), &job)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
 	}
@@ -84,9 +142,36 @@ func (r *postgresJobRepository) GetByURL(ctx context.Context, url string) (*mode
 	return &job, nil
 }
 
+// GetDuplicates returns up to limit other postings with the job's title and company, ignoring case,
+// oldest first. Jobs users submitted for evaluation are private to them and never listed.
+func (r *postgresJobRepository) GetDuplicates(ctx context.Context, job *model.Job, limit int) ([]model.JobDuplicate, error) {
+	query := `
+		SELECT id, source, job_url, lifecycle, created_at
+		FROM jobs
+		WHERE LOWER(company) = LOWER($1) AND LOWER(title) = LOWER($2) AND id <> $3 AND source <> $4
+		ORDER BY created_at
+		LIMIT $5
+	`
+	rows, err := r.db.Query(ctx, query, job.Company, job.Title, job.ID, model.JobSourceUser, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var duplicates []model.JobDuplicate
+	for rows.Next() {
+		var d model.JobDuplicate
+		if err := rows.Scan(&d.ID, &d.Source, &d.JobURL, &d.Lifecycle, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		duplicates = append(duplicates, d)
+	}
+	return duplicates, rows.Err()
+}
+
 func (r *postgresJobRepository) GetAll(ctx context.Context, limit int) ([]model.Job, error) {
 	query := `
-		SELECT id, title, company, location, job_url, description, job_type, is_remote, min_salary, max_salary, date_posted, ai_score, ai_analysis, company_info, company_info_updated_at, status, created_at, updated_at
+		SELECT ` + jobColumns + `
 		FROM jobs
 		ORDER BY created_at DESC
 		LIMIT $1
@@ -94,19 +179,73 @@ func (r *postgresJobRepository) GetAll(ctx context.Context, limit int) ([]model.
 	return r.queryJobs(ctx, query, limit)
 }
 
-func (r *postgresJobRepository) GetProcessed(ctx context.Context, limit int) ([]model.Job, error) {
+// GetProcessed returns active processed jobs matching filter, best scored first
+func (r *postgresJobRepository) GetProcessed(ctx context.Context, filter model.JobListFilter, limit int) ([]model.Job, error) {
 	query := `
-		SELECT id, title, company, location, job_url, description, job_type, is_remote, min_salary, max_salary, date_posted, ai_score, ai_analysis, company_info, company_info_updated_at, status, created_at, updated_at
+		SELECT ` + jobColumns + `
 		FROM jobs
-		WHERE status = 'processed'
-		ORDER BY ai_score DESC NULLS LAST, created_at DESC
-		LIMIT $1
+		WHERE status = 'processed' AND lifecycle = 'active'
+		  AND ($1 = '' OR seniority = $1)
+		  AND required_skills @> $2
+		  AND tech_stack @> $3
+		  AND ($4::int IS NULL OR years_experience IS NULL OR years_experience <= $4)
+		  AND ($5::boolean IS NULL OR visa_sponsorship = $5)
+		  AND ($6::numeric IS NULL OR COALESCE(max_salary_normalized, min_salary_normalized) >= $6)
+		  AND ($7::timestamptz IS NULL OR posted_at >= $7)
+		  AND ($8 = '' OR workplace_type = $8)
+		  AND ($9::float8 IS NULL
+		       OR (latitude BETWEEN $9 - $11::float8 / 111.0 AND $9 + $11 / 111.0
+		           AND ` + distanceKmSQL + ` <= $11)
+		       OR ($12 AND workplace_type = 'remote'))
+		ORDER BY ` + jobListOrder(filter.Sort) + `
+		LIMIT $13
 	`
-	return r.queryJobs(ctx, query, limit)
+	nearLatitude, nearLongitude := coordinates(filter.Near)
+	return r.queryJobs(ctx, query, filter.Seniority, model.NonNil(filter.Skills), model.NonNil(filter.TechStack),
+		filter.MaxYearsExperience, filter.VisaSponsorship, filter.MinSalary, filter.PostedAfter,
+		filter.Workplace, nearLatitude, nearLongitude, filter.RadiusKm, filter.IncludeRemote, limit)
+}
+
+// distanceKmSQL is the haversine distance in km from ($9, $10) to the job's coordinates. The
+// latitude bounding box before it (one degree is about 111 km) keeps it off most rows.
+const distanceKmSQL = `2 * 6371 * ASIN(LEAST(1, SQRT(
+	POWER(SIN(RADIANS(latitude - $9) / 2), 2) +
+	COS(RADIANS($9)) * COS(RADIANS(latitude)) * POWER(SIN(RADIANS(longitude - $10::float8) / 2), 2))))`
+
+// jobListOrder returns the ORDER BY clause for a JobListFilter sort. Salaries sort by the top
+// of the normalized range, so a "90k-150k" job ranks above a flat 120k.
+func jobListOrder(sort string) string {
+	switch sort {
+	case model.JobSortSalary:
+		return "COALESCE(max_salary_normalized, min_salary_normalized) DESC NULLS LAST, posted_at DESC"
+	case model.JobSortPosted:
+		return "posted_at DESC"
+	default:
+		return "ai_score DESC NULLS LAST, posted_at DESC"
+	}
 }
 
-func (r *postgresJobRepository) queryJobs(ctx context.Context, query string, limit int) ([]model.Job, error) {
-	rows, err := r.db.Query(ctx, query, limit)
+// GetProcessedSince returns a page of active processed jobs created after since, newest first.
+// after is the last job of the previous page; nil starts with the newest.
+func (r *postgresJobRepository) GetProcessedSince(ctx context.Context, since time.Time, after *model.Job, limit int) ([]model.Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE status = 'processed' AND lifecycle = 'active' AND created_at > $1
+			AND ($2::timestamptz IS NULL OR (created_at, id) < ($2, $3))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4
+	`
+	var afterCreatedAt *time.Time
+	var afterID uuid.UUID
+	if after != nil {
+		afterCreatedAt, afterID = &after.CreatedAt, after.ID
+	}
+	return r.queryJobs(ctx, query, since, afterCreatedAt, afterID, limit)
+}
+
+func (r *postgresJobRepository) queryJobs(ctx context.Context, query string, args ...interface{}) ([]model.Job, error) {
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -115,11 +254,7 @@ func (r *postgresJobRepository) queryJobs(ctx context.Context, query string, lim
 	var jobs []model.Job
 	for rows.Next() {
 		var job model.Job
-		if err := rows.Scan(
-			&job.ID, &job.Title, &job.Company, &job.Location, &job.JobURL, &job.Description,
-			&job.JobType, &job.IsRemote, &job.MinSalary, &job.MaxSalary, &job.DatePosted,
-			&job.AIScore, &job.AIAnalysis, &job.CompanyInfo, &job.CompanyInfoUpdatedAt, &job.Status, &job.CreatedAt, &job.UpdatedAt,
-		); err != nil {
+		if err := scanJob(rows, &job); err != nil {
 			return nil, err
 		}
 		jobs = append(jobs, job)
@@ -133,6 +268,56 @@ func (r *postgresJobRepository) UpdateCompanyInfo(ctx context.Context, id uuid.U
 	return err
 }
 
+// UpdateNormalizedSalary stores the salary range converted to a yearly base-currency amount
+func (r *postgresJobRepository) UpdateNormalizedSalary(ctx context.Context, id uuid.UUID, minYearly, maxYearly *float64) error {
+	query := `UPDATE jobs SET min_salary_normalized = $1, max_salary_normalized = $2, updated_at = NOW() WHERE id = $3`
+	_, err := r.db.Exec(ctx, query, minYearly, maxYearly, id)
+	return err
+}
+
+func (r *postgresJobRepository) UpdatePostedAt(ctx context.Context, id uuid.UUID, postedAt time.Time) error {
+	_, err := r.db.Exec(ctx, `UPDATE jobs SET posted_at = $1, updated_at = NOW() WHERE id = $2`, postedAt, id)
+	return err
+}
+
+// UpdatePlace stores the parsed location and marks it parsed
+func (r *postgresJobRepository) UpdatePlace(ctx context.Context, id uuid.UUID, place geo.Location) error {
+	query := `
+		UPDATE jobs
+		SET location_city = $1, location_region = $2, location_country = $3, workplace_type = $4,
+			latitude = $5, longitude = $6, location_parsed_at = NOW(), updated_at = NOW()
+		WHERE id = $7
+	`
+	latitude, longitude := coordinates(place.Coords)
+	_, err := r.db.Exec(ctx, query, place.City, place.Region, place.Country, place.Workplace, latitude, longitude, id)
+	return err
+}
+
+// GetUnparsedLocations returns jobs whose location has not been parsed yet, oldest first
+func (r *postgresJobRepository) GetUnparsedLocations(ctx context.Context, limit int) ([]model.Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE location_parsed_at IS NULL
+		ORDER BY created_at
+		LIMIT $1
+	`
+	return r.queryJobs(ctx, query, limit)
+}
+
+// UpdateAttributes stores the attributes extracted from the job's description
+func (r *postgresJobRepository) UpdateAttributes(ctx context.Context, id uuid.UUID, attrs model.JobAttributes) error {
+	query := `
+		UPDATE jobs
+		SET seniority = $1, required_skills = $2, years_experience = $3, visa_sponsorship = $4, tech_stack = $5,
+			attribute_sources = $6, attributes_extracted_at = $7, updated_at = NOW()
+		WHERE id = $8
+	`
+	_, err := r.db.Exec(ctx, query, attrs.Seniority, model.NonNil(attrs.RequiredSkills), attrs.YearsExperience,
+		attrs.VisaSponsorship, model.NonNil(attrs.TechStack), attrs.Sources, attrs.ExtractedAt, id)
+	return err
+}
+
 func (r *postgresJobRepository) Update(ctx context.Context, job *model.Job) error {
 	query := `
 		UPDATE jobs 
@@ -143,6 +328,36 @@ func (r *postgresJobRepository) Update(ctx context.Context, job *model.Job) erro
 	return err
 }
 
+// Promote overwrites a job a user submitted for evaluation with the scraped job of the same URL,
+// keeping its ID so the evaluation's matches stay attached. It returns false when the row is not
+// a user-submitted job, for instance because it was promoted already.
+func (r *postgresJobRepository) Promote(ctx context.Context, job *model.Job) (bool, error) {
+	query := `
+		UPDATE jobs SET title = $1, company = $2, location = $3,
+			location_city = $4, location_region = $5, location_country = $6, workplace_type = $7,
+			latitude = $8, longitude = $9, location_parsed_at = $10,
+			source = $11, description = $12, job_type = $13, is_remote = $14,
+			min_salary = $15, max_salary = $16, salary_currency = $17, salary_interval = $18,
+			date_posted = $19, posted_at = $20, ai_score = $21, ai_analysis = $22,
+			company_info = $23, company_info_updated_at = $24, status = $25, updated_at = $26
+		WHERE id = $27 AND source = $28
+	`
+	latitude, longitude := coordinates(job.Place.Coords)
+	tag, err := r.db.Exec(ctx, query,
+		job.Title, job.Company, job.Location,
+		job.Place.City, job.Place.Region, job.Place.Country, job.Place.Workplace, latitude, longitude, job.LocationParsedAt,
+		job.Source, job.Description, job.JobType, job.IsRemote,
+		job.MinSalary, job.MaxSalary, job.SalaryCurrency, job.SalaryInterval,
+		job.DatePosted, job.PostedAt, job.AIScore, job.AIAnalysis,
+		job.CompanyInfo, job.CompanyInfoUpdatedAt, job.Status, job.UpdatedAt,
+		job.ID, model.JobSourceUser,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
 func (r *postgresJobRepository) ExistsByURL(ctx context.Context, url string) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM jobs WHERE job_url = $1)`
 	var exists bool
@@ -164,23 +379,145 @@ func (r *postgresJobRepository) IsCompanyInfoFresh(ctx context.Context, id uuid.
 	return isFresh, err
 }
 
-func (r *postgresJobRepository) DeleteAll(ctx context.Context) error {
-	// Delete in order to respect foreign key constraints
-	// Notifications depend on user_job_matches, which depend on jobs
-	// But since we have ON DELETE CASCADE, we can delete jobs directly
-	// However, let's delete notifications first to be safe, then matches, then jobs
-	queries := []string{
-		`DELETE FROM notifications`,
-		`DELETE FROM user_job_matches`,
-		`DELETE FROM jobs`,
-	}
-	
-	for _, query := range queries {
-		if _, err := r.db.Exec(ctx, query); err != nil {
-			return err
+// DeleteByID removes a job; its matches and notifications go with it (ON DELETE CASCADE)
+func (r *postgresJobRepository) DeleteByID(ctx context.Context, id uuid.UUID) (bool, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM jobs WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// jobFilterCondition selects the jobs matching a JobFilter; see jobFilterArgs
+const jobFilterCondition = `($1 = '' OR status = $1)
+	AND ($2 = '' OR LOWER(company) = LOWER($2))
+	AND ($3 = '' OR source = $3)
+	AND ($4::timestamptz IS NULL OR created_at < $4)`
+
+func jobFilterArgs(filter model.JobFilter) []interface{} {
+	return []interface{}{string(filter.Status), filter.Company, filter.Source, filter.CreatedBefore}
+}
+
+// DeleteMatching deletes the jobs matching filter with their notifications and matches, in one
+// transaction, and returns the counts. With dryRun it only counts what would be deleted.
+func (r *postgresJobRepository) DeleteMatching(ctx context.Context, filter model.JobFilter, dryRun bool, hook DeleteHook) (model.JobDeleteCounts, error) {
+	var counts model.JobDeleteCounts
+	args := jobFilterArgs(filter)
+
+	if dryRun {
+		query := `
+			WITH matched AS (SELECT id FROM jobs WHERE ` + jobFilterCondition + `)
+			SELECT
+				(SELECT COUNT(*) FROM matched),
+				(SELECT COUNT(*) FROM user_job_matches WHERE job_id IN (SELECT id FROM matched)),
+				(SELECT COUNT(*) FROM notifications WHERE job_id IN (SELECT id FROM matched)),
+				(SELECT COUNT(*) FROM job_pipeline_events WHERE job_id IN (SELECT id FROM matched))
+		`
+		err := r.db.QueryRow(ctx, query, args...).Scan(&counts.Jobs, &counts.Matches, &counts.Notifications, &counts.PipelineEvents)
+		return counts, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return counts, err
+	}
+	defer tx.Rollback(ctx)
+
+	// ON DELETE CASCADE would remove notifications, matches and pipeline events too, but deleting
+	// them explicitly is what gives their counts
+	matched := `SELECT id FROM jobs WHERE ` + jobFilterCondition
+	steps := []struct {
+		query string
+		count *int64
+	}{
+		{`DELETE FROM notifications WHERE job_id IN (` + matched + `)`, &counts.Notifications},
+		{`DELETE FROM user_job_matches WHERE job_id IN (` + matched + `)`, &counts.Matches},
+		{`DELETE FROM job_pipeline_events WHERE job_id IN (` + matched + `)`, &counts.PipelineEvents},
+		{`DELETE FROM jobs WHERE ` + jobFilterCondition, &counts.Jobs},
+	}
+	for _, step := range steps {
+		tag, err := tx.Exec(ctx, step.query, args...)
+		if err != nil {
+			return model.JobDeleteCounts{}, err
 		}
+		*step.count = tag.RowsAffected()
+	}
+	if hook != nil {
+		if err := hook(ctx, tx, counts); err != nil {
+			return model.JobDeleteCounts{}, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return model.JobDeleteCounts{}, err
 	}
-	return nil
+	return counts, nil
 }
 
+// ExpirePostedBefore marks active jobs posted before cutoff as expired and returns how many changed
+func (r *postgresJobRepository) ExpirePostedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `
+		UPDATE jobs SET lifecycle = 'expired', lifecycle_changed_at = NOW(), updated_at = NOW()
+		WHERE lifecycle = 'active' AND posted_at < $1
+	`
+	tag, err := r.db.Exec(ctx, query, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
 
+// GetForURLCheck returns active jobs whose URL was not checked since checkedBefore, never-checked first.
+// Pasted jobs have no web URL to check.
+func (r *postgresJobRepository) GetForURLCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]model.Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE lifecycle = 'active' AND (url_checked_at IS NULL OR url_checked_at < $1) AND job_url ~* '^https?://'
+		ORDER BY url_checked_at NULLS FIRST, posted_at
+		LIMIT $2
+	`
+	return r.queryJobs(ctx, query, checkedBefore, limit)
+}
+
+// RecordURLCheck notes that the job's URL was checked, and closes the job if the posting is gone
+func (r *postgresJobRepository) RecordURLCheck(ctx context.Context, id uuid.UUID, closed bool) error {
+	query := `
+		UPDATE jobs SET url_checked_at = NOW(),
+			lifecycle = CASE WHEN $1 THEN 'closed' ELSE lifecycle END,
+			lifecycle_changed_at = CASE WHEN $1 THEN NOW() ELSE lifecycle_changed_at END,
+			updated_at = NOW()
+		WHERE id = $2
+	`
+	_, err := r.db.Exec(ctx, query, closed, id)
+	return err
+}
+
+// ArchiveEndedBefore archives up to limit jobs that expired or closed before cutoff and returns how
+// many it archived. The rows stay in jobs, so their matches and notifications are kept; the
+// description and company info move to jobs_archive.
+func (r *postgresJobRepository) ArchiveEndedBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	query := `
+		WITH ended AS (
+			SELECT id, description, company_info FROM jobs
+			WHERE lifecycle IN ('expired', 'closed') AND lifecycle_changed_at < $1
+			ORDER BY lifecycle_changed_at
+			LIMIT $2
+			FOR UPDATE
+		), moved AS (
+			INSERT INTO jobs_archive (job_id, description, company_info)
+			SELECT id, description, company_info FROM ended
+			ON CONFLICT (job_id) DO UPDATE
+				SET description = EXCLUDED.description, company_info = EXCLUDED.company_info, archived_at = NOW()
+		)
+		UPDATE jobs SET lifecycle = 'archived', lifecycle_changed_at = NOW(),
+			description = '', company_info = NULL, updated_at = NOW()
+		FROM ended
+		WHERE jobs.id = ended.id
+	`
+	tag, err := r.db.Exec(ctx, query, cutoff, limit)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jobping/backend/internal/database"
+	"github.com/jobping/backend/internal/features/job/model"
+)
+
+// testDB connects to TEST_DATABASE_URL and migrates it, or skips when it is unset
+func testDB(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	migrations, err := filepath.Abs("../../../database/migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := database.RunMigrations(url, migrations); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	db, err := database.Connect(url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(db.Close)
+	return db
+}
+
+func TestArchiveKeepsMatchesAndNotifications(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t)
+
+	userID, searchID, jobID, matchID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	t.Cleanup(func() {
+		db.Exec(ctx, `DELETE FROM jobs WHERE id = $1`, jobID)
+		db.Exec(ctx, `DELETE FROM users WHERE id = $1`, userID)
+	})
+
+	fixtures := []struct {
+		query string
+		args  []any
+	}{
+		{`INSERT INTO users (id, username, password_hash) VALUES ($1, $2, 'hash')`,
+			[]any{userID, "archive-" + userID.String()}},
+		{`INSERT INTO saved_searches (id, user_id, name, ai_prompt) VALUES ($1, $2, 'Go', 'Go jobs')`,
+			[]any{searchID, userID}},
+		{`INSERT INTO jobs (id, title, company, job_url, description, company_info, lifecycle, lifecycle_changed_at)
+			VALUES ($1, 'Go Engineer', 'Acme', $2, 'A long description', '{"size": "10"}', 'expired', $3)`,
+			[]any{jobID, "https://jobs.example.com/" + jobID.String(), time.Now().AddDate(0, 0, -100)}},
+		{`INSERT INTO user_job_matches (id, user_id, job_id, saved_search_id, score, analysis)
+			VALUES ($1, $2, $3, $4, 80, '{}')`,
+			[]any{matchID, userID, jobID, searchID}},
+		{`INSERT INTO notifications (user_id, job_id, match_id, job_title, company, job_url, matching_score, ai_analysis)
+			VALUES ($1, $2, $3, 'Go Engineer', 'Acme', 'https://jobs.example.com/', 80, '{}')`,
+			[]any{userID, jobID, matchID}},
+	}
+	for _, f := range fixtures {
+		if _, err := db.Exec(ctx, f.query, f.args...); err != nil {
+			t.Fatalf("fixture: %v", err)
+		}
+	}
+
+	repo := NewJobRepository(db)
+	if _, err := repo.ArchiveEndedBefore(ctx, time.Now().AddDate(0, 0, -90), 500); err != nil {
+		t.Fatalf("ArchiveEndedBefore: %v", err)
+	}
+
+	job, err := repo.GetByID(ctx, jobID)
+	if err != nil || job == nil {
+		t.Fatalf("GetByID = %v, %v; want the archived job", job, err)
+	}
+	if job.Lifecycle != model.JobLifecycleArchived || job.Description != "" {
+		t.Errorf("lifecycle = %s, description = %q; want archived with the description moved", job.Lifecycle, job.Description)
+	}
+
+	var description string
+	if err := db.QueryRow(ctx, `SELECT description FROM jobs_archive WHERE job_id = $1`, jobID).Scan(&description); err != nil {
+		t.Fatalf("jobs_archive: %v", err)
+	}
+	if description != "A long description" {
+		t.Errorf("archived description = %q", description)
+	}
+
+	var matches, notifications int
+	db.QueryRow(ctx, `SELECT COUNT(*) FROM user_job_matches WHERE id = $1`, matchID).Scan(&matches)
+	db.QueryRow(ctx, `SELECT COUNT(*) FROM notifications WHERE match_id = $1`, matchID).Scan(&notifications)
+	if matches != 1 || notifications != 1 {
+		t.Errorf("matches = %d, notifications = %d after archiving; want both kept", matches, notifications)
+	}
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jobping/backend/internal/features/job/model"
+)
+
+// PipelineEventRepository stores the per-job pipeline timeline
+type PipelineEventRepository interface {
+	// Record appends an event; the ID and time are assigned by the database
+	Record(ctx context.Context, event model.PipelineEvent) error
+	// RecordBestEffort is Record for callers in the pipeline, which log failures and carry on
+	RecordBestEffort(ctx context.Context, event model.PipelineEvent)
+	GetByJobID(ctx context.Context, jobID uuid.UUID) ([]model.PipelineEvent, error)
+	// GetForUser returns the job-wide events and the user's own, leaving out other users'
+	GetForUser(ctx context.Context, jobID, userID uuid.UUID) ([]model.PipelineEvent, error)
+}
+
+type postgresPipelineEventRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPipelineEventRepository(db *pgxpool.Pool) PipelineEventRepository {
+	return &postgresPipelineEventRepository{db: db}
+}
+
+func (r *postgresPipelineEventRepository) Record(ctx context.Context, event model.PipelineEvent) error {
+	query := `
+		INSERT INTO job_pipeline_events (job_id, user_id, saved_search_id, stage, outcome, details, error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	details := event.Details
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	_, err := r.db.Exec(ctx, query,
+		event.JobID, event.UserID, event.SavedSearchID, event.Stage, event.Outcome, details, event.Error,
+	)
+	return err
+}
+
+// RecordBestEffort logs instead of returning errors. The timeline is diagnostics, so a failed
+// write must never fail or retry the job, match or notification it describes.
+func (r *postgresPipelineEventRepository) RecordBestEffort(ctx context.Context, event model.PipelineEvent) {
+	if err := r.Record(ctx, event); err != nil {
+		log.Printf("Failed to record %s event for job %s: %v", event.Stage, event.JobID, err)
+	}
+}
+
+const pipelineEventColumns = `id, job_id, user_id, saved_search_id, stage, outcome, details, error, created_at`
+
+// GetByJobID returns the job's events, oldest first
+func (r *postgresPipelineEventRepository) GetByJobID(ctx context.Context, jobID uuid.UUID) ([]model.PipelineEvent, error) {
+	query := `
+		SELECT ` + pipelineEventColumns + `
+		FROM job_pipeline_events
+		WHERE job_id = $1
+		ORDER BY created_at, id
+	`
+	return r.queryEvents(ctx, query, jobID)
+}
+
+// GetForUser returns the job's events that are job-wide or the user's, oldest first
+func (r *postgresPipelineEventRepository) GetForUser(ctx context.Context, jobID, userID uuid.UUID) ([]model.PipelineEvent, error) {
+	query := `
+		SELECT ` + pipelineEventColumns + `
+		FROM job_pipeline_events
+		WHERE job_id = $1 AND (user_id IS NULL OR user_id = $2)
+		ORDER BY created_at, id
+	`
+	return r.queryEvents(ctx, query, jobID, userID)
+}
+
+func (r *postgresPipelineEventRepository) queryEvents(ctx context.Context, query string, args ...interface{}) ([]model.PipelineEvent, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []model.PipelineEvent
+	for rows.Next() {
+		var event model.PipelineEvent
+		if err := rows.Scan(
+			&event.ID, &event.JobID, &event.UserID, &event.SavedSearchID, &event.Stage,
+			&event.Outcome, &event.Details, &event.Error, &event.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
@@ -3,73 +3,83 @@ package service
 import (
 	"context"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/job/joberr"
 	"github.com/jobping/backend/internal/features/job/model"
+	"github.com/jobping/backend/internal/features/job/posted"
 	"github.com/jobping/backend/internal/features/job/repository"
 	usermodel "github.com/jobping/backend/internal/features/user/model"
 	userrepo "github.com/jobping/backend/internal/features/user/repository"
+	"github.com/jobping/backend/internal/salary"
 )
 
 type JobService struct {
 	repo      repository.JobRepository
+	events    repository.PipelineEventRepository
 	aiClient  AIClient
-	userRepo  userrepo.UserRepository
 	matchRepo userrepo.UserJobMatchRepository
-	sqsClient SQSClient
 }
 
-func NewJobService(repo repository.JobRepository, aiClient AIClient, userRepo userrepo.UserRepository, matchRepo userrepo.UserJobMatchRepository) *JobService {
+func NewJobService(repo repository.JobRepository, events repository.PipelineEventRepository, aiClient AIClient, matchRepo userrepo.UserJobMatchRepository) *JobService {
 	return &JobService{
 		repo:      repo,
+		events:    events,
 		aiClient:  aiClient,
-		userRepo:  userRepo,
 		matchRepo: matchRepo,
-		sqsClient: NewSQSClient(),
 	}
 }
 
-// ProcessJob receives a job from SQS, runs AI analysis, company research, and user matching
+// ProcessJob receives a job from SQS and runs AI analysis and company research. Users are matched
+// by the fanout and user-analysis stages, which score each saved search. A job a user submitted
+// for evaluation is promoted to the scraped job, keeping its ID.
 func (s *JobService) ProcessJob(ctx context.Context, input *JobInput) (*model.Job, error) {
 	// Check if job already exists
 	existingJob, err := s.repo.GetByURL(ctx, input.JobURL)
 	if err != nil {
 		return nil, err
 	}
-	if existingJob != nil {
+	if existingJob != nil && existingJob.Source != model.JobSourceUser {
 		log.Printf("Job already exists: %s", input.JobURL)
-		// Still run user matching for existing jobs if they haven't been matched yet
-		if existingJob.Status == model.JobStatusProcessed {
-			s.matchJobToUsers(ctx, existingJob)
-		}
 		return existingJob, nil
 	}
 
 	now := time.Now()
 	job := &model.Job{
-		ID:          uuid.New(),
-		Title:       input.Title,
-		Company:     input.Company,
-		Location:    input.Location,
-		JobURL:      input.JobURL,
-		Description: input.Description,
-		JobType:     input.JobType,
-		IsRemote:    input.IsRemote,
-		MinSalary:   input.MinSalary,
-		MaxSalary:   input.MaxSalary,
-		DatePosted:  input.DatePosted,
-		Status:      model.JobStatusPending,
-		CreatedAt:   now,
-		UpdatedAt:   now,
-	}
-
-	// Step 1: Research company
-	companyInfo, err := s.aiClient.ResearchCompany(ctx, job.Company, job.Title, job.Description)
-	if err != nil {
+		ID:             uuid.New(),
+		Title:          input.Title,
+		Company:        input.Company,
+		Location:       input.Location,
+		JobURL:         input.JobURL,
+		Source:         strings.ToLower(strings.TrimSpace(input.Site)),
+		Description:    input.Description,
+		JobType:        input.JobType,
+		IsRemote:       input.IsRemote,
+		MinSalary:      input.MinSalary,
+		MaxSalary:      input.MaxSalary,
+		SalaryCurrency: strings.ToUpper(strings.TrimSpace(input.Currency)),
+		SalaryInterval: salary.NormalizeInterval(input.Interval),
+		DatePosted:     input.DatePosted,
+		PostedAt:       posted.Parse(input.DatePosted, now),
+		Status:         model.JobStatusPending,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	job.Place = job.ParsePlace()
+	job.LocationParsedAt = &now
+
+	// Step 1: Research company, unless the evaluation of a user-submitted job did
+	research := model.PipelineEvent{Stage: model.PipelineStageCompanyResearch, Outcome: model.PipelineOutcomeResearched}
+	if existingJob != nil && existingJob.CompanyInfo != nil {
+		job.CompanyInfo, job.CompanyInfoUpdatedAt = existingJob.CompanyInfo, existingJob.CompanyInfoUpdatedAt
+		research.Outcome = model.PipelineOutcomeSkippedFresh
+	} else if companyInfo, err := s.aiClient.ResearchCompany(ctx, job.Company, job.Title, job.Description); err != nil {
 		log.Printf("Company research failed: %v", err)
+		research.Outcome, research.Error = model.PipelineOutcomeFailed, err.Error()
 	} else {
-		job.CompanyInfo = companyInfo
+		job.CompanyInfo, job.CompanyInfoUpdatedAt = companyInfo, &now
 	}
 
 	// Step 2: Run general AI analysis
@@ -83,122 +93,97 @@ func (s *JobService) ProcessJob(ctx context.Context, input *JobInput) (*model.Jo
 		job.Status = model.JobStatusProcessed
 	}
 
-	if err := s.repo.Create(ctx, job); err != nil {
+	ingested := model.PipelineOutcomeCreated
+	if existingJob != nil {
+		ingested = model.PipelineOutcomePromoted
+		job.ID, job.CreatedAt = existingJob.ID, existingJob.CreatedAt
+		promoted, err := s.repo.Promote(ctx, job)
+		if err != nil {
+			return nil, err
+		}
+		if !promoted {
+			// Another worker promoted it first
+			return s.repo.GetByURL(ctx, input.JobURL)
+		}
+	} else if err := s.repo.Create(ctx, job); err != nil {
 		return nil, err
 	}
-
-	// Step 3: Match to all users with AI prompts
-	if job.Status == model.JobStatusProcessed {
-		s.matchJobToUsers(ctx, job)
-	}
+	s.events.RecordBestEffort(ctx, model.PipelineEvent{
+		JobID:   job.ID,
+		Stage:   model.PipelineStageIngested,
+		Outcome: ingested,
+		Details: map[string]interface{}{"source": job.Source},
+	})
+	research.JobID = job.ID
+	s.events.RecordBestEffort(ctx, research)
 
 	return job, nil
 }
 
-// matchJobToUsers compares a job against all users with AI prompts and stores matches
-func (s *JobService) matchJobToUsers(ctx context.Context, job *model.Job) {
-	if s.userRepo == nil || s.matchRepo == nil {
-		log.Printf("User/match repos not configured, skipping user matching")
-		return
+// GetTimeline returns the job with the pipeline stages it went through, oldest first
+func (s *JobService) GetTimeline(ctx context.Context, jobID uuid.UUID) (*model.Job, []model.PipelineEvent, error) {
+	job, err := s.repo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, nil, err
 	}
-
-	users, err := s.userRepo.GetUsersWithPrompts(ctx)
+	if job == nil {
+		return nil, nil, joberr.ErrJobNotFound
+	}
+	events, err := s.events.GetByJobID(ctx, jobID)
 	if err != nil {
-		log.Printf("Failed to get users with prompts: %v", err)
-		return
+		return nil, nil, err
 	}
+	return job, events, nil
+}
 
-	log.Printf("Matching job %s to %d users", job.Title, len(users))
-
-	for _, user := range users {
-		if user.AIPrompt == nil || *user.AIPrompt == "" {
-			continue
-		}
+// maxDuplicates caps the other postings listed with a job
+const maxDuplicates = 20
 
-		// Check if already matched
-		existing, err := s.matchRepo.GetByUserAndJob(ctx, user.ID, job.ID)
-		if err != nil {
-			log.Printf("Failed to check existing match: %v", err)
-			continue
-		}
-		if existing != nil {
-			continue // Already matched
-		}
+// JobDetail is a job with its other postings and, for a signed-in caller, their best match
+type JobDetail struct {
+	Job        *model.Job
+	Duplicates []model.JobDuplicate
+	Match      *usermodel.UserJobMatch // nil when anonymous or not matched
+}
 
-		// Run AI matching
-		matchInput := &JobMatchInput{
-			Title:       job.Title,
-			Company:     job.Company,
-			Description: job.Description,
-			CompanyInfo: job.CompanyInfo,
-		}
+// GetDetail returns a job for its detail page. The match is looked up only when userID is set.
+// Jobs a user submitted for evaluation are private: they are not found for anyone without a match.
+func (s *JobService) GetDetail(ctx context.Context, jobID uuid.UUID, userID *uuid.UUID) (*JobDetail, error) {
+	job, err := s.repo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, joberr.ErrJobNotFound
+	}
+	detail := &JobDetail{Job: job}
 
-		matchResult, err := s.aiClient.MatchJobToUser(ctx, matchInput, *user.AIPrompt)
+	if userID != nil && s.matchRepo != nil {
+		detail.Match, err = s.matchRepo.GetByUserAndJob(ctx, *userID, jobID)
 		if err != nil {
-			log.Printf("Failed to match job to user %s: %v", user.Username, err)
-			continue
-		}
-
-		// Store match result
-		match := &usermodel.UserJobMatch{
-			ID:        uuid.New(),
-			UserID:    user.ID,
-			JobID:     job.ID,
-			Score:     matchResult.Score,
-			Analysis:  matchResult.Analysis,
-			Notified:  false,
-			CreatedAt: time.Now(),
-		}
-
-		if err := s.matchRepo.Create(ctx, match); err != nil {
-			log.Printf("Failed to save match: %v", err)
-			continue
-		}
-
-		log.Printf("Matched job %s to user %s with score %d", job.Title, user.Username, matchResult.Score)
-
-		// Check if should notify
-		if matchResult.Score >= user.NotifyThreshold && user.DiscordWebhook != nil {
-			s.queueNotification(ctx, user, job, matchResult)
+			return nil, err
 		}
 	}
-}
-
-// queueNotification sends a notification request to the notification SQS queue
-func (s *JobService) queueNotification(ctx context.Context, user usermodel.User, job *model.Job, match *UserMatchResult) {
-	if user.DiscordWebhook == nil || *user.DiscordWebhook == "" {
-		log.Printf("User %s has no Discord webhook configured, skipping notification", user.Username)
-		return
-	}
-
-	notification := &NotificationMessage{
-		DiscordWebhook: *user.DiscordWebhook,
-		JobTitle:       job.Title,
-		Company:        job.Company,
-		JobURL:         job.JobURL,
-		Score:          match.Score,
-		Analysis:       match.Analysis,
+	if job.Source == model.JobSourceUser {
+		if detail.Match == nil {
+			return nil, joberr.ErrJobNotFound
+		}
+		return detail, nil
 	}
 
-	if err := s.sqsClient.SendNotification(ctx, notification); err != nil {
-		log.Printf("Failed to queue notification for user %s: %v", user.Username, err)
-		return
+	detail.Duplicates, err = s.repo.GetDuplicates(ctx, job, maxDuplicates)
+	if err != nil {
+		return nil, err
 	}
-
-	log.Printf("Queued notification for user %s about job %s (score: %d)", user.Username, job.Title, match.Score)
+	return detail, nil
 }
 
-// GetJobs returns processed jobs for display
-func (s *JobService) GetJobs(ctx context.Context, limit int) ([]model.Job, error) {
+// GetJobs returns processed jobs for display, narrowed by their extracted attributes
+func (s *JobService) GetJobs(ctx context.Context, filter model.JobListFilter, limit int) ([]model.Job, error) {
 	if limit <= 0 {
 		limit = 20
 	}
-	return s.repo.GetProcessed(ctx, limit)
-}
-
-// DeleteAllJobs removes all jobs from the database (for testing)
-func (s *JobService) DeleteAllJobs(ctx context.Context) error {
-	return s.repo.DeleteAll(ctx)
+	return s.repo.GetProcessed(ctx, filter, limit)
 }
 
 // JobInput represents a job from SQS message
@@ -207,11 +192,14 @@ type JobInput struct {
 	Company     string   `json:"company"`
 	Location    string   `json:"location"`
 	JobURL      string   `json:"job_url"`
+	Site        string   `json:"site"`
 	Description string   `json:"description"`
 	JobType     string   `json:"job_type"`
 	IsRemote    bool     `json:"is_remote"`
 	MinSalary   *float64 `json:"min_amount"`
 	MaxSalary   *float64 `json:"max_amount"`
+	Currency    string   `json:"currency"`
+	Interval    string   `json:"interval"`
 	DatePosted  string   `json:"date_posted"`
 }
 
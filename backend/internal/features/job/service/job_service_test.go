@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/job/model"
+	"github.com/jobping/backend/internal/features/job/repository"
+)
+
+// memoryJobs implements the parts of JobRepository ProcessJob uses
+type memoryJobs struct {
+	repository.JobRepository
+	jobs map[string]*model.Job
+}
+
+func (r *memoryJobs) GetByURL(ctx context.Context, url string) (*model.Job, error) {
+	if job, ok := r.jobs[url]; ok {
+		found := *job
+		return &found, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryJobs) Create(ctx context.Context, job *model.Job) error {
+	stored := *job
+	r.jobs[job.JobURL] = &stored
+	return nil
+}
+
+func (r *memoryJobs) Promote(ctx context.Context, job *model.Job) (bool, error) {
+	existing, ok := r.jobs[job.JobURL]
+	if !ok || existing.ID != job.ID || existing.Source != model.JobSourceUser {
+		return false, nil
+	}
+	stored := *job
+	r.jobs[job.JobURL] = &stored
+	return true, nil
+}
+
+// memoryEvents keeps the recorded pipeline events
+type memoryEvents struct {
+	repository.PipelineEventRepository
+	events []model.PipelineEvent
+}
+
+func (r *memoryEvents) RecordBestEffort(ctx context.Context, event model.PipelineEvent) {
+	r.events = append(r.events, event)
+}
+
+// stubAI scores every job 70 and counts company research calls
+type stubAI struct {
+	AIClient
+	researched int
+}
+
+func (c *stubAI) AnalyzeJob(ctx context.Context, title, company, description string) (*AIAnalysisResult, error) {
+	return &AIAnalysisResult{Score: 70, Analysis: "fine"}, nil
+}
+
+func (c *stubAI) ResearchCompany(ctx context.Context, company, title, description string) (map[string]interface{}, error) {
+	c.researched++
+	return map[string]interface{}{"name": company}, nil
+}
+
+func TestProcessJobPromotesEvaluatedJob(t *testing.T) {
+	ctx := context.Background()
+	researchedAt := time.Now().Add(-time.Hour)
+	evaluated := &model.Job{
+		ID:                   uuid.New(),
+		Title:                "Go Engineer",
+		Company:              "Acme",
+		JobURL:               "https://jobs.example.com/1",
+		Source:               model.JobSourceUser,
+		Status:               model.JobStatusPending,
+		CompanyInfo:          map[string]interface{}{"name": "Acme"},
+		CompanyInfoUpdatedAt: &researchedAt,
+	}
+	jobs := &memoryJobs{jobs: map[string]*model.Job{evaluated.JobURL: evaluated}}
+	events := &memoryEvents{}
+	ai := &stubAI{}
+
+	job, err := NewJobService(jobs, events, ai, nil).ProcessJob(ctx, &JobInput{
+		Title:       "Senior Go Engineer",
+		Company:     "Acme",
+		JobURL:      evaluated.JobURL,
+		Site:        "Indeed",
+		Description: "Scraped description",
+	})
+	if err != nil {
+		t.Fatalf("ProcessJob: %v", err)
+	}
+
+	stored := jobs.jobs[evaluated.JobURL]
+	if job.ID != evaluated.ID || stored.ID != evaluated.ID {
+		t.Errorf("job ID = %s, stored %s; want the evaluated job's %s kept", job.ID, stored.ID, evaluated.ID)
+	}
+	if stored.Source != "indeed" || stored.Title != "Senior Go Engineer" || stored.Status != model.JobStatusProcessed {
+		t.Errorf("stored source %q title %q status %q; want the scraped, processed job", stored.Source, stored.Title, stored.Status)
+	}
+	if ai.researched != 0 {
+		t.Errorf("researched the company %d times, want the evaluation's research reused", ai.researched)
+	}
+	if len(events.events) == 0 || events.events[0].Outcome != model.PipelineOutcomePromoted {
+		t.Errorf("events = %+v, want an ingested event with outcome promoted", events.events)
+	}
+}
+
+func TestProcessJobSkipsScrapedJob(t *testing.T) {
+	ctx := context.Background()
+	scraped := &model.Job{ID: uuid.New(), JobURL: "https://jobs.example.com/1", Source: "indeed", Title: "Go Engineer"}
+	jobs := &memoryJobs{jobs: map[string]*model.Job{scraped.JobURL: scraped}}
+	ai := &stubAI{}
+
+	job, err := NewJobService(jobs, &memoryEvents{}, ai, nil).ProcessJob(ctx, &JobInput{
+		Title:  "Renamed",
+		JobURL: scraped.JobURL,
+		Site:   "linkedin",
+	})
+	if err != nil {
+		t.Fatalf("ProcessJob: %v", err)
+	}
+	if job.Title != "Go Engineer" || jobs.jobs[scraped.JobURL].Source != "indeed" || ai.researched != 0 {
+		t.Errorf("job %q source %q researched %d; want the existing job untouched", job.Title, jobs.jobs[scraped.JobURL].Source, ai.researched)
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jobping/backend/internal/features/job/repository"
+)
+
+const (
+	// urlCheckBatchSize bounds how many posting URLs one sweep checks
+	urlCheckBatchSize = 20
+	// urlRecheckInterval is how long a checked URL is trusted before it is checked again
+	urlRecheckInterval = 24 * time.Hour
+	// archiveBatchSize bounds how many jobs one sweep archives
+	archiveBatchSize = 500
+	// locationBatchSize bounds how many unparsed locations one sweep parses
+	locationBatchSize = 500
+)
+
+// SweepResult counts what one lifecycle sweep changed
+type SweepResult struct {
+	Expired  int64
+	Checked  int
+	Closed   int
+	Archived int64
+
+	LocationsParsed int
+}
+
+// LifecycleService moves jobs through their lifecycle: active jobs expire once their posting is
+// older than the expiry age, or close when their URL is gone, and ended jobs are archived later.
+type LifecycleService struct {
+	repo         repository.JobRepository
+	checker      URLChecker
+	expiryAge    time.Duration
+	archiveAfter time.Duration
+}
+
+// NewLifecycleService builds the sweeper. A nil checker skips closure detection.
+func NewLifecycleService(repo repository.JobRepository, checker URLChecker, expiryDays, archiveAfterDays int) *LifecycleService {
+	return &LifecycleService{
+		repo:         repo,
+		checker:      checker,
+		expiryAge:    time.Duration(expiryDays) * 24 * time.Hour,
+		archiveAfter: time.Duration(archiveAfterDays) * 24 * time.Hour,
+	}
+}
+
+// Sweep expires old jobs, checks a batch of posting URLs, archives jobs that ended long enough ago
+// and parses the locations of jobs ingested before location parsing existed
+func (s *LifecycleService) Sweep(ctx context.Context) (SweepResult, error) {
+	var result SweepResult
+	now := time.Now()
+
+	expired, err := s.repo.ExpirePostedBefore(ctx, now.Add(-s.expiryAge))
+	if err != nil {
+		return result, err
+	}
+	result.Expired = expired
+
+	if s.checker != nil {
+		jobs, err := s.repo.GetForURLCheck(ctx, now.Add(-urlRecheckInterval), urlCheckBatchSize)
+		if err != nil {
+			return result, err
+		}
+		for _, job := range jobs {
+			if ctx.Err() != nil {
+				break
+			}
+			closed, err := s.checker.IsClosed(ctx, job.JobURL)
+			if err != nil {
+				// Unreachable is not the same as gone; try again next time
+				log.Printf("Failed to check URL of job %s: %v", job.ID, err)
+				continue
+			}
+			if err := s.repo.RecordURLCheck(ctx, job.ID, closed); err != nil {
+				return result, err
+			}
+			result.Checked++
+			if closed {
+				result.Closed++
+			}
+		}
+	}
+
+	archived, err := s.repo.ArchiveEndedBefore(ctx, now.Add(-s.archiveAfter), archiveBatchSize)
+	if err != nil {
+		return result, err
+	}
+	result.Archived = archived
+
+	unparsed, err := s.repo.GetUnparsedLocations(ctx, locationBatchSize)
+	if err != nil {
+		return result, err
+	}
+	for _, job := range unparsed {
+		if err := s.repo.UpdatePlace(ctx, job.ID, job.ParsePlace()); err != nil {
+			return result, err
+		}
+		result.LocationsParsed++
+	}
+	return result, nil
+}
+
+// RunSweeps calls Sweep every interval until ctx is done. The local server uses it;
+// in AWS a scheduled event triggers the sweep instead.
+func (s *LifecycleService) RunSweeps(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if result, err := s.Sweep(ctx); err != nil {
+			log.Printf("Job lifecycle sweep failed: %v", err)
+		} else if result.Expired > 0 || result.Closed > 0 || result.Archived > 0 || result.LocationsParsed > 0 {
+			log.Printf("Job lifecycle sweep: %d expired, %d of %d checked closed, %d archived, %d locations parsed",
+				result.Expired, result.Closed, result.Checked, result.Archived, result.LocationsParsed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
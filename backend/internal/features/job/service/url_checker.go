@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"net/http"
+)
+
+// URLChecker reports whether a job posting has been taken down
+type URLChecker interface {
+	IsClosed(ctx context.Context, url string) (bool, error)
+}
+
+// HTTPDoer is the part of *http.Client the URL checker uses, so tests and local runs can stub it
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type headURLChecker struct {
+	client HTTPDoer
+}
+
+// NewURLChecker returns a checker that sends a HEAD request to the posting. Only 404 and 410 count
+// as closed: boards often answer HEAD with 403 or 405, or rate-limit, which says nothing about the job.
+func NewURLChecker(client HTTPDoer) URLChecker {
+	return &headURLChecker{client: client}
+}
+
+func (c *headURLChecker) IsClosed(ctx context.Context, url string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("User-Agent", "JobPing link checker")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone, nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// stubDoer answers every request with a fixed status, or fails with err
+type stubDoer struct {
+	status int
+	err    error
+	last   *http.Request
+}
+
+func (d *stubDoer) Do(req *http.Request) (*http.Response, error) {
+	d.last = req
+	if d.err != nil {
+		return nil, d.err
+	}
+	return &http.Response{StatusCode: d.status, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+func TestHeadURLCheckerIsClosed(t *testing.T) {
+	tests := []struct {
+		status int
+		closed bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusMovedPermanently, false},
+		{http.StatusNotFound, true},
+		{http.StatusGone, true},
+		// Boards that refuse HEAD or bots say nothing about the posting
+		{http.StatusForbidden, false},
+		{http.StatusMethodNotAllowed, false},
+		{http.StatusTooManyRequests, false},
+		{http.StatusInternalServerError, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			doer := &stubDoer{status: tt.status}
+			closed, err := NewURLChecker(doer).IsClosed(context.Background(), "https://jobs.example.com/1")
+			if err != nil {
+				t.Fatalf("IsClosed: %v", err)
+			}
+			if closed != tt.closed {
+				t.Errorf("closed = %v, want %v", closed, tt.closed)
+			}
+			if doer.last.Method != http.MethodHead {
+				t.Errorf("method = %s, want HEAD", doer.last.Method)
+			}
+		})
+	}
+}
+
+func TestHeadURLCheckerReturnsNetworkErrors(t *testing.T) {
+	doer := &stubDoer{err: errors.New("connection reset")}
+	closed, err := NewURLChecker(doer).IsClosed(context.Background(), "https://jobs.example.com/1")
+	if err == nil || closed {
+		t.Errorf("closed = %v, err = %v; want an error and not closed, so the next sweep retries", closed, err)
+	}
+}
@@ -7,11 +7,22 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 )
 
 // AIClient interface for AI analysis (ChatGPT)
 type AIClient interface {
 	ResearchCompany(ctx context.Context, company, title, description string) (map[string]interface{}, error)
+	ExtractAttributes(ctx context.Context, title, description string) (*ExtractedAttributes, error)
+}
+
+// ExtractedAttributes is the model's reading of a job description, before validation
+type ExtractedAttributes struct {
+	Seniority       string   `json:"seniority"`
+	RequiredSkills  []string `json:"required_skills"`
+	YearsExperience *int     `json:"years_experience"`
+	VisaSponsorship *bool    `json:"visa_sponsorship"`
+	TechStack       []string `json:"tech_stack"`
 }
 
 type openAIClient struct {
@@ -84,6 +95,41 @@ Provide a JSON response with these fields:
 	return companyInfo, nil
 }
 
+func (c *openAIClient) ExtractAttributes(ctx context.Context, title, description string) (*ExtractedAttributes, error) {
+	if c.apiKey == "" {
+		return nil, fmt.Errorf("OPENAI_API_KEY is not configured")
+	}
+
+	prompt := fmt.Sprintf(`Extract structured facts from this job posting. Use null or an empty list when the posting does not say.
+
+Job Title: %s
+Job Description: %s
+
+Provide a JSON response with exactly these fields:
+{
+  "seniority": "one of intern, junior, mid, senior, lead, principal",
+  "required_skills": ["skills or technologies the posting requires, not nice-to-haves, at most 20"],
+  "years_experience": minimum years of experience required as a number, or null,
+  "visa_sponsorship": true if visa sponsorship is offered, false if it is ruled out, null if not mentioned,
+  "tech_stack": ["languages, frameworks, databases and platforms the team uses, at most 20"]
+}`, title, truncate(description, 4000))
+
+	result, err := c.callOpenAI(ctx, prompt, "You extract structured data from job postings. Reply with JSON only, and never guess facts the posting does not state.")
+	if err != nil {
+		return nil, err
+	}
+
+	// Models sometimes wrap JSON in a code fence
+	result = strings.TrimSpace(result)
+	result = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(result, "```json"), "```"), "```")
+
+	var attrs ExtractedAttributes
+	if err := json.Unmarshal([]byte(result), &attrs); err != nil {
+		return nil, fmt.Errorf("failed to parse extracted attributes: %w", err)
+	}
+	return &attrs, nil
+}
+
 func (c *openAIClient) callOpenAI(ctx context.Context, prompt, systemPrompt string) (string, error) {
 	reqBody := openAIRequest{
 		Model: "gpt-3.5-turbo",
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jobping/backend/internal/features/job/model"
+)
+
+// Values of JobAttributes.Sources
+const (
+	sourceLLM       = "llm"
+	sourceHeuristic = "heuristic"
+)
+
+const (
+	maxAttributeTerms   = 20
+	maxAttributeTermLen = 50
+	maxYearsExperience  = 40
+)
+
+// seniorityPatterns are checked against the title in order, so "Senior Staff Engineer" is principal
+var seniorityPatterns = []struct {
+	level   string
+	pattern *regexp.Regexp
+}{
+	{model.SeniorityPrincipal, regexp.MustCompile(`(?i)\b(principal|staff|distinguished)\b`)},
+	{model.SeniorityLead, regexp.MustCompile(`(?i)\b(lead|head of|team lead)\b`)},
+	{model.SenioritySenior, regexp.MustCompile(`(?i)\b(senior|sr\.?)(\s|$)`)},
+	{model.SeniorityJunior, regexp.MustCompile(`(?i)\b(junior|jr\.?|entry[- ]level|graduate)(\s|$)`)},
+	{model.SeniorityIntern, regexp.MustCompile(`(?i)\b(intern|internship|trainee)\b`)},
+	{model.SeniorityMid, regexp.MustCompile(`(?i)\b(mid[- ]level|intermediate)\b`)},
+}
+
+var (
+	// "5+ years of experience", "3-5 years' professional experience", "at least 2 years experience"
+	yearsPattern = regexp.MustCompile(`(?i)\b(\d{1,2})\s*\+?\s*(?:(?:-|–|to)\s*\d{1,2}\s*\+?\s*)?years?['’]?\s+(?:of\s+)?(?:[a-z-]+\s+){0,3}?experience`)
+
+	noSponsorshipPattern = regexp.MustCompile(`(?i)\b(no|not|unable to|cannot|can't|won't|will not|do not|does not|don't)\s+(?:\w+\s+){0,3}?(?:visa\s+)?sponsor`)
+	sponsorshipPattern   = regexp.MustCompile(`(?i)(visa sponsorship (?:is\s+)?(?:available|provided|offered)|(?:will|can|we)\s+sponsor|sponsorship (?:is\s+)?available)`)
+)
+
+// techTerms maps stack entries to the patterns that find them. "Go" and a few other names that are
+// also common words are matched case-sensitively.
+var techTerms = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{"go", regexp.MustCompile(`\bGo\b|(?i)\bgolang\b`)},
+	{"python", regexp.MustCompile(`(?i)\bpython\b`)},
+	{"java", regexp.MustCompile(`(?i)\bjava\b`)},
+	{"javascript", regexp.MustCompile(`(?i)\bjavascript\b`)},
+	{"typescript", regexp.MustCompile(`(?i)\btypescript\b`)},
+	{"rust", regexp.MustCompile(`(?i)\brust\b`)},
+	{"ruby", regexp.MustCompile(`(?i)\bruby\b`)},
+	{"php", regexp.MustCompile(`(?i)\bphp\b`)},
+	{"c#", regexp.MustCompile(`(?i)\bc#|\.net\b`)},
+	{"c++", regexp.MustCompile(`(?i)\bc\+\+`)},
+	{"kotlin", regexp.MustCompile(`(?i)\bkotlin\b`)},
+	{"swift", regexp.MustCompile(`\bSwift\b`)},
+	{"scala", regexp.MustCompile(`(?i)\bscala\b`)},
+	{"elixir", regexp.MustCompile(`(?i)\belixir\b`)},
+	{"react", regexp.MustCompile(`\bReact\b|(?i)\breact\.?js\b`)},
+	{"vue", regexp.MustCompile(`(?i)\bvue(\.js)?\b`)},
+	{"angular", regexp.MustCompile(`(?i)\bangular\b`)},
+	{"node.js", regexp.MustCompile(`(?i)\bnode\.?js\b`)},
+	{"django", regexp.MustCompile(`(?i)\bdjango\b`)},
+	{"rails", regexp.MustCompile(`(?i)\brails\b`)},
+	{"spring", regexp.MustCompile(`(?i)\bspring (boot|framework)\b`)},
+	{"postgresql", regexp.MustCompile(`(?i)\bpostgres(ql)?\b`)},
+	{"mysql", regexp.MustCompile(`(?i)\bmysql\b`)},
+	{"mongodb", regexp.MustCompile(`(?i)\bmongo(db)?\b`)},
+	{"redis", regexp.MustCompile(`(?i)\bredis\b`)},
+	{"kafka", regexp.MustCompile(`(?i)\bkafka\b`)},
+	{"elasticsearch", regexp.MustCompile(`(?i)\belastic\s?search\b`)},
+	{"graphql", regexp.MustCompile(`(?i)\bgraphql\b`)},
+	{"grpc", regexp.MustCompile(`(?i)\bgrpc\b`)},
+	{"aws", regexp.MustCompile(`(?i)\baws\b|amazon web services`)},
+	{"gcp", regexp.MustCompile(`(?i)\bgcp\b|google cloud`)},
+	{"azure", regexp.MustCompile(`(?i)\bazure\b`)},
+	{"docker", regexp.MustCompile(`(?i)\bdocker\b`)},
+	{"kubernetes", regexp.MustCompile(`(?i)\bkubernetes\b|\bk8s\b`)},
+	{"terraform", regexp.MustCompile(`(?i)\bterraform\b`)},
+	{"linux", regexp.MustCompile(`(?i)\blinux\b`)},
+}
+
+// heuristicAttributes extracts what cheap pattern matching can find: seniority from the title,
+// the first years-of-experience figure, explicit sponsorship statements and known technologies.
+// It never fills RequiredSkills, since patterns cannot tell requirements from nice-to-haves.
+func heuristicAttributes(title, description string) model.JobAttributes {
+	var attrs model.JobAttributes
+
+	for _, s := range seniorityPatterns {
+		if s.pattern.MatchString(title) {
+			attrs.Seniority = s.level
+			break
+		}
+	}
+
+	// The first figure is usually the core requirement; later ones tend to be for single skills
+	if m := yearsPattern.FindStringSubmatch(description); m != nil {
+		if years, err := strconv.Atoi(m[1]); err == nil && years <= maxYearsExperience {
+			attrs.YearsExperience = &years
+		}
+	}
+
+	switch {
+	case noSponsorshipPattern.MatchString(description):
+		sponsors := false
+		attrs.VisaSponsorship = &sponsors
+	case sponsorshipPattern.MatchString(description):
+		sponsors := true
+		attrs.VisaSponsorship = &sponsors
+	}
+
+	text := title + "\n" + description
+	for _, t := range techTerms {
+		if t.pattern.MatchString(text) {
+			attrs.TechStack = append(attrs.TechStack, t.name)
+		}
+	}
+	return attrs
+}
+
+// mergeAttributes validates the model's extraction and falls back to the heuristics for every
+// attribute the model left out or got wrong. extracted may be nil when the model call failed.
+func mergeAttributes(extracted *ExtractedAttributes, heuristic model.JobAttributes, now time.Time) model.JobAttributes {
+	attrs := heuristic
+	attrs.Sources = map[string]string{}
+	attrs.ExtractedAt = &now
+
+	if extracted != nil {
+		if seniority := strings.ToLower(strings.TrimSpace(extracted.Seniority)); model.ValidSeniority(seniority) {
+			attrs.Seniority = seniority
+			attrs.Sources["seniority"] = sourceLLM
+		}
+		if skills := cleanTerms(extracted.RequiredSkills); len(skills) > 0 {
+			attrs.RequiredSkills = skills
+			attrs.Sources["required_skills"] = sourceLLM
+		}
+		if y := extracted.YearsExperience; y != nil && *y >= 0 && *y <= maxYearsExperience {
+			attrs.YearsExperience = y
+			attrs.Sources["years_experience"] = sourceLLM
+		}
+		if extracted.VisaSponsorship != nil {
+			attrs.VisaSponsorship = extracted.VisaSponsorship
+			attrs.Sources["visa_sponsorship"] = sourceLLM
+		}
+		if stack := cleanTerms(extracted.TechStack); len(stack) > 0 {
+			attrs.TechStack = stack
+			attrs.Sources["tech_stack"] = sourceLLM
+		}
+	}
+
+	for name, found := range map[string]bool{
+		"seniority":        attrs.Seniority != "",
+		"years_experience": attrs.YearsExperience != nil,
+		"visa_sponsorship": attrs.VisaSponsorship != nil,
+		"tech_stack":       len(attrs.TechStack) > 0,
+	} {
+		if _, fromLLM := attrs.Sources[name]; found && !fromLLM {
+			attrs.Sources[name] = sourceHeuristic
+		}
+	}
+	return attrs
+}
+
+// cleanTerms lower-cases and trims terms, dropping empty, overlong and duplicate ones
+func cleanTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	var out []string
+	for _, term := range terms {
+		term = strings.ToLower(strings.TrimSpace(term))
+		if term == "" || len(term) > maxAttributeTermLen || seen[term] {
+			continue
+		}
+		seen[term] = true
+		out = append(out, term)
+		if len(out) == maxAttributeTerms {
+			break
+		}
+	}
+	return out
+}
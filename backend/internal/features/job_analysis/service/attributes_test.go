@@ -0,0 +1,193 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jobping/backend/internal/features/job/model"
+)
+
+func intPtr(n int) *int    { return &n }
+func boolPtr(b bool) *bool { return &b }
+
+func TestHeuristicSeniority(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Senior Backend Engineer", model.SenioritySenior},
+		{"Sr. Go Developer", model.SenioritySenior},
+		{"Senior Staff Engineer", model.SeniorityPrincipal},
+		{"Engineering Team Lead", model.SeniorityLead},
+		{"Head of Platform", model.SeniorityLead},
+		{"Junior Frontend Developer", model.SeniorityJunior},
+		{"Entry-level Data Analyst", model.SeniorityJunior},
+		{"Software Engineering Intern", model.SeniorityIntern},
+		{"Mid-level QA Engineer", model.SeniorityMid},
+		{"Backend Engineer", ""},
+		// Words that only contain a level are not one
+		{"Internal Tools Engineer", ""},
+		{"Leader in Sales", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			if got := heuristicAttributes(tt.title, "").Seniority; got != tt.want {
+				t.Errorf("seniority = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeuristicYearsAndSponsorship(t *testing.T) {
+	tests := []struct {
+		description string
+		years       *int
+		sponsors    *bool
+	}{
+		{"5+ years of experience with Go", intPtr(5), nil},
+		{"3-5 years' professional experience", intPtr(3), nil},
+		{"At least 2 years relevant industry experience. 8 years of Python experience a plus.", intPtr(2), nil},
+		{"10 to 12 years of experience", intPtr(10), nil},
+		{"Founded 25 years ago", nil, nil},
+		{"99 years of experience", nil, nil},
+		{"We are unable to offer visa sponsorship.", nil, boolPtr(false)},
+		{"This role does not provide sponsorship.", nil, boolPtr(false)},
+		{"Visa sponsorship is available.", nil, boolPtr(true)},
+		{"We will sponsor visas for the right candidate.", nil, boolPtr(true)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			attrs := heuristicAttributes("Engineer", tt.description)
+			if !reflect.DeepEqual(attrs.YearsExperience, tt.years) {
+				t.Errorf("years = %v, want %v", deref(attrs.YearsExperience), deref(tt.years))
+			}
+			if !reflect.DeepEqual(attrs.VisaSponsorship, tt.sponsors) {
+				t.Errorf("sponsorship = %v, want %v", deref(attrs.VisaSponsorship), deref(tt.sponsors))
+			}
+		})
+	}
+}
+
+func deref[T any](p *T) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+func TestHeuristicTechStack(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Go, PostgreSQL and Kubernetes on AWS", []string{"go", "postgresql", "aws", "kubernetes"}},
+		{"Golang microservices with gRPC", []string{"go", "grpc"}},
+		// "go" and "swift" as plain words are not technologies
+		{"We go the extra mile with swift delivery", nil},
+		{"React and Node.js frontend", []string{"react", "node.js"}},
+		{"C# / .NET and C++", []string{"c#", "c++"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := heuristicAttributes("", tt.text).TechStack; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tech stack = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeAttributes(t *testing.T) {
+	now := time.Now()
+	heuristic := model.JobAttributes{
+		Seniority:       model.SenioritySenior,
+		YearsExperience: intPtr(5),
+		TechStack:       []string{"go"},
+	}
+
+	tests := []struct {
+		name      string
+		extracted *ExtractedAttributes
+		want      model.JobAttributes
+	}{
+		{
+			name:      "model call failed",
+			extracted: nil,
+			want: model.JobAttributes{
+				Seniority:       model.SenioritySenior,
+				YearsExperience: intPtr(5),
+				TechStack:       []string{"go"},
+				Sources:         map[string]string{"seniority": "heuristic", "years_experience": "heuristic", "tech_stack": "heuristic"},
+			},
+		},
+		{
+			name: "model values win",
+			extracted: &ExtractedAttributes{
+				Seniority:       " Lead ",
+				RequiredSkills:  []string{"Go", "go", " SQL ", ""},
+				YearsExperience: intPtr(7),
+				VisaSponsorship: boolPtr(false),
+				TechStack:       []string{"Go", "Kafka"},
+			},
+			want: model.JobAttributes{
+				Seniority:       model.SeniorityLead,
+				RequiredSkills:  []string{"go", "sql"},
+				YearsExperience: intPtr(7),
+				VisaSponsorship: boolPtr(false),
+				TechStack:       []string{"go", "kafka"},
+				Sources: map[string]string{
+					"seniority": "llm", "required_skills": "llm", "years_experience": "llm",
+					"visa_sponsorship": "llm", "tech_stack": "llm",
+				},
+			},
+		},
+		{
+			name: "invalid model values fall back",
+			extracted: &ExtractedAttributes{
+				Seniority:       "rockstar",
+				YearsExperience: intPtr(80),
+				TechStack:       []string{strings.Repeat("x", 60)},
+			},
+			want: model.JobAttributes{
+				Seniority:       model.SenioritySenior,
+				YearsExperience: intPtr(5),
+				TechStack:       []string{"go"},
+				Sources:         map[string]string{"seniority": "heuristic", "years_experience": "heuristic", "tech_stack": "heuristic"},
+			},
+		},
+		{
+			name:      "negative years fall back",
+			extracted: &ExtractedAttributes{YearsExperience: intPtr(-1)},
+			want: model.JobAttributes{
+				Seniority:       model.SenioritySenior,
+				YearsExperience: intPtr(5),
+				TechStack:       []string{"go"},
+				Sources:         map[string]string{"seniority": "heuristic", "years_experience": "heuristic", "tech_stack": "heuristic"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeAttributes(tt.extracted, heuristic, now)
+			tt.want.ExtractedAt = &now
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeAttributes =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanTermsCapsCount(t *testing.T) {
+	terms := make([]string, 30)
+	for i := range terms {
+		terms[i] = strings.Repeat("a", i+1)
+	}
+	if got := cleanTerms(terms); len(got) != maxAttributeTerms {
+		t.Errorf("cleanTerms kept %d terms, want %d", len(got), maxAttributeTerms)
+	}
+}
@@ -5,22 +5,28 @@ import (
 	"encoding/json"
 	"log"
 	"os"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/google/uuid"
+	jobmodel "github.com/jobping/backend/internal/features/job/model"
+	"github.com/jobping/backend/internal/features/job/posted"
 	jobrepo "github.com/jobping/backend/internal/features/job/repository"
+	"github.com/jobping/backend/internal/salary"
 )
 
 type JobAnalysisService struct {
 	jobRepo      jobrepo.JobRepository
+	events       jobrepo.PipelineEventRepository
 	aiClient     AIClient
+	salaries     *salary.Normalizer
 	fanoutQueueURL string
 	sqsClient    *sqs.Client
 }
 
-func NewJobAnalysisService(jobRepo jobrepo.JobRepository, aiClient AIClient) *JobAnalysisService {
+func NewJobAnalysisService(jobRepo jobrepo.JobRepository, events jobrepo.PipelineEventRepository, aiClient AIClient, salaries *salary.Normalizer) *JobAnalysisService {
 	fanoutQueueURL := os.Getenv("USER_FANOUT_QUEUE_URL")
 	
 	var sqsClient *sqs.Client
@@ -33,7 +39,9 @@ func NewJobAnalysisService(jobRepo jobrepo.JobRepository, aiClient AIClient) *Jo
 
 	return &JobAnalysisService{
 		jobRepo:        jobRepo,
+		events:         events,
 		aiClient:       aiClient,
+		salaries:       salaries,
 		fanoutQueueURL: fanoutQueueURL,
 		sqsClient:      sqsClient,
 	}
@@ -51,6 +59,14 @@ func (s *JobAnalysisService) AnalyzeJob(ctx context.Context, jobID uuid.UUID) er
 		return nil
 	}
 
+	// Extract structured attributes once; a retried message skips this step
+	if job.Attributes.ExtractedAt == nil {
+		s.extractAttributes(ctx, job)
+	}
+	s.normalizeSalary(ctx, job)
+	s.parsePostedAt(ctx, job)
+	s.parseLocation(ctx, job)
+
 	// Check if company info is fresh (< 6 months old)
 	isFresh, err := s.jobRepo.IsCompanyInfoFresh(ctx, jobID)
 	if err != nil {
@@ -60,18 +76,31 @@ func (s *JobAnalysisService) AnalyzeJob(ctx context.Context, jobID uuid.UUID) er
 
 	if isFresh && job.CompanyInfo != nil {
 		log.Printf("Company info is fresh for job %s, skipping analysis", jobID)
+		s.events.RecordBestEffort(ctx, jobmodel.PipelineEvent{
+			JobID:   jobID,
+			Stage:   jobmodel.PipelineStageCompanyResearch,
+			Outcome: jobmodel.PipelineOutcomeSkippedFresh,
+			Details: map[string]interface{}{"company_info_updated_at": job.CompanyInfoUpdatedAt},
+		})
 	} else {
 		// Research company using ChatGPT
 		log.Printf("Researching company for job %s: %s", jobID, job.Company)
 		companyInfo, err := s.aiClient.ResearchCompany(ctx, job.Company, job.Title, job.Description)
 		if err != nil {
+			s.recordFailure(ctx, jobID, jobmodel.PipelineStageCompanyResearch, err)
 			return err
 		} else {
 			// Save company info
 			if err := s.jobRepo.UpdateCompanyInfo(ctx, jobID, companyInfo); err != nil {
 				log.Printf("Failed to save company info: %v", err)
+				s.recordFailure(ctx, jobID, jobmodel.PipelineStageCompanyResearch, err)
 			} else {
 				log.Printf("Saved company info for job %s", jobID)
+				s.events.RecordBestEffort(ctx, jobmodel.PipelineEvent{
+					JobID:   jobID,
+					Stage:   jobmodel.PipelineStageCompanyResearch,
+					Outcome: jobmodel.PipelineOutcomeResearched,
+				})
 			}
 		}
 	}
@@ -79,12 +108,84 @@ func (s *JobAnalysisService) AnalyzeJob(ctx context.Context, jobID uuid.UUID) er
 	// Enqueue to user-fanout-queue
 	if err := s.enqueueToFanout(ctx, jobID); err != nil {
 		log.Printf("Failed to enqueue to fanout queue: %v", err)
+		s.recordFailure(ctx, jobID, jobmodel.PipelineStageFanout, err)
 		return err
 	}
 
 	return nil
 }
 
+// extractAttributes asks the AI client for the job's structured attributes, validates them and fills
+// the gaps with regex heuristics. Failures are logged and never block the pipeline.
+func (s *JobAnalysisService) extractAttributes(ctx context.Context, job *jobmodel.Job) {
+	extracted, err := s.aiClient.ExtractAttributes(ctx, job.Title, job.Description)
+	if err != nil {
+		log.Printf("AI attribute extraction failed for job %s, using heuristics only: %v", job.ID, err)
+		extracted = nil
+	}
+
+	attrs := mergeAttributes(extracted, heuristicAttributes(job.Title, job.Description), time.Now())
+	if err := s.jobRepo.UpdateAttributes(ctx, job.ID, attrs); err != nil {
+		log.Printf("Failed to save attributes for job %s: %v", job.ID, err)
+		return
+	}
+	job.Attributes = attrs
+	log.Printf("Saved attributes for job %s: seniority=%q, %d required skills", job.ID, attrs.Seniority, len(attrs.RequiredSkills))
+}
+
+// normalizeSalary stores the salary as a yearly base-currency amount, so it can be compared across
+// jobs. It runs on every analysis, so a retried message picks up changed rates.
+func (s *JobAnalysisService) normalizeSalary(ctx context.Context, job *jobmodel.Job) {
+	if job.MinSalary == nil && job.MaxSalary == nil {
+		return
+	}
+
+	minYearly := s.salaries.Annualize(job.MinSalary, job.SalaryCurrency, job.SalaryInterval)
+	maxYearly := s.salaries.Annualize(job.MaxSalary, job.SalaryCurrency, job.SalaryInterval)
+	if minYearly == nil && maxYearly == nil {
+		log.Printf("Cannot normalize salary of job %s: currency %q, interval %q", job.ID, job.SalaryCurrency, job.SalaryInterval)
+	}
+	if err := s.jobRepo.UpdateNormalizedSalary(ctx, job.ID, minYearly, maxYearly); err != nil {
+		log.Printf("Failed to save normalized salary for job %s: %v", job.ID, err)
+		return
+	}
+	job.MinSalaryNormalized, job.MaxSalaryNormalized = minYearly, maxYearly
+}
+
+// parsePostedAt turns the scraped date_posted string into posted_at. Relative dates count back
+// from when the job was ingested, so re-parsing gives the same result.
+func (s *JobAnalysisService) parsePostedAt(ctx context.Context, job *jobmodel.Job) {
+	postedAt := posted.Parse(job.DatePosted, job.CreatedAt)
+	if postedAt.Equal(job.PostedAt) {
+		return
+	}
+	if err := s.jobRepo.UpdatePostedAt(ctx, job.ID, postedAt); err != nil {
+		log.Printf("Failed to save posted_at for job %s: %v", job.ID, err)
+		return
+	}
+	job.PostedAt = postedAt
+}
+
+// parseLocation parses and geocodes the scraped location. It runs on every analysis, so jobs pick
+// up gazetteer additions when they are re-analyzed.
+func (s *JobAnalysisService) parseLocation(ctx context.Context, job *jobmodel.Job) {
+	place := job.ParsePlace()
+	if err := s.jobRepo.UpdatePlace(ctx, job.ID, place); err != nil {
+		log.Printf("Failed to save parsed location for job %s: %v", job.ID, err)
+		return
+	}
+	job.Place = place
+}
+
+func (s *JobAnalysisService) recordFailure(ctx context.Context, jobID uuid.UUID, stage string, err error) {
+	s.events.RecordBestEffort(ctx, jobmodel.PipelineEvent{
+		JobID:   jobID,
+		Stage:   stage,
+		Outcome: jobmodel.PipelineOutcomeFailed,
+		Error:   err.Error(),
+	})
+}
+
 func (s *JobAnalysisService) enqueueToFanout(ctx context.Context, jobID uuid.UUID) error {
 	if s.sqsClient == nil || s.fanoutQueueURL == "" {
 		log.Printf("SQS not configured, skipping fanout enqueue")
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"github.com/google/uuid"
+	notificationrepo "github.com/jobping/backend/internal/features/notification/repository"
+)
+
+type NotificationResponse struct {
+	ID            uuid.UUID              `json:"id"`
+	UserID        uuid.UUID              `json:"user_id"`
+	JobID         uuid.UUID              `json:"job_id"`
+	MatchID       uuid.UUID              `json:"match_id"`
+	JobTitle      string                 `json:"job_title"`
+	Company       string                 `json:"company"`
+	JobURL        string                 `json:"job_url"`
+	MatchingScore int                    `json:"matching_score"`
+	AIAnalysis    map[string]interface{} `json:"ai_analysis"`
+	Delivery      string                 `json:"delivery"`
+	Channels      []string               `json:"channels"`
+	Read          bool                   `json:"read"`
+	ReadAt        *string                `json:"read_at,omitempty"`
+	CreatedAt     string                 `json:"created_at"`
+}
+
+type NotificationsResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+}
+
+type UnreadCountResponse struct {
+	Unread int `json:"unread"`
+}
+
+type SaveTemplateRequest struct {
+	Subject  string `json:"subject"`
+	Body     string `json:"body"`
+	TextBody string `json:"text_body"`
+}
+
+type TemplateResponse struct {
+	Channel   string `json:"channel"`
+	Subject   string `json:"subject,omitempty"`
+	Body      string `json:"body,omitempty"`
+	TextBody  string `json:"text_body,omitempty"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type TemplatesResponse struct {
+	Templates []TemplateResponse `json:"templates"`
+}
+
+// PreviewRequest renders a match for a channel; Template optionally previews an unsaved override
+type PreviewRequest struct {
+	MatchID  uuid.UUID            `json:"match_id"`
+	Channel  string               `json:"channel"`
+	Template *SaveTemplateRequest `json:"template,omitempty"`
+}
+
+func ToTemplateResponse(t notificationrepo.NotificationTemplate) TemplateResponse {
+	return TemplateResponse{
+		Channel:   t.Channel,
+		Subject:   t.Subject,
+		Body:      t.Body,
+		TextBody:  t.TextBody,
+		UpdatedAt: t.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+func ToNotificationResponse(n notificationrepo.Notification) NotificationResponse {
+	response := NotificationResponse{
+		ID:            n.ID,
+		UserID:        n.UserID,
+		JobID:         n.JobID,
+		MatchID:       n.MatchID,
+		JobTitle:      n.JobTitle,
+		Company:       n.Company,
+		JobURL:        n.JobURL,
+		MatchingScore: n.MatchingScore,
+		AIAnalysis:    n.AIAnalysis,
+		Delivery:      n.Delivery,
+		Channels:      n.Channels,
+		Read:          n.ReadAt != nil,
+		CreatedAt:     n.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if n.ReadAt != nil {
+		readAt := n.ReadAt.Format("2006-01-02T15:04:05Z")
+		response.ReadAt = &readAt
+	}
+	return response
+}
+
+func ToNotificationsResponse(notifications []notificationrepo.Notification) NotificationsResponse {
+	response := NotificationsResponse{Notifications: make([]NotificationResponse, len(notifications))}
+	for i, n := range notifications {
+		response.Notifications[i] = ToNotificationResponse(n)
+	}
+	return response
+}
@@ -2,46 +2,315 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/notification/notificationerr"
+	"github.com/jobping/backend/internal/features/notification/render"
 	"github.com/jobping/backend/internal/features/notification/service"
+	userhandler "github.com/jobping/backend/internal/features/user/handler"
 )
 
 type HTTPHandler struct {
-	service *service.NotificationService
+	service         *service.NotificationService
+	templateService *service.TemplateService
 }
 
-func NewHTTPHandler(svc *service.NotificationService) *HTTPHandler {
-	return &HTTPHandler{service: svc}
+func NewHTTPHandler(svc *service.NotificationService, templateSvc *service.TemplateService) *HTTPHandler {
+	return &HTTPHandler{
+		service:         svc,
+		templateService: templateSvc,
+	}
 }
 
-// GetNotifications returns notifications (for testing)
+// maxTemplateSize bounds user-supplied templates (bytes per field)
+const maxTemplateSize = 16 * 1024
+
+// GetNotifications returns the caller's notifications. ?unread=true limits to unread ones.
 func (h *HTTPHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userhandler.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+	notifications, err := h.service.GetNotifications(r.Context(), userID, unreadOnly, parseLimit(r))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to fetch notifications")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ToNotificationsResponse(notifications))
+}
+
+// GetUnreadCount returns the number of unread notifications for the caller
+func (h *HTTPHandler) GetUnreadCount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userhandler.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	count, err := h.service.CountUnread(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, UnreadCountResponse{Unread: count})
+}
+
+// MarkRead marks one of the caller's notifications as read
+func (h *HTTPHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userhandler.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	notificationID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid notification id")
+		return
+	}
+
+	if err := h.service.MarkRead(r.Context(), userID, notificationID); err != nil {
+		if errors.Is(err, notificationerr.ErrNotificationNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MarkAllRead marks all of the caller's notifications as read
+func (h *HTTPHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userhandler.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	updated, err := h.service.MarkAllRead(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]int64{"updated": updated})
+}
+
+// DeleteNotification deletes one of the caller's notifications
+func (h *HTTPHandler) DeleteNotification(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userhandler.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	notificationID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid notification id")
+		return
+	}
+
+	if err := h.service.DeleteNotification(r.Context(), userID, notificationID); err != nil {
+		if errors.Is(err, notificationerr.ErrNotificationNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AdminGetNotifications returns notifications across users, optionally filtered by ?user_id=
+func (h *HTTPHandler) AdminGetNotifications(w http.ResponseWriter, r *http.Request) {
+	var userID *uuid.UUID
+	if uidStr := r.URL.Query().Get("user_id"); uidStr != "" {
+		uid, err := uuid.Parse(uidStr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid user_id")
+			return
+		}
+		userID = &uid
+	}
+
+	notifications, err := h.service.GetAllNotifications(r.Context(), userID, parseLimit(r))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to fetch notifications")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ToNotificationsResponse(notifications))
+}
+
+func parseLimit(r *http.Request) int {
 	limit := 20
 	if l := r.URL.Query().Get("limit"); l != "" {
 		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
 			limit = parsed
 		}
 	}
+	if limit > 100 {
+		limit = 100
+	}
+	return limit
+}
 
-	var userID *uuid.UUID
-	if uidStr := r.URL.Query().Get("user_id"); uidStr != "" {
-		if uid, err := uuid.Parse(uidStr); err == nil {
-			userID = &uid
+// GetTemplates lists the caller's template overrides
+func (h *HTTPHandler) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userhandler.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	templates, err := h.templateService.GetTemplates(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	response := TemplatesResponse{Templates: make([]TemplateResponse, len(templates))}
+	for i, t := range templates {
+		response.Templates[i] = ToTemplateResponse(t)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// SaveTemplate creates or replaces the caller's override for a channel
+func (h *HTTPHandler) SaveTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userhandler.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	channel, err := render.ParseChannel(chi.URLParam(r, "channel"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req SaveTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Subject == "" && req.Body == "" && req.TextBody == "" {
+		writeError(w, http.StatusBadRequest, "at least one of subject, body or text_body is required")
+		return
+	}
+	if !templateSizeOK(req) {
+		writeError(w, http.StatusBadRequest, "template too large")
+		return
+	}
+
+	tmpl, err := h.templateService.SaveTemplate(r.Context(), userID, channel, toOverride(req))
+	if err != nil {
+		if errors.Is(err, notificationerr.ErrInvalidTemplate) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ToTemplateResponse(*tmpl))
+}
+
+// DeleteTemplate reverts a channel to the default template
+func (h *HTTPHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userhandler.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	channel, err := render.ParseChannel(chi.URLParam(r, "channel"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(r.Context(), userID, channel); err != nil {
+		if errors.Is(err, notificationerr.ErrTemplateNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PreviewNotification renders a notification for one of the caller's matches without sending it
+func (h *HTTPHandler) PreviewNotification(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userhandler.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req PreviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.MatchID == uuid.Nil {
+		writeError(w, http.StatusBadRequest, "match_id is required")
+		return
+	}
+
+	channel, err := render.ParseChannel(req.Channel)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var draft *render.Override
+	if req.Template != nil {
+		if !templateSizeOK(*req.Template) {
+			writeError(w, http.StatusBadRequest, "template too large")
+			return
 		}
+		override := toOverride(*req.Template)
+		draft = &override
 	}
 
-	notifications, err := h.service.GetNotifications(r.Context(), userID, limit)
+	message, err := h.templateService.Preview(r.Context(), userID, req.MatchID, channel, draft)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to fetch notifications")
+		switch {
+		case errors.Is(err, notificationerr.ErrMatchNotFound):
+			writeError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, notificationerr.ErrInvalidTemplate):
+			writeError(w, http.StatusBadRequest, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "internal server error")
+		}
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"notifications": notifications,
-	})
+	writeJSON(w, http.StatusOK, message)
+}
+
+func toOverride(req SaveTemplateRequest) render.Override {
+	return render.Override{Subject: req.Subject, Body: req.Body, TextBody: req.TextBody}
+}
+
+func templateSizeOK(req SaveTemplateRequest) bool {
+	return len(req.Subject) <= maxTemplateSize && len(req.Body) <= maxTemplateSize && len(req.TextBody) <= maxTemplateSize
 }
 
 func writeJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -55,5 +324,3 @@ func writeError(w http.ResponseWriter, status int, message string) {
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(map[string]interface{}{"code": status, "message": message})
 }
-
-
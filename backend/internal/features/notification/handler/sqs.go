@@ -18,20 +18,30 @@ func NewSQSHandler(svc *service.NotificationService) *SQSHandler {
 	return &SQSHandler{service: svc}
 }
 
-// HandleSQSEvent processes SQS messages containing job_id and user_id
+// HandleSQSEvent processes SQS messages containing job_id, user_id and saved_search_id.
+// A message of {"type": "digest"} (sent on a schedule) delivers deferred notifications instead.
 func (h *SQSHandler) HandleSQSEvent(ctx context.Context, sqsEvent events.SQSEvent) error {
 	for _, record := range sqsEvent.Records {
 		log.Printf("Processing SQS message: %s", record.MessageId)
 
 		var message struct {
-			JobID  string `json:"job_id"`
-			UserID string `json:"user_id"`
+			Type          string `json:"type"`
+			JobID         string `json:"job_id"`
+			UserID        string `json:"user_id"`
+			SavedSearchID string `json:"saved_search_id"`
 		}
 		if err := json.Unmarshal([]byte(record.Body), &message); err != nil {
 			log.Printf("Failed to parse SQS message: %v", err)
 			continue
 		}
 
+		if message.Type == "digest" {
+			if err := h.service.SendDigests(ctx); err != nil {
+				log.Printf("Failed to send digests: %v", err)
+			}
+			continue
+		}
+
 		jobID, err := uuid.Parse(message.JobID)
 		if err != nil {
 			log.Printf("Invalid job_id in message: %v", err)
@@ -44,7 +54,17 @@ func (h *SQSHandler) HandleSQSEvent(ctx context.Context, sqsEvent events.SQSEven
 			continue
 		}
 
-		if err := h.service.SendNotification(ctx, jobID, userID); err != nil {
+		// Messages queued before saved searches have no saved_search_id
+		var searchID uuid.UUID
+		if message.SavedSearchID != "" {
+			searchID, err = uuid.Parse(message.SavedSearchID)
+			if err != nil {
+				log.Printf("Invalid saved_search_id in message: %v", err)
+				continue
+			}
+		}
+
+		if err := h.service.SendNotification(ctx, jobID, userID, searchID); err != nil {
 			log.Printf("Failed to send notification: %v", err)
 			continue
 		}
@@ -54,5 +74,3 @@ func (h *SQSHandler) HandleSQSEvent(ctx context.Context, sqsEvent events.SQSEven
 
 	return nil
 }
-
-
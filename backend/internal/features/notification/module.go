@@ -0,0 +1,37 @@
+package notification
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jobping/backend/internal/features/notification/handler"
+	usermodel "github.com/jobping/backend/internal/features/user/model"
+)
+
+// RegisterRoutes registers notification HTTP routes. All routes require authentication;
+// requireAdmin additionally guards the cross-user admin view, and requireScope limits
+// personal API tokens to the notification scopes.
+func RegisterRoutes(r chi.Router, notificationHandler *handler.HTTPHandler, authenticate, requireAdmin func(http.Handler) http.Handler, requireScope func(scope string) func(http.Handler) http.Handler) {
+	r.Group(func(r chi.Router) {
+		r.Use(authenticate)
+		read := r.With(requireScope(usermodel.ScopeReadNotifications))
+		write := r.With(requireScope(usermodel.ScopeWriteNotifications))
+
+		// Caller's notifications (GET /notifications kept for existing clients)
+		read.Get("/notifications", notificationHandler.GetNotifications)
+		read.Get("/users/me/notifications", notificationHandler.GetNotifications)
+		read.Get("/users/me/notifications/unread-count", notificationHandler.GetUnreadCount)
+		write.Post("/users/me/notifications/read-all", notificationHandler.MarkAllRead)
+		write.Post("/users/me/notifications/{id}/read", notificationHandler.MarkRead)
+		write.Delete("/users/me/notifications/{id}", notificationHandler.DeleteNotification)
+
+		// Templates
+		read.Get("/users/me/notification-templates", notificationHandler.GetTemplates)
+		write.Put("/users/me/notification-templates/{channel}", notificationHandler.SaveTemplate)
+		write.Delete("/users/me/notification-templates/{channel}", notificationHandler.DeleteTemplate)
+		read.Post("/users/me/notifications/preview", notificationHandler.PreviewNotification)
+
+		// Admin
+		r.With(requireAdmin).Get("/admin/notifications", notificationHandler.AdminGetNotifications)
+	})
+}
@@ -0,0 +1,11 @@
+package notificationerr
+
+import "errors"
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+	ErrMatchNotFound        = errors.New("match not found")
+	ErrUnknownChannel       = errors.New("unknown notification channel")
+	ErrInvalidTemplate      = errors.New("invalid notification template")
+	ErrTemplateNotFound     = errors.New("notification template not found")
+)
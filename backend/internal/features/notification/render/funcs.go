@@ -0,0 +1,97 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// funcs are available to default and user-supplied templates
+var funcs = map[string]interface{}{
+	"json":       toJSON,
+	"scoreBar":   scoreBar,
+	"scoreColor": scoreColor,
+	"scoreHex":   scoreHex,
+	"bullets":    bullets,
+	"limit":      limit,
+	"truncate":   truncate,
+}
+
+// toJSON encodes v as a JSON literal so templates can safely embed user content in webhook payloads
+func toJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// scoreBar draws a 10-segment bar, e.g. 70 -> "███████░░░"
+func scoreBar(score int) string {
+	filled := clampScore(score) / 10
+	return strings.Repeat("█", filled) + strings.Repeat("░", 10-filled)
+}
+
+// scoreColor returns the Discord embed color (decimal RGB) for a score
+func scoreColor(score int) int {
+	switch s := clampScore(score); {
+	case s >= 85:
+		return 0x2ECC71 // green
+	case s >= 70:
+		return 0xF1C40F // yellow
+	case s >= 50:
+		return 0xE67E22 // orange
+	default:
+		return 0xE74C3C // red
+	}
+}
+
+// scoreHex returns the score color as a CSS hex string
+func scoreHex(score int) string {
+	return fmt.Sprintf("#%06x", scoreColor(score))
+}
+
+// bullets formats items as a markdown list, or an em dash when empty (Discord rejects empty fields)
+func bullets(items []string) string {
+	if len(items) == 0 {
+		return "—"
+	}
+	lines := make([]string, len(items))
+	for i, item := range items {
+		lines[i] = "• " + item
+	}
+	return strings.Join(lines, "\n")
+}
+
+// limit returns the first n items; a negative n is treated as 0
+func limit(items []string, n int) []string {
+	if n <= 0 {
+		return nil
+	}
+	if len(items) <= n {
+		return items
+	}
+	return items[:n]
+}
+
+// truncate cuts s to maxLen runes, ending with an ellipsis when cut; a maxLen of 0 or less gives ""
+func truncate(s string, maxLen int) string {
+	if maxLen <= 0 {
+		return ""
+	}
+	r := []rune(s)
+	if len(r) <= maxLen {
+		return s
+	}
+	return string(r[:maxLen-1]) + "…"
+}
+
+func clampScore(score int) int {
+	if score < 0 {
+		return 0
+	}
+	if score > 100 {
+		return 100
+	}
+	return score
+}
@@ -0,0 +1,92 @@
+package render
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScoreFuncs(t *testing.T) {
+	tests := []struct {
+		score int
+		bar   string
+		color int
+		hex   string
+	}{
+		{0, "░░░░░░░░░░", 0xE74C3C, "#e74c3c"},
+		{49, "████░░░░░░", 0xE74C3C, "#e74c3c"},
+		{50, "█████░░░░░", 0xE67E22, "#e67e22"},
+		{70, "███████░░░", 0xF1C40F, "#f1c40f"},
+		{85, "████████░░", 0x2ECC71, "#2ecc71"},
+		{100, "██████████", 0x2ECC71, "#2ecc71"},
+		// Out of range scores are clamped
+		{-5, "░░░░░░░░░░", 0xE74C3C, "#e74c3c"},
+		{140, "██████████", 0x2ECC71, "#2ecc71"},
+	}
+
+	for _, tt := range tests {
+		if got := scoreBar(tt.score); got != tt.bar {
+			t.Errorf("scoreBar(%d) = %q, want %q", tt.score, got, tt.bar)
+		}
+		if got := scoreColor(tt.score); got != tt.color {
+			t.Errorf("scoreColor(%d) = %#x, want %#x", tt.score, got, tt.color)
+		}
+		if got := scoreHex(tt.score); got != tt.hex {
+			t.Errorf("scoreHex(%d) = %q, want %q", tt.score, got, tt.hex)
+		}
+	}
+}
+
+func TestLimit(t *testing.T) {
+	items := []string{"a", "b", "c"}
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{2, []string{"a", "b"}},
+		{3, items},
+		{10, items},
+		{0, nil},
+		{-1, nil},
+	}
+
+	for _, tt := range tests {
+		if got := limit(items, tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("limit(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s      string
+		maxLen int
+		want   string
+	}{
+		{"Go Engineer", 20, "Go Engineer"},
+		{"Go Engineer", 11, "Go Engineer"},
+		{"Go Engineer", 5, "Go E…"},
+		{"Go Engineer", 1, "…"},
+		{"Go Engineer", 0, ""},
+		{"Go Engineer", -3, ""},
+		// Cuts on runes, not bytes
+		{"Müller GmbH", 3, "Mü…"},
+	}
+
+	for _, tt := range tests {
+		if got := truncate(tt.s, tt.maxLen); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.maxLen, got, tt.want)
+		}
+	}
+}
+
+func TestBulletsAndJSON(t *testing.T) {
+	if got := bullets(nil); got != "—" {
+		t.Errorf("bullets(nil) = %q, want an em dash", got)
+	}
+	if got := bullets([]string{"Remote", "Go"}); got != "• Remote\n• Go" {
+		t.Errorf("bullets = %q", got)
+	}
+	if got, err := toJSON(`say "hi"`); err != nil || got != `"say \"hi\""` {
+		t.Errorf("toJSON = %s, %v", got, err)
+	}
+}
@@ -0,0 +1,258 @@
+package render
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/jobping/backend/internal/features/notification/notificationerr"
+)
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// maxOutputSize caps each rendered part, so a user template cannot build huge messages
+const maxOutputSize = 64 << 10
+
+var errOutputTooLarge = fmt.Errorf("output is larger than %d KiB", maxOutputSize>>10)
+
+// Channel is a notification delivery channel with its own message format
+type Channel string
+
+const (
+	ChannelDiscord Channel = "discord"
+	ChannelSlack   Channel = "slack"
+	ChannelEmail   Channel = "email"
+)
+
+// Channels lists every supported channel
+var Channels = []Channel{ChannelDiscord, ChannelSlack, ChannelEmail}
+
+// ParseChannel validates a channel name from user input
+func ParseChannel(s string) (Channel, error) {
+	for _, c := range Channels {
+		if string(c) == s {
+			return c, nil
+		}
+	}
+	return "", notificationerr.ErrUnknownChannel
+}
+
+// Data is the view model passed to every notification template
+type Data struct {
+	Username    string
+	JobTitle    string
+	Company     string
+	JobURL      string
+	Score       int
+	Explanation string
+	Pros        []string
+	Cons        []string
+}
+
+// Override holds a user's replacement templates for one channel. Empty fields fall back to the default.
+// Body is the Discord/Slack JSON payload or the HTML email body; Subject and TextBody only apply to email.
+type Override struct {
+	Subject  string
+	Body     string
+	TextBody string
+}
+
+// Message is a rendered notification ready to hand to a channel sender
+type Message struct {
+	Channel     Channel `json:"channel"`
+	ContentType string  `json:"content_type"`
+	Subject     string  `json:"subject,omitempty"`
+	Body        string  `json:"body"`
+	TextBody    string  `json:"text_body,omitempty"`
+}
+
+// Renderer renders notifications with the embedded default templates and optional per-user overrides
+type Renderer struct {
+	discord      *texttemplate.Template
+	slack        *texttemplate.Template
+	emailSubject *texttemplate.Template
+	emailHTML    *htmltemplate.Template
+	emailText    *texttemplate.Template
+}
+
+// NewRenderer parses the embedded default templates
+func NewRenderer() (*Renderer, error) {
+	r := &Renderer{}
+	var err error
+
+	if r.discord, err = parseTextFile("discord.json.tmpl"); err != nil {
+		return nil, err
+	}
+	if r.slack, err = parseTextFile("slack.json.tmpl"); err != nil {
+		return nil, err
+	}
+	if r.emailSubject, err = parseTextFile("email_subject.txt.tmpl"); err != nil {
+		return nil, err
+	}
+	if r.emailText, err = parseTextFile("email.txt.tmpl"); err != nil {
+		return nil, err
+	}
+	content, err := defaultTemplates.ReadFile("templates/email.html.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	if r.emailHTML, err = parseHTML("email.html.tmpl", string(content)); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Render renders data for channel, using override templates where set
+func (r *Renderer) Render(channel Channel, data Data, override *Override) (*Message, error) {
+	if override == nil {
+		override = &Override{}
+	}
+
+	switch channel {
+	case ChannelDiscord:
+		body, err := renderJSON(r.discord, override.Body, data)
+		if err != nil {
+			return nil, err
+		}
+		return &Message{Channel: channel, ContentType: "application/json", Body: body}, nil
+
+	case ChannelSlack:
+		body, err := renderJSON(r.slack, override.Body, data)
+		if err != nil {
+			return nil, err
+		}
+		return &Message{Channel: channel, ContentType: "application/json", Body: body}, nil
+
+	case ChannelEmail:
+		subject, err := renderText(r.emailSubject, override.Subject, data)
+		if err != nil {
+			return nil, err
+		}
+		html, err := renderHTML(r.emailHTML, override.Body, data)
+		if err != nil {
+			return nil, err
+		}
+		text, err := renderText(r.emailText, override.TextBody, data)
+		if err != nil {
+			return nil, err
+		}
+		return &Message{
+			Channel:     channel,
+			ContentType: "text/html",
+			Subject:     strings.TrimSpace(subject),
+			Body:        html,
+			TextBody:    text,
+		}, nil
+	}
+
+	return nil, notificationerr.ErrUnknownChannel
+}
+
+// Validate checks that an override parses and renders valid output against sample data
+func (r *Renderer) Validate(channel Channel, override Override) error {
+	_, err := r.Render(channel, sampleData, &override)
+	return err
+}
+
+var sampleData = Data{
+	Username:    "jobseeker",
+	JobTitle:    "Senior Backend Engineer",
+	Company:     "Example Corp",
+	JobURL:      "https://example.com/jobs/123",
+	Score:       82,
+	Explanation: "Strong match on Go and distributed systems experience.",
+	Pros:        []string{"Remote friendly", "Go-heavy stack"},
+	Cons:        []string{"On-call rotation"},
+}
+
+func parseTextFile(name string) (*texttemplate.Template, error) {
+	content, err := defaultTemplates.ReadFile("templates/" + name)
+	if err != nil {
+		return nil, err
+	}
+	return parseText(name, string(content))
+}
+
+func parseText(name, content string) (*texttemplate.Template, error) {
+	tmpl, err := texttemplate.New(name).Funcs(texttemplate.FuncMap(funcs)).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", notificationerr.ErrInvalidTemplate, err)
+	}
+	return tmpl, nil
+}
+
+func parseHTML(name, content string) (*htmltemplate.Template, error) {
+	tmpl, err := htmltemplate.New(name).Funcs(htmltemplate.FuncMap(funcs)).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", notificationerr.ErrInvalidTemplate, err)
+	}
+	return tmpl, nil
+}
+
+func renderText(def *texttemplate.Template, override string, data Data) (string, error) {
+	tmpl := def
+	if override != "" {
+		var err error
+		if tmpl, err = parseText("override", override); err != nil {
+			return "", err
+		}
+	}
+
+	var buf cappedBuffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("%w: %v", notificationerr.ErrInvalidTemplate, err)
+	}
+	return buf.String(), nil
+}
+
+func renderHTML(def *htmltemplate.Template, override string, data Data) (string, error) {
+	tmpl := def
+	if override != "" {
+		var err error
+		if tmpl, err = parseHTML("override", override); err != nil {
+			return "", err
+		}
+	}
+
+	var buf cappedBuffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("%w: %v", notificationerr.ErrInvalidTemplate, err)
+	}
+	return buf.String(), nil
+}
+
+// renderJSON renders a webhook payload and checks the result is valid JSON
+func renderJSON(def *texttemplate.Template, override string, data Data) (string, error) {
+	out, err := renderText(def, override, data)
+	if err != nil {
+		return "", err
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, []byte(out)); err != nil {
+		return "", fmt.Errorf("%w: output is not valid JSON: %v", notificationerr.ErrInvalidTemplate, err)
+	}
+	return compact.String(), nil
+}
+
+// cappedBuffer collects template output and fails writes past maxOutputSize, which stops the template
+type cappedBuffer struct {
+	buf bytes.Buffer
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if b.buf.Len()+len(p) > maxOutputSize {
+		return 0, errOutputTooLarge
+	}
+	return b.buf.Write(p)
+}
+
+func (b *cappedBuffer) String() string {
+	return b.buf.String()
+}
@@ -0,0 +1,79 @@
+package render
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jobping/backend/internal/features/notification/notificationerr"
+)
+
+func TestRenderDefaultTemplates(t *testing.T) {
+	r, err := NewRenderer()
+	if err != nil {
+		t.Fatalf("NewRenderer: %v", err)
+	}
+	data := sampleData
+	data.JobTitle = `Engineer "Go" <script>`
+
+	for _, channel := range Channels {
+		t.Run(string(channel), func(t *testing.T) {
+			msg, err := r.Render(channel, data, nil)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			switch channel {
+			case ChannelDiscord, ChannelSlack:
+				if !json.Valid([]byte(msg.Body)) {
+					t.Errorf("body is not valid JSON: %s", msg.Body)
+				}
+				if !strings.Contains(msg.Body, data.Company) {
+					t.Errorf("body does not mention the company: %s", msg.Body)
+				}
+			case ChannelEmail:
+				if !strings.Contains(msg.Subject, data.Company) || msg.TextBody == "" {
+					t.Errorf("subject %q, text body %q", msg.Subject, msg.TextBody)
+				}
+				if strings.Contains(msg.Body, "<script>") {
+					t.Error("HTML body does not escape the job title")
+				}
+			}
+		})
+	}
+}
+
+func TestRenderOverrides(t *testing.T) {
+	r, err := NewRenderer()
+	if err != nil {
+		t.Fatalf("NewRenderer: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		channel  Channel
+		override Override
+		valid    bool
+	}{
+		{"json payload", ChannelDiscord, Override{Body: `{"content": {{json .JobTitle}}}`}, true},
+		{"clamped helpers", ChannelSlack, Override{Body: `{"text": {{json (truncate .JobTitle -1)}}, "n": {{len (limit .Pros -2)}}}`}, true},
+		{"not json", ChannelDiscord, Override{Body: `content: {{.JobTitle}}`}, false},
+		{"parse error", ChannelSlack, Override{Body: `{"text": {{.JobTitle}`}, false},
+		{"unknown field", ChannelEmail, Override{Subject: `{{.Salary}}`}, false},
+		// Output past maxOutputSize stops the template
+		{"huge output", ChannelEmail, Override{TextBody: `{{printf "%070000d" 0}}`}, false},
+		{"huge JSON", ChannelDiscord, Override{Body: `{"content": "{{printf "%070000d" 0}}"}`}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.Validate(tt.channel, tt.override)
+			if tt.valid && err != nil {
+				t.Errorf("Validate: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, notificationerr.ErrInvalidTemplate) {
+				t.Errorf("err = %v, want ErrInvalidTemplate", err)
+			}
+		})
+	}
+}
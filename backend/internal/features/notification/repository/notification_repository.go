@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
+	eventrepo "github.com/jobping/backend/internal/features/event/repository"
 )
 
 type Notification struct {
@@ -18,13 +19,30 @@ type Notification struct {
 	JobURL        string
 	MatchingScore int
 	AIAnalysis    map[string]interface{}
-	CreatedAt     time.Time
+	Delivery      string
+	// Channels the notification is for, from the saved search that matched; empty means in-app only
+	Channels  []string
+	ReadAt    *time.Time
+	CreatedAt time.Time
 }
 
+// Delivery modes recorded on a notification
+const (
+	DeliveryInstant = "instant"
+	DeliveryDigest  = "digest"
+)
+
 type NotificationRepository interface {
 	Create(ctx context.Context, notification *Notification) error
-	GetByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]Notification, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]Notification, error)
 	GetAll(ctx context.Context, limit int) ([]Notification, error)
+	// CreateWithinLimit creates the notification unless the user was already sent limit instant
+	// notifications since the given time, deleted ones included, and reports whether it did
+	CreateWithinLimit(ctx context.Context, notification *Notification, since time.Time, limit int) (bool, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+	MarkRead(ctx context.Context, userID, id uuid.UUID) (bool, error)
+	MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) (bool, error)
 }
 
 type postgresNotificationRepository struct {
@@ -36,32 +54,107 @@ func NewNotificationRepository(db *pgxpool.Pool) NotificationRepository {
 }
 
 func (r *postgresNotificationRepository) Create(ctx context.Context, notification *Notification) error {
+	_, err := r.create(ctx, notification, time.Time{}, 0)
+	return err
+}
+
+// CreateWithinLimit counts and inserts under a per-user advisory lock, so notification workers
+// handling the same user at once cannot both pass the check
+func (r *postgresNotificationRepository) CreateWithinLimit(ctx context.Context, notification *Notification, since time.Time, limit int) (bool, error) {
+	return r.create(ctx, notification, since, limit)
+}
+
+func (r *postgresNotificationRepository) create(ctx context.Context, notification *Notification, since time.Time, limit int) (bool, error) {
 	query := `
-		INSERT INTO notifications (id, user_id, job_id, match_id, job_title, company, job_url, matching_score, ai_analysis, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO notifications (id, user_id, job_id, match_id, job_title, company, job_url, matching_score, ai_analysis, delivery, channels, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
-	_, err := r.db.Exec(ctx, query,
+	delivery := notification.Delivery
+	if delivery == "" {
+		delivery = DeliveryInstant
+	}
+	channels := notification.Channels
+	if channels == nil {
+		channels = []string{}
+	}
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	if limit > 0 {
+		// Held until commit; the lock key is shared by every worker for this user
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtextextended('notifications:' || $1::text, 0))`, notification.UserID); err != nil {
+			return false, err
+		}
+		// Counted from the delivery log, not notifications, which users can delete
+		if _, err := tx.Exec(ctx, `DELETE FROM notification_deliveries WHERE user_id = $1 AND created_at < $2`,
+			notification.UserID, since); err != nil {
+			return false, err
+		}
+		var count int
+		if err := tx.QueryRow(ctx, `
+			SELECT COUNT(*)
+			FROM notification_deliveries
+			WHERE user_id = $1 AND created_at >= $2
+		`, notification.UserID, since).Scan(&count); err != nil {
+			return false, err
+		}
+		if count >= limit {
+			return false, nil
+		}
+	}
+
+	if _, err := tx.Exec(ctx, query,
 		notification.ID, notification.UserID, notification.JobID, notification.MatchID,
 		notification.JobTitle, notification.Company, notification.JobURL,
-		notification.MatchingScore, notification.AIAnalysis, notification.CreatedAt,
-	)
-	return err
+		notification.MatchingScore, notification.AIAnalysis, delivery, channels, notification.CreatedAt,
+	); err != nil {
+		return false, err
+	}
+	if delivery == DeliveryInstant {
+		if _, err := tx.Exec(ctx, `INSERT INTO notification_deliveries (user_id, created_at) VALUES ($1, $2)`,
+			notification.UserID, notification.CreatedAt); err != nil {
+			return false, err
+		}
+	}
+
+	if err := eventrepo.Publish(ctx, tx, notification.UserID, eventrepo.TypeNotificationCreated, map[string]interface{}{
+		"notification_id": notification.ID,
+		"job_id":          notification.JobID,
+		"match_id":        notification.MatchID,
+		"job_title":       notification.JobTitle,
+		"company":         notification.Company,
+		"job_url":         notification.JobURL,
+		"matching_score":  notification.MatchingScore,
+		"delivery":        delivery,
+		"channels":        channels,
+		"created_at":      notification.CreatedAt,
+	}); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
-func (r *postgresNotificationRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]Notification, error) {
+func (r *postgresNotificationRepository) GetByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]Notification, error) {
 	query := `
-		SELECT id, user_id, job_id, match_id, job_title, company, job_url, matching_score, ai_analysis, created_at
+		SELECT id, user_id, job_id, match_id, job_title, company, job_url, matching_score, ai_analysis, delivery, channels, read_at, created_at
 		FROM notifications
-		WHERE user_id = $1
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
 		ORDER BY created_at DESC
-		LIMIT $2
+		LIMIT $3
 	`
-	return r.queryNotifications(ctx, query, userID, limit)
+	return r.queryNotifications(ctx, query, userID, unreadOnly, limit)
 }
 
 func (r *postgresNotificationRepository) GetAll(ctx context.Context, limit int) ([]Notification, error) {
 	query := `
-		SELECT id, user_id, job_id, match_id, job_title, company, job_url, matching_score, ai_analysis, created_at
+		SELECT id, user_id, job_id, match_id, job_title, company, job_url, matching_score, ai_analysis, delivery, channels, read_at, created_at
 		FROM notifications
 		ORDER BY created_at DESC
 		LIMIT $1
@@ -69,6 +162,40 @@ func (r *postgresNotificationRepository) GetAll(ctx context.Context, limit int)
 	return r.queryNotifications(ctx, query, limit)
 }
 
+func (r *postgresNotificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`
+	var count int
+	err := r.db.QueryRow(ctx, query, userID).Scan(&count)
+	return count, err
+}
+
+func (r *postgresNotificationRepository) MarkRead(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	query := `UPDATE notifications SET read_at = COALESCE(read_at, NOW()) WHERE id = $1 AND user_id = $2`
+	tag, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *postgresNotificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	query := `UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`
+	tag, err := r.db.Exec(ctx, query, userID)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *postgresNotificationRepository) Delete(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	query := `DELETE FROM notifications WHERE id = $1 AND user_id = $2`
+	tag, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
 func (r *postgresNotificationRepository) queryNotifications(ctx context.Context, query string, args ...interface{}) ([]Notification, error) {
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -82,7 +209,7 @@ func (r *postgresNotificationRepository) queryNotifications(ctx context.Context,
 		if err := rows.Scan(
 			&n.ID, &n.UserID, &n.JobID, &n.MatchID,
 			&n.JobTitle, &n.Company, &n.JobURL, &n.MatchingScore,
-			&n.AIAnalysis, &n.CreatedAt,
+			&n.AIAnalysis, &n.Delivery, &n.Channels, &n.ReadAt, &n.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -90,4 +217,3 @@ func (r *postgresNotificationRepository) queryNotifications(ctx context.Context,
 	}
 	return notifications, rows.Err()
 }
-
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NotificationTemplate is a user's override of the default template for one channel
+type NotificationTemplate struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Channel   string
+	Subject   string
+	Body      string
+	TextBody  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type TemplateRepository interface {
+	Upsert(ctx context.Context, tmpl *NotificationTemplate) error
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]NotificationTemplate, error)
+	GetByUserAndChannel(ctx context.Context, userID uuid.UUID, channel string) (*NotificationTemplate, error)
+	Delete(ctx context.Context, userID uuid.UUID, channel string) (bool, error)
+}
+
+type postgresTemplateRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTemplateRepository(db *pgxpool.Pool) TemplateRepository {
+	return &postgresTemplateRepository{db: db}
+}
+
+func (r *postgresTemplateRepository) Upsert(ctx context.Context, tmpl *NotificationTemplate) error {
+	query := `
+		INSERT INTO notification_templates (id, user_id, channel, subject, body, text_body, created_at, updated_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), $7, $8)
+		ON CONFLICT (user_id, channel) DO UPDATE
+		SET subject = EXCLUDED.subject, body = EXCLUDED.body, text_body = EXCLUDED.text_body, updated_at = EXCLUDED.updated_at
+	`
+	_, err := r.db.Exec(ctx, query,
+		tmpl.ID, tmpl.UserID, tmpl.Channel, tmpl.Subject, tmpl.Body, tmpl.TextBody, tmpl.CreatedAt, tmpl.UpdatedAt,
+	)
+	return err
+}
+
+func (r *postgresTemplateRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]NotificationTemplate, error) {
+	query := `
+		SELECT id, user_id, channel, COALESCE(subject, ''), COALESCE(body, ''), COALESCE(text_body, ''), created_at, updated_at
+		FROM notification_templates
+		WHERE user_id = $1
+		ORDER BY channel
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []NotificationTemplate
+	for rows.Next() {
+		var t NotificationTemplate
+		if err := rows.Scan(
+			&t.ID, &t.UserID, &t.Channel, &t.Subject, &t.Body, &t.TextBody, &t.CreatedAt, &t.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+func (r *postgresTemplateRepository) GetByUserAndChannel(ctx context.Context, userID uuid.UUID, channel string) (*NotificationTemplate, error) {
+	query := `
+		SELECT id, user_id, channel, COALESCE(subject, ''), COALESCE(body, ''), COALESCE(text_body, ''), created_at, updated_at
+		FROM notification_templates
+		WHERE user_id = $1 AND channel = $2
+	`
+	var t NotificationTemplate
+	err := r.db.QueryRow(ctx, query, userID, channel).Scan(
+		&t.ID, &t.UserID, &t.Channel, &t.Subject, &t.Body, &t.TextBody, &t.CreatedAt, &t.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *postgresTemplateRepository) Delete(ctx context.Context, userID uuid.UUID, channel string) (bool, error) {
+	query := `DELETE FROM notification_templates WHERE user_id = $1 AND channel = $2`
+	tag, err := r.db.Exec(ctx, query, userID, channel)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
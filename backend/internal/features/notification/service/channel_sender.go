@@ -0,0 +1,88 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/jobping/backend/internal/features/notification/render"
+	usermodel "github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/mailer"
+)
+
+// ErrNoDestination is returned by a ChannelSender when the user has not set up the channel,
+// e.g. no webhook URL or no verified email address
+var ErrNoDestination = errors.New("channel is not set up for the user")
+
+// ChannelSender delivers a rendered notification to a user over one channel
+type ChannelSender interface {
+	Send(ctx context.Context, user *usermodel.User, msg *render.Message) error
+}
+
+// NewChannelSenders returns the senders for every channel: Discord and Slack post to the
+// user's incoming webhook, email goes to their verified address
+func NewChannelSenders(mail mailer.Mailer) map[render.Channel]ChannelSender {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return map[render.Channel]ChannelSender{
+		render.ChannelDiscord: &webhookSender{client: client, webhook: func(u *usermodel.User) *string { return u.DiscordWebhook }},
+		render.ChannelSlack:   &webhookSender{client: client, webhook: func(u *usermodel.User) *string { return u.SlackWebhook }},
+		render.ChannelEmail:   &emailSender{mail: mail},
+	}
+}
+
+// webhookSender posts the rendered JSON payload to a webhook URL from the user's profile
+type webhookSender struct {
+	client  *http.Client
+	webhook func(*usermodel.User) *string
+}
+
+func (s *webhookSender) Send(ctx context.Context, user *usermodel.User, msg *render.Message) error {
+	target := s.webhook(user)
+	if target == nil || *target == "" {
+		return ErrNoDestination
+	}
+	u, err := url.Parse(*target)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("webhook URL must be an https URL")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewBufferString(msg.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", msg.ContentType)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// emailSender mails the rendered subject, HTML and text bodies. Unverified addresses are not used.
+type emailSender struct {
+	mail mailer.Mailer
+}
+
+func (s *emailSender) Send(ctx context.Context, user *usermodel.User, msg *render.Message) error {
+	if user.Email == nil || user.EmailVerifiedAt == nil {
+		return ErrNoDestination
+	}
+	return s.mail.Send(ctx, mailer.Message{
+		To:       *user.Email,
+		Subject:  msg.Subject,
+		TextBody: msg.TextBody,
+		HTMLBody: msg.Body,
+	})
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"errors"
+	"time"
+	_ "time/tzdata" // user timezones must resolve on Lambda images without zoneinfo
+
+	usermodel "github.com/jobping/backend/internal/features/user/model"
+)
+
+// Reasons an instant notification is deferred to the next digest
+const (
+	DeferReasonQuietHours  = "quiet_hours"
+	DeferReasonRateLimited = "rate_limited"
+)
+
+// errRateLimited is returned by deliver when the user's hourly limit held an instant notification back
+var errRateLimited = errors.New("hourly notification limit reached")
+
+// userLocation resolves the user's timezone, falling back to UTC for unknown zones
+func userLocation(user *usermodel.User) *time.Location {
+	if user.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// inQuietHours reports whether t falls inside the user's quiet hours in their own timezone.
+// The window is [start, end) in whole hours and may wrap past midnight (e.g. 22 -> 7).
+func inQuietHours(user *usermodel.User, t time.Time) bool {
+	if user.QuietHoursStart == nil || user.QuietHoursEnd == nil {
+		return false
+	}
+	start, end := *user.QuietHoursStart, *user.QuietHoursEnd
+	if start == end {
+		return false
+	}
+
+	hour := t.In(userLocation(user)).Hour()
+	if start < end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
+}
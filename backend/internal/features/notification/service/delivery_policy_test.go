@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	usermodel "github.com/jobping/backend/internal/features/user/model"
+)
+
+func hours(start, end int) (*int, *int) {
+	return &start, &end
+}
+
+func TestInQuietHours(t *testing.T) {
+	// 2026-01-15 22:30 UTC is 23:30 in Berlin and 07:30 the next day in Tokyo
+	at := time.Date(2026, 1, 15, 22, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		timezone   string
+		start, end int
+		unset      bool
+		quiet      bool
+	}{
+		{name: "no quiet hours", unset: true, quiet: false},
+		{name: "same start and end", start: 9, end: 9, quiet: false},
+		{name: "inside daytime window", start: 20, end: 23, quiet: true},
+		{name: "end is exclusive", start: 18, end: 22, quiet: false},
+		{name: "start is inclusive", start: 22, end: 23, quiet: true},
+		{name: "outside daytime window", start: 9, end: 17, quiet: false},
+		// Windows that wrap past midnight
+		{name: "wrapping, before midnight", start: 22, end: 7, quiet: true},
+		{name: "wrapping, outside", start: 23, end: 7, quiet: false},
+		{name: "wrapping, after midnight", timezone: "Asia/Tokyo", start: 22, end: 8, quiet: true},
+		{name: "wrapping, past the end", timezone: "Asia/Tokyo", start: 22, end: 7, quiet: false},
+		// The hour is read in the user's own timezone
+		{name: "user timezone", timezone: "Europe/Berlin", start: 23, end: 7, quiet: true},
+		{name: "user timezone outside", timezone: "Europe/Berlin", start: 0, end: 7, quiet: false},
+		{name: "unknown timezone falls back to UTC", timezone: "Mars/Olympus", start: 22, end: 23, quiet: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &usermodel.User{Timezone: tt.timezone}
+			if !tt.unset {
+				user.QuietHoursStart, user.QuietHoursEnd = hours(tt.start, tt.end)
+			}
+			if got := inQuietHours(user, at); got != tt.quiet {
+				t.Errorf("inQuietHours(%d-%d %q) = %v, want %v", tt.start, tt.end, tt.timezone, got, tt.quiet)
+			}
+		})
+	}
+}
@@ -2,38 +2,64 @@ package service
 
 import (
 	"context"
+	"errors"
 	"log"
 	"time"
 
 	"github.com/google/uuid"
+	jobmodel "github.com/jobping/backend/internal/features/job/model"
 	jobrepo "github.com/jobping/backend/internal/features/job/repository"
+	"github.com/jobping/backend/internal/features/notification/notificationerr"
+	"github.com/jobping/backend/internal/features/notification/render"
 	notificationrepo "github.com/jobping/backend/internal/features/notification/repository"
+	usermodel "github.com/jobping/backend/internal/features/user/model"
 	userrepo "github.com/jobping/backend/internal/features/user/repository"
 )
 
 type NotificationService struct {
 	jobRepo    jobrepo.JobRepository
+	events     jobrepo.PipelineEventRepository
 	userRepo   userrepo.UserRepository
 	matchRepo  userrepo.UserJobMatchRepository
+	searchRepo userrepo.SavedSearchRepository
 	notifRepo  notificationrepo.NotificationRepository
+	templates  *TemplateService
+	senders    map[render.Channel]ChannelSender
 }
 
+// Per-channel delivery results, recorded on the notification's pipeline event
+const (
+	ChannelSent          = "sent"
+	ChannelNotConfigured = "not_configured"
+	ChannelFailed        = "failed"
+)
+
 func NewNotificationService(
 	jobRepo jobrepo.JobRepository,
+	events jobrepo.PipelineEventRepository,
 	userRepo userrepo.UserRepository,
 	matchRepo userrepo.UserJobMatchRepository,
+	searchRepo userrepo.SavedSearchRepository,
 	notifRepo notificationrepo.NotificationRepository,
+	templates *TemplateService,
+	senders map[render.Channel]ChannelSender,
 ) *NotificationService {
 	return &NotificationService{
-		jobRepo:   jobRepo,
-		userRepo:  userRepo,
-		matchRepo: matchRepo,
-		notifRepo: notifRepo,
+		jobRepo:    jobRepo,
+		events:     events,
+		userRepo:   userRepo,
+		matchRepo:  matchRepo,
+		searchRepo: searchRepo,
+		notifRepo:  notifRepo,
+		templates:  templates,
+		senders:    senders,
 	}
 }
 
-// SendNotification creates a notification event for testing (stores in notifications table)
-func (s *NotificationService) SendNotification(ctx context.Context, jobID, userID uuid.UUID) error {
+// SendNotification creates a notification event for testing (stores in notifications table).
+// Matches arriving during the user's quiet hours or over their hourly limit are deferred to the next digest.
+// A nil searchID (messages queued before saved searches) uses the user's best match for the job.
+func (s *NotificationService) SendNotification(ctx context.Context, jobID, userID, searchID uuid.UUID) error {
 	// Fetch user
 	user, err := s.userRepo.GetUserByID(ctx, userID)
 	if err != nil {
@@ -55,33 +81,147 @@ func (s *NotificationService) SendNotification(ctx context.Context, jobID, userI
 	}
 
 	// Fetch match
-	match, err := s.matchRepo.GetByUserAndJob(ctx, userID, jobID)
+	var match *usermodel.UserJobMatch
+	if searchID == uuid.Nil {
+		match, err = s.matchRepo.GetByUserAndJob(ctx, userID, jobID)
+	} else {
+		match, err = s.matchRepo.GetBySearchAndJob(ctx, searchID, jobID)
+	}
 	if err != nil {
 		return err
 	}
-	if match == nil {
+	if match == nil || match.UserID != userID {
 		log.Printf("Match not found for user %s and job %s", userID, jobID)
+		event := pipelineEvent(jobID, userID, jobmodel.PipelineOutcomeSkipped, map[string]interface{}{"reason": "no_match"})
+		if searchID != uuid.Nil {
+			event.SavedSearchID = &searchID
+		}
+		s.events.RecordBestEffort(ctx, event)
 		return nil
 	}
 
-	// Create notification event
+	if match.Notified {
+		log.Printf("Match %s already notified, skipping", match.ID)
+		s.events.RecordBestEffort(ctx, matchEvent(match, jobmodel.PipelineOutcomeSkipped, map[string]interface{}{"reason": "already_notified"}))
+		return nil
+	}
+
+	// Quiet hours and rate limit: defer to the next digest instead of dropping
+	if inQuietHours(user, time.Now()) {
+		return s.deferMatch(ctx, user, job, match, DeferReasonQuietHours)
+	}
+
+	err = s.deliver(ctx, user, job, match, notificationrepo.DeliveryInstant)
+	if errors.Is(err, errRateLimited) {
+		return s.deferMatch(ctx, user, job, match, DeferReasonRateLimited)
+	}
+	return err
+}
+
+// deferMatch holds a match back for the next digest
+func (s *NotificationService) deferMatch(ctx context.Context, user *usermodel.User, job *jobmodel.Job, match *usermodel.UserJobMatch, reason string) error {
+	if err := s.matchRepo.MarkDeferred(ctx, match.ID, reason); err != nil {
+		log.Printf("Failed to defer match %s: %v", match.ID, err)
+		return err
+	}
+	log.Printf("Deferred notification for user %s about job %s (%s)", user.Username, job.Title, reason)
+	s.events.RecordBestEffort(ctx, matchEvent(match, jobmodel.PipelineOutcomeDeferred, map[string]interface{}{"reason": reason}))
+	return nil
+}
+
+// SendDigests delivers every deferred match whose user is currently outside quiet hours.
+// Digests are not subject to the hourly rate limit; they are the rollup for matches it held back.
+func (s *NotificationService) SendDigests(ctx context.Context) error {
+	matches, err := s.matchRepo.GetDeferred(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	users := make(map[uuid.UUID]*usermodel.User)
+	sent := 0
+	for i := range matches {
+		match := &matches[i]
+
+		user, ok := users[match.UserID]
+		if !ok {
+			user, err = s.userRepo.GetUserByID(ctx, match.UserID)
+			if err != nil {
+				// Retried on the next digest run; other users' digests still go out
+				log.Printf("Failed to load user %s for digest: %v", match.UserID, err)
+				continue
+			}
+			users[match.UserID] = user
+		}
+		if user == nil || inQuietHours(user, now) {
+			continue
+		}
+
+		job, err := s.jobRepo.GetByID(ctx, match.JobID)
+		if err != nil {
+			log.Printf("Failed to load job %s for digest: %v", match.JobID, err)
+			continue
+		}
+		if job == nil {
+			log.Printf("Job not found for deferred match %s", match.ID)
+			continue
+		}
+
+		if err := s.deliver(ctx, user, job, match, notificationrepo.DeliveryDigest); err != nil {
+			log.Printf("Failed to deliver digest notification for match %s: %v", match.ID, err)
+			continue
+		}
+		sent++
+	}
+
+	log.Printf("Sent %d of %d deferred notifications in digest", sent, len(matches))
+	return nil
+}
+
+// deliver stores the notification event, marks the match as notified and sends the notification
+// over the channels of the match's saved search
+func (s *NotificationService) deliver(ctx context.Context, user *usermodel.User, job *jobmodel.Job, match *usermodel.UserJobMatch, delivery string) error {
+	search, err := s.searchRepo.GetByID(ctx, match.SavedSearchID)
+	if err != nil {
+		return err
+	}
+	var channels []string
+	if search != nil {
+		channels = search.Channels
+	}
+
 	notification := &notificationrepo.Notification{
 		ID:            uuid.New(),
-		UserID:        userID,
-		JobID:         jobID,
+		UserID:        user.ID,
+		JobID:         job.ID,
 		MatchID:       match.ID,
 		JobTitle:      job.Title,
 		Company:       job.Company,
 		JobURL:        job.JobURL,
 		MatchingScore: match.Score,
 		AIAnalysis:    match.Analysis,
+		Delivery:      delivery,
+		Channels:      channels,
 		CreatedAt:     time.Now(),
 	}
 
-	if err := s.notifRepo.Create(ctx, notification); err != nil {
+	// The hourly limit applies to instant notifications only; it is checked when the row is
+	// inserted so concurrent workers cannot both slip under it
+	limit := 0
+	if delivery == notificationrepo.DeliveryInstant && user.MaxNotificationsPerHour != nil {
+		limit = *user.MaxNotificationsPerHour
+	}
+	created, err := s.notifRepo.CreateWithinLimit(ctx, notification, notification.CreatedAt.Add(-time.Hour), limit)
+	if err != nil {
 		log.Printf("Failed to create notification: %v", err)
+		event := matchEvent(match, jobmodel.PipelineOutcomeFailed, map[string]interface{}{"delivery": delivery})
+		event.Error = err.Error()
+		s.events.RecordBestEffort(ctx, event)
 		return err
 	}
+	if !created {
+		return errRateLimited
+	}
 
 	// Mark match as notified
 	if err := s.matchRepo.MarkNotified(ctx, match.ID); err != nil {
@@ -89,15 +229,115 @@ func (s *NotificationService) SendNotification(ctx context.Context, jobID, userI
 		// Don't fail the whole operation
 	}
 
-	log.Printf("Created notification for user %s about job %s (score: %d)", user.Username, job.Title, match.Score)
+	results := s.sendToChannels(ctx, user, job, match, channels)
+
+	log.Printf("Created %s notification for user %s about job %s (score: %d)", delivery, user.Username, job.Title, match.Score)
+	s.events.RecordBestEffort(ctx, matchEvent(match, jobmodel.PipelineOutcomeSent, map[string]interface{}{
+		"delivery": delivery,
+		"channels": results,
+		"score":    match.Score,
+	}))
 	return nil
 }
 
-// GetNotifications returns notifications for a user (or all if userID is nil)
-func (s *NotificationService) GetNotifications(ctx context.Context, userID *uuid.UUID, limit int) ([]notificationrepo.Notification, error) {
+// sendToChannels renders the notification for each channel with the user's template override, or
+// the default, and sends it. The in-app notification is already stored and the match marked, so
+// failed channels are reported in the results rather than retried.
+func (s *NotificationService) sendToChannels(ctx context.Context, user *usermodel.User, job *jobmodel.Job, match *usermodel.UserJobMatch, channels []string) map[string]string {
+	results := make(map[string]string, len(channels))
+	data := NewRenderData(user.Username, job.Title, job.Company, job.JobURL, match.Score, match.Analysis)
+	for _, name := range channels {
+		channel := render.Channel(name)
+		sender, ok := s.senders[channel]
+		if !ok || s.templates == nil {
+			results[name] = ChannelNotConfigured
+			continue
+		}
+
+		msg, err := s.templates.Render(ctx, user.ID, channel, data, nil)
+		if err != nil {
+			// Overrides are validated when saved, but a stored one must not block delivery
+			log.Printf("Failed to render %s notification for match %s, using the default template: %v", channel, match.ID, err)
+			msg, err = s.templates.Render(ctx, user.ID, channel, data, &render.Override{})
+		}
+		if err == nil {
+			err = sender.Send(ctx, user, msg)
+		}
+
+		switch {
+		case errors.Is(err, ErrNoDestination):
+			results[name] = ChannelNotConfigured
+		case err != nil:
+			log.Printf("Failed to send %s notification for match %s: %v", channel, match.ID, err)
+			results[name] = ChannelFailed
+		default:
+			results[name] = ChannelSent
+		}
+	}
+	return results
+}
+
+// pipelineEvent builds a notification event for the job's timeline
+func pipelineEvent(jobID, userID uuid.UUID, outcome string, details map[string]interface{}) jobmodel.PipelineEvent {
+	return jobmodel.PipelineEvent{
+		JobID:   jobID,
+		UserID:  &userID,
+		Stage:   jobmodel.PipelineStageNotification,
+		Outcome: outcome,
+		Details: details,
+	}
+}
+
+// matchEvent builds a notification event about one match
+func matchEvent(match *usermodel.UserJobMatch, outcome string, details map[string]interface{}) jobmodel.PipelineEvent {
+	event := pipelineEvent(match.JobID, match.UserID, outcome, details)
+	event.SavedSearchID = &match.SavedSearchID
+	return event
+}
+
+// GetNotifications returns the user's notifications, newest first
+func (s *NotificationService) GetNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]notificationrepo.Notification, error) {
+	return s.notifRepo.GetByUserID(ctx, userID, unreadOnly, limit)
+}
+
+// GetAllNotifications is the admin cross-user view: one user's notifications, or everyone's if userID is nil
+func (s *NotificationService) GetAllNotifications(ctx context.Context, userID *uuid.UUID, limit int) ([]notificationrepo.Notification, error) {
 	if userID != nil {
-		return s.notifRepo.GetByUserID(ctx, *userID, limit)
+		return s.notifRepo.GetByUserID(ctx, *userID, false, limit)
 	}
 	return s.notifRepo.GetAll(ctx, limit)
 }
 
+// CountUnread returns how many of the user's notifications are unread
+func (s *NotificationService) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.notifRepo.CountUnread(ctx, userID)
+}
+
+// MarkRead marks one of the user's notifications as read
+func (s *NotificationService) MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error {
+	found, err := s.notifRepo.MarkRead(ctx, userID, notificationID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return notificationerr.ErrNotificationNotFound
+	}
+	return nil
+}
+
+// MarkAllRead marks all of the user's notifications as read and returns how many changed
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.notifRepo.MarkAllRead(ctx, userID)
+}
+
+// DeleteNotification deletes one of the user's notifications
+func (s *NotificationService) DeleteNotification(ctx context.Context, userID, notificationID uuid.UUID) error {
+	found, err := s.notifRepo.Delete(ctx, userID, notificationID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return notificationerr.ErrNotificationNotFound
+	}
+	return nil
+}
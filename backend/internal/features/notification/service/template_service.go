@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	jobrepo "github.com/jobping/backend/internal/features/job/repository"
+	"github.com/jobping/backend/internal/features/notification/notificationerr"
+	"github.com/jobping/backend/internal/features/notification/render"
+	notificationrepo "github.com/jobping/backend/internal/features/notification/repository"
+	userrepo "github.com/jobping/backend/internal/features/user/repository"
+)
+
+// TemplateService manages per-user template overrides and renders notifications per channel
+type TemplateService struct {
+	jobRepo      jobrepo.JobRepository
+	userRepo     userrepo.UserRepository
+	matchRepo    userrepo.UserJobMatchRepository
+	templateRepo notificationrepo.TemplateRepository
+	renderer     *render.Renderer
+}
+
+func NewTemplateService(
+	jobRepo jobrepo.JobRepository,
+	userRepo userrepo.UserRepository,
+	matchRepo userrepo.UserJobMatchRepository,
+	templateRepo notificationrepo.TemplateRepository,
+	renderer *render.Renderer,
+) *TemplateService {
+	return &TemplateService{
+		jobRepo:      jobRepo,
+		userRepo:     userRepo,
+		matchRepo:    matchRepo,
+		templateRepo: templateRepo,
+		renderer:     renderer,
+	}
+}
+
+// GetTemplates returns the user's template overrides
+func (s *TemplateService) GetTemplates(ctx context.Context, userID uuid.UUID) ([]notificationrepo.NotificationTemplate, error) {
+	return s.templateRepo.GetByUserID(ctx, userID)
+}
+
+// SaveTemplate validates and stores a user's override for a channel
+func (s *TemplateService) SaveTemplate(ctx context.Context, userID uuid.UUID, channel render.Channel, override render.Override) (*notificationrepo.NotificationTemplate, error) {
+	if err := s.renderer.Validate(channel, override); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tmpl := &notificationrepo.NotificationTemplate{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Channel:   string(channel),
+		Subject:   override.Subject,
+		Body:      override.Body,
+		TextBody:  override.TextBody,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.templateRepo.Upsert(ctx, tmpl); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// DeleteTemplate removes a user's override so the channel falls back to the default template
+func (s *TemplateService) DeleteTemplate(ctx context.Context, userID uuid.UUID, channel render.Channel) error {
+	deleted, err := s.templateRepo.Delete(ctx, userID, string(channel))
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return notificationerr.ErrTemplateNotFound
+	}
+	return nil
+}
+
+// Preview renders the notification for one of the user's matches without sending it.
+// A non-nil draft is rendered instead of the stored override, so edits can be previewed before saving.
+func (s *TemplateService) Preview(ctx context.Context, userID, matchID uuid.UUID, channel render.Channel, draft *render.Override) (*render.Message, error) {
+	match, err := s.matchRepo.GetByID(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+	if match == nil || match.UserID != userID {
+		return nil, notificationerr.ErrMatchNotFound
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	job, err := s.jobRepo.GetByID(ctx, match.JobID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || job == nil {
+		return nil, notificationerr.ErrMatchNotFound
+	}
+
+	data := NewRenderData(user.Username, job.Title, job.Company, job.JobURL, match.Score, match.Analysis)
+	return s.Render(ctx, userID, channel, data, draft)
+}
+
+// Render renders data for a channel using the user's stored override, or draft when given
+func (s *TemplateService) Render(ctx context.Context, userID uuid.UUID, channel render.Channel, data render.Data, draft *render.Override) (*render.Message, error) {
+	override := draft
+	if override == nil {
+		stored, err := s.templateRepo.GetByUserAndChannel(ctx, userID, string(channel))
+		if err != nil {
+			return nil, err
+		}
+		if stored != nil {
+			override = &render.Override{Subject: stored.Subject, Body: stored.Body, TextBody: stored.TextBody}
+		}
+	}
+	return s.renderer.Render(channel, data, override)
+}
+
+// NewRenderData builds template data from a match's stored AI analysis
+func NewRenderData(username, jobTitle, company, jobURL string, score int, analysis map[string]interface{}) render.Data {
+	data := render.Data{
+		Username: username,
+		JobTitle: jobTitle,
+		Company:  company,
+		JobURL:   jobURL,
+		Score:    score,
+	}
+	if explanation, ok := analysis["explanation"].(string); ok {
+		data.Explanation = explanation
+	}
+	data.Pros = stringSlice(analysis["pros"])
+	data.Cons = stringSlice(analysis["cons"])
+	return data
+}
+
+// stringSlice converts a JSONB array (decoded as []interface{}) to strings
+func stringSlice(v interface{}) []string {
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/jobping/backend/internal/features/user/usererr"
+)
+
+func (h *UserHandler) UpdateEmail(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req UpdateEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.accounts.SetEmail(r.Context(), userID, req.Email); err != nil {
+		switch {
+		case errors.Is(err, usererr.ErrInvalidEmail):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, usererr.ErrEmailAlreadyExists):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	if req.Email == "" {
+		writeJSON(w, http.StatusOK, map[string]string{"message": "email removed"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "email updated, check your inbox to verify it"})
+}
+
+func (h *UserHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.accounts.ResendVerification(r.Context(), userID); err != nil {
+		if errors.Is(err, usererr.ErrEmailNotSet) || errors.Is(err, usererr.ErrEmailAlreadyVerified) {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]string{"message": "verification email sent"})
+}
+
+func (h *UserHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req TokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		writeError(w, http.StatusBadRequest, "token is required")
+		return
+	}
+
+	if err := h.accounts.VerifyEmail(r.Context(), req.Token); err != nil {
+		if errors.Is(err, usererr.ErrInvalidToken) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "email verified"})
+}
+
+// ForgotPassword always answers 202 so the response does not reveal whether the email is registered
+func (h *UserHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Email == "" {
+		writeError(w, http.StatusBadRequest, "email is required")
+		return
+	}
+
+	if err := h.accounts.ForgotPassword(r.Context(), req.Email); err != nil {
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]string{"message": "if that email belongs to a verified account, a reset link has been sent"})
+}
+
+func (h *UserHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Token == "" || req.Password == "" {
+		writeError(w, http.StatusBadRequest, "token and password are required")
+		return
+	}
+
+	if err := h.accounts.ResetPassword(r.Context(), req.Token, req.Password); err != nil {
+		if errors.Is(err, usererr.ErrInvalidToken) || errors.Is(err, usererr.ErrWeakPassword) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "password updated, please log in again"})
+}
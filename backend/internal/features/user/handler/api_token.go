@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/service"
+	"github.com/jobping/backend/internal/features/user/usererr"
+)
+
+// CreateAPIToken issues a personal API token. The token is in this response only.
+func (h *UserHandler) CreateAPIToken(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req CreateAPITokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	expiresIn := service.DefaultAPITokenExpiry
+	if req.ExpiresInDays != nil {
+		expiresIn = time.Duration(*req.ExpiresInDays) * 24 * time.Hour
+	}
+
+	token, rawToken, err := h.tokens.Create(r.Context(), userID, req.Name, req.Scopes, expiresIn)
+	if err != nil {
+		switch {
+		case errors.Is(err, usererr.ErrInvalidTokenName), errors.Is(err, usererr.ErrInvalidScope), errors.Is(err, usererr.ErrInvalidExpiry):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, usererr.ErrTooManyAPITokens):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, CreateAPITokenResponse{
+		APITokenResponse: toAPITokenResponse(*token, time.Now()),
+		Token:            rawToken,
+	})
+}
+
+func (h *UserHandler) GetAPITokens(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	tokens, err := h.tokens.List(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	now := time.Now()
+	response := APITokensResponse{
+		Tokens:          make([]APITokenResponse, 0, len(tokens)),
+		AvailableScopes: model.Scopes,
+	}
+	for _, t := range tokens {
+		response.Tokens = append(response.Tokens, toAPITokenResponse(t, now))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *UserHandler) RevokeAPIToken(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	tokenID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid token id")
+		return
+	}
+
+	if err := h.tokens.Revoke(r.Context(), userID, tokenID); err != nil {
+		if errors.Is(err, usererr.ErrAPITokenNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func toAPITokenResponse(t model.APIToken, now time.Time) APITokenResponse {
+	resp := APITokenResponse{
+		ID:          t.ID,
+		Name:        t.Name,
+		TokenPrefix: t.TokenPrefix,
+		Scopes:      t.Scopes,
+		Expired:     !t.ExpiresAt.After(now),
+		ExpiresAt:   t.ExpiresAt.Format(time.RFC3339),
+		CreatedAt:   t.CreatedAt.Format(time.RFC3339),
+	}
+	if t.LastUsedAt != nil {
+		lastUsed := t.LastUsedAt.Format(time.RFC3339)
+		resp.LastUsedAt = &lastUsed
+	}
+	return resp
+}
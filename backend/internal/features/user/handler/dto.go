@@ -1,10 +1,14 @@
 package handler
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/user/model"
+)
 
 type RegisterRequest struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
+	Email    string `json:"email,omitempty"`
 }
 
 type LoginRequest struct {
@@ -12,9 +16,106 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+// TokenResponse carries a short-lived access token (Token) and the refresh token used to renew it
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"` // access token lifetime in seconds
+}
+
 type AuthResponse struct {
-	Token string       `json:"token"`
-	User  UserResponse `json:"user"`
+	TokenResponse
+	User UserResponse `json:"user"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type UpdateEmailRequest struct {
+	Email string `json:"email"`
+}
+
+type TokenRequest struct {
+	Token string `json:"token"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	Current    bool      `json:"current"`
+	StartedAt  string    `json:"started_at"`
+	LastUsedAt string    `json:"last_used_at"`
+	ExpiresAt  string    `json:"expires_at"`
+}
+
+type SessionsResponse struct {
+	Sessions []SessionResponse `json:"sessions"`
+}
+
+// CreateAPITokenRequest creates a personal API token. ExpiresInDays defaults to 90.
+type CreateAPITokenRequest struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays *int     `json:"expires_in_days,omitempty"`
+}
+
+type APITokenResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	TokenPrefix string    `json:"token_prefix"`
+	Scopes      []string  `json:"scopes"`
+	Expired     bool      `json:"expired"`
+	ExpiresAt   string    `json:"expires_at"`
+	LastUsedAt  *string   `json:"last_used_at"`
+	CreatedAt   string    `json:"created_at"`
+}
+
+// CreateAPITokenResponse carries the only copy of the token the server ever returns
+type CreateAPITokenResponse struct {
+	APITokenResponse
+	Token string `json:"token"`
+}
+
+type APITokensResponse struct {
+	Tokens []APITokenResponse `json:"tokens"`
+	// AvailableScopes lists every scope a token can be granted
+	AvailableScopes []string `json:"available_scopes"`
+}
+
+type OIDCProvidersResponse struct {
+	Providers []string `json:"providers"`
+}
+
+type OIDCStartResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+type OIDCCallbackRequest struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
+
+type IdentityResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Provider    string    `json:"provider"`
+	Email       string    `json:"email,omitempty"`
+	CreatedAt   string    `json:"created_at"`
+	LastLoginAt string    `json:"last_login_at,omitempty"`
+}
+
+type IdentitiesResponse struct {
+	Identities []IdentityResponse `json:"identities"`
 }
 
 type UserResponse struct {
@@ -49,27 +150,208 @@ type UpdateDiscordRequest struct {
 	WebhookURL string `json:"webhook_url"`
 }
 
+type UpdateSlackRequest struct {
+	WebhookURL string `json:"webhook_url"`
+}
+
 type UpdateThresholdRequest struct {
 	Threshold int `json:"threshold"`
 }
 
+type UpdateNotificationSettingsRequest struct {
+	Timezone                string `json:"timezone"`
+	QuietHoursStart         *int   `json:"quiet_hours_start"`
+	QuietHoursEnd           *int   `json:"quiet_hours_end"`
+	MaxNotificationsPerHour *int   `json:"max_notifications_per_hour"`
+}
+
+type NotificationSettingsResponse struct {
+	Timezone                string `json:"timezone"`
+	QuietHoursStart         *int   `json:"quiet_hours_start"`
+	QuietHoursEnd           *int   `json:"quiet_hours_end"`
+	MaxNotificationsPerHour *int   `json:"max_notifications_per_hour"`
+}
+
 type ProfileResponse struct {
-	ID              uuid.UUID `json:"id"`
-	Username        string    `json:"username"`
-	AIPrompt        *string   `json:"ai_prompt"`
-	DiscordWebhook  *string   `json:"discord_webhook"`
-	NotifyThreshold int       `json:"notify_threshold"`
+	ID                   uuid.UUID                    `json:"id"`
+	Username             string                       `json:"username"`
+	Email                *string                      `json:"email"`
+	EmailVerified        bool                         `json:"email_verified"`
+	AIPrompt             *string                      `json:"ai_prompt"`
+	DiscordWebhook       *string                      `json:"discord_webhook"`
+	SlackWebhook         *string                      `json:"slack_webhook"`
+	NotifyThreshold      int                          `json:"notify_threshold"`
+	NotificationSettings NotificationSettingsResponse `json:"notification_settings"`
+	DeletionScheduledAt  *string                      `json:"deletion_scheduled_at"`
 }
 
 type UserJobMatchResponse struct {
-	ID        uuid.UUID              `json:"id"`
-	JobID     uuid.UUID              `json:"job_id"`
-	Score     int                    `json:"score"`
-	Analysis  map[string]interface{} `json:"analysis"`
-	Notified  bool                   `json:"notified"`
-	CreatedAt string                 `json:"created_at"`
+	ID               uuid.UUID              `json:"id"`
+	SavedSearchID    uuid.UUID              `json:"saved_search_id"`
+	JobID            uuid.UUID              `json:"job_id"`
+	Score            int                    `json:"score"`
+	Analysis         map[string]interface{} `json:"analysis"`
+	Notified         bool                   `json:"notified"`
+	DeferredReason   *string                `json:"deferred_reason,omitempty"`
+	PromptRevisionID *uuid.UUID             `json:"prompt_revision_id"`
+	CreatedAt        string                 `json:"created_at"`
 }
 
 type UserMatchesResponse struct {
 	Matches []UserJobMatchResponse `json:"matches"`
-}
\ No newline at end of file
+}
+
+// DeleteAccountRequest confirms an account deletion. Confirm must be the username;
+// Password is required when the account has one.
+type DeleteAccountRequest struct {
+	Confirm  string `json:"confirm"`
+	Password string `json:"password"`
+}
+
+type DeleteAccountResponse struct {
+	DeletionScheduledAt string `json:"deletion_scheduled_at"`
+}
+
+// SavedSearchRequest creates or updates a saved search. On update, omitted fields are unchanged.
+// name and ai_prompt are required on create; notify_threshold defaults to 70 and channels to ["discord"].
+type SavedSearchRequest struct {
+	Name            *string              `json:"name"`
+	AIPrompt        *string              `json:"ai_prompt"`
+	Filters         *model.SearchFilters `json:"filters"`
+	NotifyThreshold *int                 `json:"notify_threshold"`
+	Channels        []string             `json:"channels"`
+	Active          *bool                `json:"active"`
+}
+
+type SavedSearchResponse struct {
+	ID              uuid.UUID           `json:"id"`
+	Name            string              `json:"name"`
+	AIPrompt        string              `json:"ai_prompt"`
+	Filters         model.SearchFilters `json:"filters"`
+	NotifyThreshold int                 `json:"notify_threshold"`
+	Channels        []string            `json:"channels"`
+	Active          bool                `json:"active"`
+	IsDefault       bool                `json:"is_default"`
+	CreatedAt       string              `json:"created_at"`
+	UpdatedAt       string              `json:"updated_at"`
+}
+
+type SavedSearchesResponse struct {
+	Searches []SavedSearchResponse `json:"searches"`
+	// AvailableChannels lists every notification channel a search can use
+	AvailableChannels []string `json:"available_channels"`
+}
+
+type PromptRevisionResponse struct {
+	ID        uuid.UUID `json:"id"`
+	AIPrompt  string    `json:"ai_prompt"`
+	CreatedAt string    `json:"created_at"`
+}
+
+// PromptHistoryResponse lists a saved search's prompts, newest (current) first
+type PromptHistoryResponse struct {
+	Revisions []PromptRevisionResponse `json:"revisions"`
+}
+
+// StartRescoreRequest picks how many days of recent jobs to re-score (1-30, default 7)
+type StartRescoreRequest struct {
+	Days int `json:"days"`
+}
+
+type RescoreResponse struct {
+	ID               uuid.UUID  `json:"id"`
+	SavedSearchID    uuid.UUID  `json:"saved_search_id"`
+	PromptRevisionID *uuid.UUID `json:"prompt_revision_id"`
+	Days             int        `json:"days"`
+	// Status is "running" until Completed and Failed add up to Total, then "finished"
+	Status     string  `json:"status"`
+	Total      int     `json:"total"`
+	Completed  int     `json:"completed"`
+	Failed     int     `json:"failed"`
+	CreatedAt  string  `json:"created_at"`
+	FinishedAt *string `json:"finished_at"`
+}
+
+type RescoresResponse struct {
+	Rescores []RescoreResponse `json:"rescores"`
+}
+
+// ResumeProfileResponse is the profile derived from the user's resume. The extracted text is
+// only included in the data export.
+type ResumeProfileResponse struct {
+	FileName        string   `json:"file_name"`
+	Format          string   `json:"format"`
+	Skills          []string `json:"skills"`
+	Seniority       string   `json:"seniority"`
+	Locations       []string `json:"locations"`
+	SalaryMin       *int     `json:"salary_min"`
+	SalaryMax       *int     `json:"salary_max"`
+	SalaryCurrency  string   `json:"salary_currency"`
+	SuggestedPrompt string   `json:"suggested_prompt"`
+	// Applied is set on upload when the suggested prompt became the default search's prompt
+	Applied   bool   `json:"applied,omitempty"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// JobExplanationResponse explains why the user was, or was not, notified about a job.
+// Notified is true when any of the user's saved searches notified them.
+type JobExplanationResponse struct {
+	JobID    uuid.UUID                   `json:"job_id"`
+	Title    string                      `json:"title"`
+	Company  string                      `json:"company"`
+	Notified bool                        `json:"notified"`
+	Steps    []ExplanationStepResponse   `json:"steps"`
+	Searches []SearchExplanationResponse `json:"searches"`
+}
+
+type SearchExplanationResponse struct {
+	SavedSearchID uuid.UUID `json:"saved_search_id"`
+	Name          string    `json:"name"`
+	// Verdict is "notified", "deferred", "pending" or "not_notified"; Reason is the deciding step's detail
+	Verdict string                    `json:"verdict"`
+	Reason  string                    `json:"reason"`
+	Steps   []ExplanationStepResponse `json:"steps"`
+}
+
+// ExplanationStepResponse is one decision; Status is "passed", "failed", "pending" or "skipped"
+type ExplanationStepResponse struct {
+	Step   string  `json:"step"`
+	Status string  `json:"status"`
+	Detail string  `json:"detail,omitempty"`
+	At     *string `json:"at,omitempty"`
+}
+
+// EvaluateJobRequest is a job given by job_url, by pasted title and description, or both.
+// saved_search_id defaults to the user's default search.
+type EvaluateJobRequest struct {
+	JobURL        string     `json:"job_url"`
+	Title         string     `json:"title"`
+	Company       string     `json:"company"`
+	Location      string     `json:"location"`
+	Description   string     `json:"description"`
+	SavedSearchID *uuid.UUID `json:"saved_search_id"`
+}
+
+type JobEvaluationResponse struct {
+	JobID    uuid.UUID `json:"job_id"`
+	JobURL   string    `json:"job_url"`
+	Title    string    `json:"title"`
+	Company  string    `json:"company"`
+	Location string    `json:"location"`
+	// Created is true when the evaluation ingested the job
+	Created        bool      `json:"created"`
+	SavedSearchID  uuid.UUID `json:"saved_search_id"`
+	SearchName     string    `json:"search_name"`
+	MatchID        uuid.UUID `json:"match_id"`
+	Score          int       `json:"score"`
+	Threshold      int       `json:"threshold"`
+	AboveThreshold bool      `json:"above_threshold"`
+	Explanation    string    `json:"explanation"`
+	Pros           []string  `json:"pros"`
+	Cons           []string  `json:"cons"`
+	// CompanyResearch is "researched", "skipped_fresh" or "failed"
+	CompanyResearch string                 `json:"company_research"`
+	CompanyInfo     map[string]interface{} `json:"company_info"`
+	EvaluatedAt     string                 `json:"evaluated_at"`
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/service"
+	"github.com/jobping/backend/internal/features/user/usererr"
+)
+
+// maxEvaluateBody leaves room for a pasted description, which is cut to 5,000 characters anyway
+const maxEvaluateBody = 256 << 10
+
+// EvaluateJob scores a job given by URL or pasted text against one of the user's saved searches
+func (h *UserHandler) EvaluateJob(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req EvaluateJobRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxEvaluateBody)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	evaluation, err := h.evaluations.Evaluate(r.Context(), userID, service.EvaluateInput{
+		JobURL:        req.JobURL,
+		Title:         req.Title,
+		Company:       req.Company,
+		Location:      req.Location,
+		Description:   req.Description,
+		SavedSearchID: req.SavedSearchID,
+	})
+	if err != nil {
+		writeEvaluateError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toJobEvaluationResponse(evaluation))
+}
+
+func writeEvaluateError(w http.ResponseWriter, err error) {
+	if writeLockedError(w, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, usererr.ErrEvaluationInputMissing), errors.Is(err, usererr.ErrInvalidJobURL),
+		errors.Is(err, usererr.ErrInvalidSearchPrompt):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, usererr.ErrSavedSearchNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, usererr.ErrJobPageUnreadable):
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, usererr.ErrEvaluationFailed):
+		writeError(w, http.StatusBadGateway, err.Error())
+	case errors.Is(err, usererr.ErrEvaluationTimeout):
+		writeError(w, http.StatusGatewayTimeout, err.Error())
+	default:
+		log.Printf("Failed to evaluate job: %v", err)
+		writeError(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+func toJobEvaluationResponse(e *model.JobEvaluation) JobEvaluationResponse {
+	pros, cons := e.Pros, e.Cons
+	if pros == nil {
+		pros = []string{}
+	}
+	if cons == nil {
+		cons = []string{}
+	}
+	return JobEvaluationResponse{
+		JobID:           e.JobID,
+		JobURL:          e.JobURL,
+		Title:           e.Title,
+		Company:         e.Company,
+		Location:        e.Location,
+		Created:         e.Created,
+		SavedSearchID:   e.SavedSearchID,
+		SearchName:      e.SearchName,
+		MatchID:         e.MatchID,
+		Score:           e.Score,
+		Threshold:       e.Threshold,
+		AboveThreshold:  e.Score >= e.Threshold,
+		Explanation:     e.Explanation,
+		Pros:            pros,
+		Cons:            cons,
+		CompanyResearch: e.CompanyResearch,
+		CompanyInfo:     e.CompanyInfo,
+		EvaluatedAt:     e.EvaluatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/job/joberr"
+	"github.com/jobping/backend/internal/features/user/model"
+)
+
+// GetJobExplanation explains why the user was, or was not, notified about a job
+func (h *UserHandler) GetJobExplanation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	jobID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid job id")
+		return
+	}
+
+	explanation, err := h.explains.Explain(r.Context(), userID, jobID)
+	if err != nil {
+		if errors.Is(err, joberr.ErrJobNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		log.Printf("Failed to explain job %s for user %s: %v", jobID, userID, err)
+		writeError(w, http.StatusInternalServerError, "failed to explain job")
+		return
+	}
+	writeJSON(w, http.StatusOK, toJobExplanationResponse(explanation))
+}
+
+func toJobExplanationResponse(e *model.JobExplanation) JobExplanationResponse {
+	response := JobExplanationResponse{
+		JobID:    e.JobID,
+		Title:    e.Title,
+		Company:  e.Company,
+		Notified: e.Notified,
+		Steps:    toExplanationSteps(e.Steps),
+		Searches: make([]SearchExplanationResponse, len(e.Searches)),
+	}
+	for i, search := range e.Searches {
+		response.Searches[i] = SearchExplanationResponse{
+			SavedSearchID: search.SavedSearchID,
+			Name:          search.Name,
+			Verdict:       search.Verdict,
+			Reason:        search.Reason,
+			Steps:         toExplanationSteps(search.Steps),
+		}
+	}
+	return response
+}
+
+func toExplanationSteps(steps []model.ExplanationStep) []ExplanationStepResponse {
+	response := make([]ExplanationStepResponse, len(steps))
+	for i, step := range steps {
+		response[i] = ExplanationStepResponse{Step: step.Step, Status: step.Status, Detail: step.Detail}
+		if step.At != nil {
+			at := step.At.Format(time.RFC3339)
+			response[i].At = &at
+		}
+	}
+	return response
+}
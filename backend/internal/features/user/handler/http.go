@@ -3,23 +3,48 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/user/model"
 	"github.com/jobping/backend/internal/features/user/service"
 	"github.com/jobping/backend/internal/features/user/usererr"
 )
 
 type UserHandler struct {
-	service *service.UserService
-	auth    *AuthMiddleware
+	service     *service.UserService
+	sessions    *service.SessionService
+	accounts    *service.AccountService
+	oidc        *service.OIDCService
+	tokens      *service.APITokenService
+	privacy     *service.PrivacyService
+	searches    *service.SavedSearchService
+	rescores    *service.RescoreService
+	resumes     *service.ResumeService
+	explains    *service.ExplainService
+	evaluations *service.EvaluateService
+	auth        *AuthMiddleware
 }
 
-func NewUserHandler(svc *service.UserService, auth *AuthMiddleware) *UserHandler {
+func NewUserHandler(svc *service.UserService, sessions *service.SessionService, accounts *service.AccountService, oidcService *service.OIDCService, tokens *service.APITokenService, privacy *service.PrivacyService, searches *service.SavedSearchService, rescores *service.RescoreService, resumes *service.ResumeService, explains *service.ExplainService, evaluations *service.EvaluateService, auth *AuthMiddleware) *UserHandler {
 	return &UserHandler{
-		service: svc,
-		auth:    auth,
+		service:     svc,
+		sessions:    sessions,
+		accounts:    accounts,
+		oidc:        oidcService,
+		tokens:      tokens,
+		privacy:     privacy,
+		searches:    searches,
+		rescores:    rescores,
+		resumes:     resumes,
+		explains:    explains,
+		evaluations: evaluations,
+		auth:        auth,
 	}
 }
 
@@ -35,25 +60,39 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userEntity, err := h.service.Register(r.Context(), req.Username, req.Password)
+	userEntity, err := h.service.Register(r.Context(), req.Username, req.Password, req.Email, clientInfo(r))
 	if err != nil {
-		if errors.Is(err, usererr.ErrUserAlreadyExists) {
+		if writeLockedError(w, err) {
+			return
+		}
+		if errors.Is(err, usererr.ErrUserAlreadyExists) || errors.Is(err, usererr.ErrEmailAlreadyExists) {
 			writeError(w, http.StatusConflict, err.Error())
 			return
 		}
+		if errors.Is(err, usererr.ErrInvalidEmail) || errors.Is(err, usererr.ErrWeakPassword) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		writeError(w, http.StatusInternalServerError, "internal server error")
 		return
 	}
 
-	token, err := h.auth.GenerateToken(userEntity.ID)
+	// The account is usable without a verified email, so a failed send does not fail registration
+	if userEntity.Email != nil {
+		if err := h.accounts.SendVerification(r.Context(), userEntity); err != nil {
+			log.Printf("Failed to send verification email to user %s: %v", userEntity.ID, err)
+		}
+	}
+
+	tokens, err := h.startSession(r, userEntity)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "internal server error")
 		return
 	}
 
 	writeJSON(w, http.StatusCreated, AuthResponse{
-		Token: token,
-		User:  UserResponse{ID: userEntity.ID, Username: userEntity.Username},
+		TokenResponse: *tokens,
+		User:          UserResponse{ID: userEntity.ID, Username: userEntity.Username},
 	})
 }
 
@@ -64,25 +103,32 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userEntity, err := h.service.Authenticate(r.Context(), req.Username, req.Password)
+	userEntity, err := h.service.Authenticate(r.Context(), req.Username, req.Password, clientInfo(r))
 	if err != nil {
+		if writeLockedError(w, err) {
+			return
+		}
 		if errors.Is(err, usererr.ErrInvalidCredentials) {
 			writeError(w, http.StatusUnauthorized, err.Error())
 			return
 		}
+		if errors.Is(err, usererr.ErrAccountDisabled) {
+			writeError(w, http.StatusForbidden, err.Error())
+			return
+		}
 		writeError(w, http.StatusInternalServerError, "internal server error")
 		return
 	}
 
-	token, err := h.auth.GenerateToken(userEntity.ID)
+	tokens, err := h.startSession(r, userEntity)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "internal server error")
 		return
 	}
 
 	writeJSON(w, http.StatusOK, AuthResponse{
-		Token: token,
-		User:  UserResponse{ID: userEntity.ID, Username: userEntity.Username},
+		TokenResponse: *tokens,
+		User:          UserResponse{ID: userEntity.ID, Username: userEntity.Username},
 	})
 }
 
@@ -212,13 +258,27 @@ func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, ProfileResponse{
+	response := ProfileResponse{
 		ID:              user.ID,
 		Username:        user.Username,
+		Email:           user.Email,
+		EmailVerified:   user.EmailVerifiedAt != nil,
 		AIPrompt:        user.AIPrompt,
 		DiscordWebhook:  user.DiscordWebhook,
+		SlackWebhook:    user.SlackWebhook,
 		NotifyThreshold: user.NotifyThreshold,
-	})
+		NotificationSettings: NotificationSettingsResponse{
+			Timezone:                user.Timezone,
+			QuietHoursStart:         user.QuietHoursStart,
+			QuietHoursEnd:           user.QuietHoursEnd,
+			MaxNotificationsPerHour: user.MaxNotificationsPerHour,
+		},
+	}
+	if user.DeletionScheduledAt != nil {
+		deletionAt := user.DeletionScheduledAt.Format(time.RFC3339)
+		response.DeletionScheduledAt = &deletionAt
+	}
+	writeJSON(w, http.StatusOK, response)
 }
 
 func (h *UserHandler) UpdatePrompt(w http.ResponseWriter, r *http.Request) {
@@ -263,6 +323,27 @@ func (h *UserHandler) UpdateDiscord(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"message": "discord webhook updated"})
 }
 
+func (h *UserHandler) UpdateSlack(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req UpdateSlackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.service.UpdateSlackWebhook(r.Context(), userID, req.WebhookURL); err != nil {
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "slack webhook updated"})
+}
+
 func (h *UserHandler) UpdateThreshold(w http.ResponseWriter, r *http.Request) {
 	userID, ok := UserIDFromContext(r.Context())
 	if !ok {
@@ -289,6 +370,42 @@ func (h *UserHandler) UpdateThreshold(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"message": "threshold updated"})
 }
 
+func (h *UserHandler) UpdateNotificationSettings(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req UpdateNotificationSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.MaxNotificationsPerHour != nil && *req.MaxNotificationsPerHour < 0 {
+		writeError(w, http.StatusBadRequest, "max_notifications_per_hour must not be negative")
+		return
+	}
+
+	settings := &model.NotificationSettings{
+		Timezone:                req.Timezone,
+		QuietHoursStart:         req.QuietHoursStart,
+		QuietHoursEnd:           req.QuietHoursEnd,
+		MaxNotificationsPerHour: req.MaxNotificationsPerHour,
+	}
+	if err := h.service.UpdateNotificationSettings(r.Context(), userID, settings); err != nil {
+		if errors.Is(err, usererr.ErrInvalidTimezone) || errors.Is(err, usererr.ErrInvalidQuietHours) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "notification settings updated"})
+}
+
 func (h *UserHandler) GetMatches(w http.ResponseWriter, r *http.Request) {
 	userID, ok := UserIDFromContext(r.Context())
 	if !ok {
@@ -302,19 +419,25 @@ func (h *UserHandler) GetMatches(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	writeJSON(w, http.StatusOK, toMatchesResponse(matches))
+}
+
+func toMatchesResponse(matches []model.UserJobMatch) UserMatchesResponse {
 	response := UserMatchesResponse{Matches: make([]UserJobMatchResponse, len(matches))}
 	for i, m := range matches {
 		response.Matches[i] = UserJobMatchResponse{
-			ID:        m.ID,
-			JobID:     m.JobID,
-			Score:     m.Score,
-			Analysis:  m.Analysis,
-			Notified:  m.Notified,
-			CreatedAt: m.CreatedAt.Format("2006-01-02T15:04:05Z"),
+			ID:               m.ID,
+			SavedSearchID:    m.SavedSearchID,
+			JobID:            m.JobID,
+			Score:            m.Score,
+			Analysis:         m.Analysis,
+			Notified:         m.Notified,
+			DeferredReason:   m.DeferredReason,
+			PromptRevisionID: m.PromptRevisionID,
+			CreatedAt:        m.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		}
 	}
-
-	writeJSON(w, http.StatusOK, response)
+	return response
 }
 
 func writeJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -323,6 +446,17 @@ func writeJSON(w http.ResponseWriter, status int, data interface{}) {
 	json.NewEncoder(w).Encode(data)
 }
 
+// writeLockedError writes 429 with Retry-After when err is a rate limit lockout
+func writeLockedError(w http.ResponseWriter, err error) bool {
+	var locked *usererr.LockedError
+	if !errors.As(err, &locked) {
+		return false
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+	writeError(w, http.StatusTooManyRequests, locked.Error())
+	return true
+}
+
 func writeError(w http.ResponseWriter, status int, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -2,30 +2,57 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"log"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/user/jwtkeys"
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/service"
+	"github.com/jobping/backend/internal/features/user/usererr"
 )
 
 type contextKey string
 
-const UserIDKey contextKey = "userID"
+const (
+	UserIDKey    contextKey = "userID"
+	SessionIDKey contextKey = "sessionID"
+	RoleKey      contextKey = "role"
+	// ScopesKey is only set for requests authenticated with a personal API token
+	ScopesKey contextKey = "scopes"
+)
+
+// APITokenVerifier resolves personal API tokens (see service.APITokenService)
+type APITokenVerifier interface {
+	Authenticate(ctx context.Context, rawToken string) (*model.APIToken, *model.User, error)
+}
 
+// AuthMiddleware issues and validates short-lived access tokens. Long-lived sign-in is
+// handled by refresh tokens (see service.SessionService). It also accepts personal API
+// tokens, which are limited to the routes that declare one of their scopes.
 type AuthMiddleware struct {
-	jwtSecret string
-	expiry    time.Duration
+	keys   *jwtkeys.KeySet
+	expiry time.Duration
+	tokens APITokenVerifier
 }
 
-func NewAuthMiddleware(jwtSecret string, expiryHours int) *AuthMiddleware {
+func NewAuthMiddleware(keys *jwtkeys.KeySet, expiry time.Duration, tokens APITokenVerifier) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtSecret: jwtSecret,
-		expiry:    time.Duration(expiryHours) * time.Hour,
+		keys:   keys,
+		expiry: expiry,
+		tokens: tokens,
 	}
 }
 
+// Expiry is the lifetime of issued access tokens
+func (a *AuthMiddleware) Expiry() time.Duration {
+	return a.expiry
+}
+
 func (a *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
@@ -40,36 +67,159 @@ func (a *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 			return
 		}
 
+		if strings.HasPrefix(parts[1], service.APITokenPrefix) {
+			a.authenticateAPIToken(w, r, next, parts[1])
+			return
+		}
+
 		claims := &Claims{}
-		token, err := jwt.ParseWithClaims(parts[1], claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(a.jwtSecret), nil
-		})
+		token, err := jwt.ParseWithClaims(parts[1], claims, a.keys.Keyfunc,
+			jwt.WithValidMethods(a.keys.Algorithms()),
+			jwt.WithExpirationRequired(),
+		)
 
 		if err != nil || !token.Valid {
 			http.Error(w, `{"code":401,"message":"unauthorized"}`, http.StatusUnauthorized)
 			return
 		}
 
+		// Tokens issued before roles existed carry none; they are treated as regular users
+		role := claims.Role
+		if role == "" {
+			role = model.RoleUser
+		}
+
 		ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
+		ctx = context.WithValue(ctx, RoleKey, role)
+		if claims.SessionID != uuid.Nil {
+			ctx = context.WithValue(ctx, SessionIDKey, claims.SessionID)
+		}
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
-func (a *AuthMiddleware) GenerateToken(userID uuid.UUID) (string, error) {
+// OptionalAuthenticate serves anonymous requests as they are, for endpoints that show more to
+// signed-in users. A request that sends credentials must still pass Authenticate.
+func (a *AuthMiddleware) OptionalAuthenticate(next http.Handler) http.Handler {
+	authenticated := a.Authenticate(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		authenticated.ServeHTTP(w, r)
+	})
+}
+
+// authenticateAPIToken serves next as the token's user. API tokens never carry the admin role.
+func (a *AuthMiddleware) authenticateAPIToken(w http.ResponseWriter, r *http.Request, next http.Handler, rawToken string) {
+	if a.tokens == nil {
+		http.Error(w, `{"code":401,"message":"unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	token, _, err := a.tokens.Authenticate(r.Context(), rawToken)
+	switch {
+	case errors.Is(err, usererr.ErrInvalidToken):
+		http.Error(w, `{"code":401,"message":"unauthorized"}`, http.StatusUnauthorized)
+		return
+	case errors.Is(err, usererr.ErrAccountDisabled):
+		http.Error(w, `{"code":403,"message":"account is disabled"}`, http.StatusForbidden)
+		return
+	case err != nil:
+		log.Printf("Failed to verify API token: %v", err)
+		http.Error(w, `{"code":500,"message":"internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), UserIDKey, token.UserID)
+	ctx = context.WithValue(ctx, RoleKey, model.RoleUser)
+	ctx = context.WithValue(ctx, ScopesKey, token.Scopes)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GenerateToken issues an access token. sessionID is the refresh token family it was issued for.
+func (a *AuthMiddleware) GenerateToken(userID, sessionID uuid.UUID, role string) (string, error) {
 	claims := &Claims{
-		UserID: userID,
+		UserID:    userID,
+		SessionID: sessionID,
+		Role:      role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(a.expiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(a.jwtSecret))
+	return a.keys.Sign(claims)
+}
+
+// JWKS publishes the public verification keys at /.well-known/jwks.json
+func (a *AuthMiddleware) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	writeJSON(w, http.StatusOK, a.keys.JWKS())
+}
+
+// RequireRole allows only users whose access token carries one of roles. It must run after
+// Authenticate. Roles are read from the token, so a role change applies from the next refresh.
+func (a *AuthMiddleware) RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, ok := RoleFromContext(r.Context())
+			if !ok {
+				http.Error(w, `{"code":401,"message":"unauthorized"}`, http.StatusUnauthorized)
+				return
+			}
+			for _, allowed := range roles {
+				if role == allowed {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			http.Error(w, `{"code":403,"message":"forbidden"}`, http.StatusForbidden)
+		})
+	}
+}
+
+// RequireScope limits API tokens to those granted scope. Signed-in sessions pass unchecked.
+// It must run after Authenticate.
+func (a *AuthMiddleware) RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scopes, isAPIToken := ScopesFromContext(r.Context())
+			if isAPIToken && !containsScope(scopes, scope) {
+				writeError(w, http.StatusForbidden, "API token is missing the "+scope+" scope")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireSession rejects API tokens, for account security routes that only a signed-in
+// user may use (sessions, tokens, linked identities, email). It must run after Authenticate.
+func (a *AuthMiddleware) RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, isAPIToken := ScopesFromContext(r.Context()); isAPIToken {
+			writeError(w, http.StatusForbidden, "not available to API tokens")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func containsScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
 }
 
 type Claims struct {
-	UserID uuid.UUID `json:"user_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	SessionID uuid.UUID `json:"sid"`
+	Role      string    `json:"role"`
 	jwt.RegisteredClaims
 }
 
@@ -78,3 +228,21 @@ func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
 	return userID, ok
 }
 
+// SessionIDFromContext returns the session (refresh token family) the access token was issued for
+func SessionIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	sessionID, ok := ctx.Value(SessionIDKey).(uuid.UUID)
+	return sessionID, ok
+}
+
+// RoleFromContext returns the role from the access token
+func RoleFromContext(ctx context.Context) (string, bool) {
+	role, ok := ctx.Value(RoleKey).(string)
+	return role, ok
+}
+
+// ScopesFromContext returns the scopes of the API token the request was authenticated with.
+// It returns false for signed-in sessions, which are not scoped.
+func ScopesFromContext(ctx context.Context) ([]string, bool) {
+	scopes, ok := ctx.Value(ScopesKey).([]string)
+	return scopes, ok
+}
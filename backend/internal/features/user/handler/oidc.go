@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/oidc"
+	"github.com/jobping/backend/internal/features/user/usererr"
+)
+
+func (h *UserHandler) GetOIDCProviders(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, OIDCProvidersResponse{Providers: h.oidc.Providers()})
+}
+
+// StartOIDCLogin returns the provider URL to send the browser to. The frontend should keep the
+// state from that URL and check it against the one the provider redirects back with.
+func (h *UserHandler) StartOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	h.startOIDC(w, r, nil)
+}
+
+// OIDCCallback completes an external login with the code and state the provider redirected back with
+func (h *UserHandler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	var req OIDCCallbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	userEntity, created, err := h.oidc.CompleteLogin(r.Context(), req.Code, req.State)
+	if err != nil {
+		writeOIDCError(w, err)
+		return
+	}
+
+	tokens, err := h.startSession(r, userEntity)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, AuthResponse{
+		TokenResponse: *tokens,
+		User:          UserResponse{ID: userEntity.ID, Username: userEntity.Username},
+	})
+}
+
+func (h *UserHandler) GetIdentities(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	identities, err := h.oidc.GetIdentities(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	response := IdentitiesResponse{Identities: make([]IdentityResponse, 0, len(identities))}
+	for _, i := range identities {
+		response.Identities = append(response.Identities, toIdentityResponse(i))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// StartLinkIdentity begins linking another provider to the signed-in account
+func (h *UserHandler) StartLinkIdentity(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	h.startOIDC(w, r, &userID)
+}
+
+func (h *UserHandler) LinkIdentityCallback(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req OIDCCallbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	identity, err := h.oidc.CompleteLink(r.Context(), userID, req.Code, req.State)
+	if err != nil {
+		writeOIDCError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toIdentityResponse(*identity))
+}
+
+func (h *UserHandler) UnlinkIdentity(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	identityID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid identity id")
+		return
+	}
+
+	if err := h.oidc.Unlink(r.Context(), userID, identityID); err != nil {
+		switch {
+		case errors.Is(err, usererr.ErrIdentityNotFound):
+			writeError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, usererr.ErrLastLoginMethod):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *UserHandler) startOIDC(w http.ResponseWriter, r *http.Request, linkUserID *uuid.UUID) {
+	authURL, err := h.oidc.Begin(r.Context(), chi.URLParam(r, "provider"), linkUserID)
+	if err != nil {
+		writeOIDCError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, OIDCStartResponse{AuthorizationURL: authURL})
+}
+
+func writeOIDCError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usererr.ErrAccountDisabled):
+		writeError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, usererr.ErrUnknownProvider):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, usererr.ErrInvalidLoginState):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, usererr.ErrIdentityLinked), errors.Is(err, usererr.ErrEmailNeedsLinking):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, oidc.ErrExchange):
+		// Provider details stay in the log; the client only learns that the login failed
+		log.Printf("External login failed: %v", err)
+		writeError(w, http.StatusUnauthorized, oidc.ErrExchange.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+func toIdentityResponse(i model.UserIdentity) IdentityResponse {
+	resp := IdentityResponse{
+		ID:        i.ID,
+		Provider:  i.Provider,
+		CreatedAt: i.CreatedAt.Format(time.RFC3339),
+	}
+	if i.Email != nil {
+		resp.Email = *i.Email
+	}
+	if i.LastLoginAt != nil {
+		resp.LastLoginAt = i.LastLoginAt.Format(time.RFC3339)
+	}
+	return resp
+}
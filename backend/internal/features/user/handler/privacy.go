@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/jobping/backend/internal/features/user/usererr"
+)
+
+// ExportData returns everything stored about the user as one JSON document, or with
+// ?format=zip as an archive holding one JSON file per section.
+func (h *UserHandler) ExportData(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "zip" {
+		writeError(w, http.StatusBadRequest, "format must be json or zip")
+		return
+	}
+
+	sections, err := h.privacy.Export(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	filename := fmt.Sprintf("jobping-export-%s", time.Now().UTC().Format("2006-01-02"))
+	w.Header().Set("Cache-Control", "no-store")
+
+	if format != "zip" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, filename))
+		writeJSON(w, http.StatusOK, sections)
+		return
+	}
+
+	names := make([]string, 0, len(sections))
+	for name := range sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, filename))
+	w.WriteHeader(http.StatusOK)
+
+	archive := zip.NewWriter(w)
+	for _, name := range names {
+		f, err := archive.Create(name + ".json")
+		if err != nil {
+			log.Printf("Failed to write data export for user %s: %v", userID, err)
+			return
+		}
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, sections[name], "", "  "); err != nil {
+			log.Printf("Failed to write data export for user %s: %v", userID, err)
+			return
+		}
+		if _, err := buf.WriteTo(f); err != nil {
+			log.Printf("Failed to write data export for user %s: %v", userID, err)
+			return
+		}
+	}
+	if err := archive.Close(); err != nil {
+		log.Printf("Failed to write data export for user %s: %v", userID, err)
+	}
+}
+
+// DeleteAccount schedules the account for deletion after the grace period and signs the user out everywhere
+func (h *UserHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	deleteAt, err := h.privacy.RequestDeletion(r.Context(), userID, req.Confirm, req.Password, clientInfo(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, usererr.ErrDeletionNotConfirmed):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, usererr.ErrDeletionScheduled):
+			writeError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, usererr.ErrUserNotFound):
+			writeError(w, http.StatusNotFound, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, DeleteAccountResponse{
+		DeletionScheduledAt: deleteAt.Format(time.RFC3339),
+	})
+}
+
+func (h *UserHandler) CancelDeletion(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.privacy.CancelDeletion(r.Context(), userID, clientInfo(r)); err != nil {
+		switch {
+		case errors.Is(err, usererr.ErrDeletionNotScheduled):
+			writeError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, usererr.ErrUserNotFound):
+			writeError(w, http.StatusNotFound, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/usererr"
+)
+
+// GetPromptHistory lists the prompts a saved search has had
+func (h *UserHandler) GetPromptHistory(w http.ResponseWriter, r *http.Request) {
+	userID, searchID, ok := savedSearchParams(w, r)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.searches.GetPromptHistory(r.Context(), userID, searchID)
+	if err != nil {
+		writeSavedSearchError(w, err)
+		return
+	}
+
+	response := PromptHistoryResponse{Revisions: make([]PromptRevisionResponse, len(revisions))}
+	for i, rev := range revisions {
+		response.Revisions[i] = PromptRevisionResponse{
+			ID:        rev.ID,
+			AIPrompt:  rev.AIPrompt,
+			CreatedAt: rev.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// StartRescore queues a saved search's recent jobs for scoring against its current prompt
+func (h *UserHandler) StartRescore(w http.ResponseWriter, r *http.Request) {
+	userID, searchID, ok := savedSearchParams(w, r)
+	if !ok {
+		return
+	}
+
+	// The body is optional
+	var req StartRescoreRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	run, err := h.rescores.Start(r.Context(), userID, searchID, req.Days)
+	if err != nil {
+		writeRescoreError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, toRescoreResponse(*run))
+}
+
+func (h *UserHandler) GetRescores(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	runs, err := h.rescores.List(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	response := RescoresResponse{Rescores: make([]RescoreResponse, len(runs))}
+	for i, run := range runs {
+		response.Rescores[i] = toRescoreResponse(run)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// GetRescore reports the progress of one rescore run
+func (h *UserHandler) GetRescore(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	runID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid rescore id")
+		return
+	}
+
+	run, err := h.rescores.Get(r.Context(), userID, runID)
+	if err != nil {
+		writeRescoreError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toRescoreResponse(*run))
+}
+
+func writeRescoreError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usererr.ErrSavedSearchNotFound), errors.Is(err, usererr.ErrRescoreNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, usererr.ErrInvalidRescoreDays):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, usererr.ErrSearchInactive), errors.Is(err, usererr.ErrRescoreInProgress):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, usererr.ErrRescoreUnavailable):
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+func toRescoreResponse(run model.RescoreRun) RescoreResponse {
+	response := RescoreResponse{
+		ID:               run.ID,
+		SavedSearchID:    run.SavedSearchID,
+		PromptRevisionID: run.PromptRevisionID,
+		Days:             run.Days,
+		Status:           "running",
+		Total:            run.Total,
+		Completed:        run.Completed,
+		Failed:           run.Failed,
+		CreatedAt:        run.CreatedAt.Format(time.RFC3339),
+	}
+	if run.FinishedAt != nil {
+		response.Status = "finished"
+		finishedAt := run.FinishedAt.Format(time.RFC3339)
+		response.FinishedAt = &finishedAt
+	}
+	return response
+}
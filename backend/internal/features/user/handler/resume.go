@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/service"
+	"github.com/jobping/backend/internal/features/user/usererr"
+)
+
+// UploadResume accepts a resume as the "file" field of a multipart form, or as the raw request
+// body with ?filename=. apply=true (form field or query) also sets the suggested prompt.
+func (h *UserHandler) UploadResume(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	// Room for the multipart envelope on top of the file itself
+	r.Body = http.MaxBytesReader(w, r.Body, service.MaxResumeSize+64<<10)
+
+	var fileName string
+	var data []byte
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			writeResumeError(w, resumeReadError(err))
+			return
+		}
+		defer file.Close()
+		fileName = header.Filename
+		if data, err = io.ReadAll(file); err != nil {
+			writeResumeError(w, resumeReadError(err))
+			return
+		}
+	} else {
+		fileName = r.URL.Query().Get("filename")
+		var err error
+		if data, err = io.ReadAll(r.Body); err != nil {
+			writeResumeError(w, resumeReadError(err))
+			return
+		}
+	}
+
+	apply, _ := strconv.ParseBool(r.FormValue("apply"))
+	// Setting the prompt is a filters change, which API tokens need their own scope for
+	if scopes, isAPIToken := ScopesFromContext(r.Context()); apply && isAPIToken && !containsScope(scopes, model.ScopeWriteFilters) {
+		writeError(w, http.StatusForbidden, "API token is missing the "+model.ScopeWriteFilters+" scope")
+		return
+	}
+
+	profile, err := h.resumes.Upload(r.Context(), userID, fileName, data, apply)
+	if err != nil {
+		writeResumeError(w, err)
+		return
+	}
+
+	response := toResumeProfileResponse(*profile)
+	response.Applied = apply && profile.SuggestedPrompt != ""
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *UserHandler) GetResume(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	profile, err := h.resumes.Get(r.Context(), userID)
+	if err != nil {
+		writeResumeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toResumeProfileResponse(*profile))
+}
+
+func (h *UserHandler) DeleteResume(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.resumes.Delete(r.Context(), userID); err != nil {
+		writeResumeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// resumeReadError maps a failure to read the upload to the error reported to the client
+func resumeReadError(err error) error {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		return usererr.ErrResumeTooLarge
+	case errors.Is(err, http.ErrMissingFile):
+		return usererr.ErrResumeMissing
+	}
+	return err
+}
+
+func writeResumeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usererr.ErrResumeNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, usererr.ErrResumeMissing):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, usererr.ErrResumeTooLarge):
+		writeError(w, http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, usererr.ErrUnsupportedResume):
+		writeError(w, http.StatusUnsupportedMediaType, err.Error())
+	case errors.Is(err, usererr.ErrResumeUnreadable):
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, usererr.ErrResumeAnalysisFailed):
+		writeError(w, http.StatusBadGateway, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+func toResumeProfileResponse(p model.ResumeProfile) ResumeProfileResponse {
+	skills, locations := p.Skills, p.Locations
+	if skills == nil {
+		skills = []string{}
+	}
+	if locations == nil {
+		locations = []string{}
+	}
+	return ResumeProfileResponse{
+		FileName:        p.FileName,
+		Format:          p.Format,
+		Skills:          skills,
+		Seniority:       p.Seniority,
+		Locations:       locations,
+		SalaryMin:       p.SalaryMin,
+		SalaryMax:       p.SalaryMax,
+		SalaryCurrency:  p.SalaryCurrency,
+		SuggestedPrompt: p.SuggestedPrompt,
+		CreatedAt:       p.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       p.UpdatedAt.Format(time.RFC3339),
+	}
+}
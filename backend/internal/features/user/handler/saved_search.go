@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/service"
+	"github.com/jobping/backend/internal/features/user/usererr"
+)
+
+func (h *UserHandler) GetSavedSearches(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	searches, err := h.searches.List(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	response := SavedSearchesResponse{
+		Searches:          make([]SavedSearchResponse, 0, len(searches)),
+		AvailableChannels: model.Channels,
+	}
+	for _, s := range searches {
+		response.Searches = append(response.Searches, toSavedSearchResponse(s))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *UserHandler) GetSavedSearch(w http.ResponseWriter, r *http.Request) {
+	userID, searchID, ok := savedSearchParams(w, r)
+	if !ok {
+		return
+	}
+
+	search, err := h.searches.Get(r.Context(), userID, searchID)
+	if err != nil {
+		writeSavedSearchError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toSavedSearchResponse(*search))
+}
+
+func (h *UserHandler) CreateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req SavedSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	search, err := h.searches.Create(r.Context(), userID, toSavedSearchInput(req))
+	if err != nil {
+		writeSavedSearchError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, toSavedSearchResponse(*search))
+}
+
+func (h *UserHandler) UpdateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	userID, searchID, ok := savedSearchParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req SavedSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	search, err := h.searches.Update(r.Context(), userID, searchID, toSavedSearchInput(req))
+	if err != nil {
+		writeSavedSearchError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toSavedSearchResponse(*search))
+}
+
+func (h *UserHandler) DeleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	userID, searchID, ok := savedSearchParams(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.searches.Delete(r.Context(), userID, searchID); err != nil {
+		writeSavedSearchError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *UserHandler) GetSavedSearchMatches(w http.ResponseWriter, r *http.Request) {
+	userID, searchID, ok := savedSearchParams(w, r)
+	if !ok {
+		return
+	}
+
+	matches, err := h.searches.GetMatches(r.Context(), userID, searchID)
+	if err != nil {
+		writeSavedSearchError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toMatchesResponse(matches))
+}
+
+// savedSearchParams reads the caller and the {id} URL parameter, writing the error response if either is missing
+func savedSearchParams(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+	searchID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid saved search id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, searchID, true
+}
+
+func writeSavedSearchError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usererr.ErrSavedSearchNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, usererr.ErrInvalidSearchName), errors.Is(err, usererr.ErrInvalidSearchPrompt),
+		errors.Is(err, usererr.ErrInvalidThreshold), errors.Is(err, usererr.ErrInvalidChannel),
+		errors.Is(err, usererr.ErrUnknownLocation), errors.Is(err, usererr.ErrInvalidRadius):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, usererr.ErrSearchNameTaken), errors.Is(err, usererr.ErrTooManySavedSearches):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+func toSavedSearchInput(req SavedSearchRequest) service.SavedSearchInput {
+	return service.SavedSearchInput{
+		Name:            req.Name,
+		AIPrompt:        req.AIPrompt,
+		Filters:         req.Filters,
+		NotifyThreshold: req.NotifyThreshold,
+		Channels:        req.Channels,
+		Active:          req.Active,
+	}
+}
+
+func toSavedSearchResponse(s model.SavedSearch) SavedSearchResponse {
+	channels := s.Channels
+	if channels == nil {
+		channels = []string{}
+	}
+	return SavedSearchResponse{
+		ID:              s.ID,
+		Name:            s.Name,
+		AIPrompt:        s.AIPrompt,
+		Filters:         s.Filters,
+		NotifyThreshold: s.NotifyThreshold,
+		Channels:        channels,
+		Active:          s.Active,
+		IsDefault:       s.IsDefault,
+		CreatedAt:       s.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       s.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/clientip"
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/service"
+	"github.com/jobping/backend/internal/features/user/usererr"
+)
+
+// Refresh exchanges a refresh token for a new access token and a new refresh token.
+// The presented refresh token stops working; replaying it revokes the session.
+func (h *UserHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		writeError(w, http.StatusBadRequest, "refresh_token is required")
+		return
+	}
+
+	session, refreshToken, err := h.sessions.Refresh(r.Context(), req.RefreshToken, clientInfo(r))
+	if err != nil {
+		if errors.Is(err, usererr.ErrInvalidRefreshToken) || errors.Is(err, usererr.ErrRefreshTokenReused) {
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	// The role is re-read on every refresh so role changes and disabling take effect here
+	user, err := h.service.GetActiveUser(r.Context(), session.UserID)
+	if err != nil {
+		if errors.Is(err, usererr.ErrAccountDisabled) || errors.Is(err, usererr.ErrUserNotFound) {
+			if revokeErr := h.sessions.RevokeSession(r.Context(), session.UserID, session.FamilyID); revokeErr != nil {
+				log.Printf("Failed to revoke session %s of inactive user: %v", session.FamilyID, revokeErr)
+			}
+			writeError(w, http.StatusForbidden, usererr.ErrAccountDisabled.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	tokens, err := h.tokenResponse(session, refreshToken, user.Role)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tokens)
+}
+
+// Logout revokes the session of the given refresh token. It succeeds for unknown tokens so
+// clients can always clear local state.
+func (h *UserHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		writeError(w, http.StatusBadRequest, "refresh_token is required")
+		return
+	}
+
+	if err := h.sessions.Logout(r.Context(), req.RefreshToken); err != nil {
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LogoutAll revokes every session of the caller. Access tokens already issued stay valid until they expire.
+func (h *UserHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	revoked, err := h.sessions.LogoutAll(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]int64{"revoked": revoked})
+}
+
+func (h *UserHandler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	currentID, _ := SessionIDFromContext(r.Context())
+
+	sessions, err := h.sessions.GetSessions(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	response := SessionsResponse{Sessions: make([]SessionResponse, len(sessions))}
+	for i, s := range sessions {
+		response.Sessions[i] = toSessionResponse(s, currentID)
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *UserHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	sessionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid session id")
+		return
+	}
+
+	if err := h.sessions.RevokeSession(r.Context(), userID, sessionID); err != nil {
+		if errors.Is(err, usererr.ErrSessionNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// startSession begins a session for a fresh login and issues its tokens
+func (h *UserHandler) startSession(r *http.Request, user *model.User) (*TokenResponse, error) {
+	session, refreshToken, err := h.sessions.Start(r.Context(), user.ID, clientInfo(r))
+	if err != nil {
+		return nil, err
+	}
+	return h.tokenResponse(session, refreshToken, user.Role)
+}
+
+func (h *UserHandler) tokenResponse(session *model.Session, refreshToken, role string) (*TokenResponse, error) {
+	token, err := h.auth.GenerateToken(session.UserID, session.FamilyID, role)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(h.auth.Expiry().Seconds()),
+	}, nil
+}
+
+func toSessionResponse(s model.Session, currentID uuid.UUID) SessionResponse {
+	return SessionResponse{
+		ID:         s.FamilyID,
+		UserAgent:  s.UserAgent,
+		IPAddress:  s.IPAddress,
+		Current:    s.FamilyID == currentID,
+		StartedAt:  s.StartedAt.Format(time.RFC3339),
+		LastUsedAt: s.CreatedAt.Format(time.RFC3339),
+		ExpiresAt:  s.ExpiresAt.Format(time.RFC3339),
+	}
+}
+
+func clientInfo(r *http.Request) service.ClientInfo {
+	return service.ClientInfo{
+		UserAgent: r.UserAgent(),
+		IPAddress: clientip.FromRequest(r),
+	}
+}
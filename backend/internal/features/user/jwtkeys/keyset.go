@@ -0,0 +1,228 @@
+// Package jwtkeys loads the keys used to sign and verify access tokens.
+//
+// Tokens are signed with RS256 or EdDSA and carry a kid header: the RFC 7638 thumbprint
+// of the signing key's public half, so issuers and verifiers agree on key IDs without
+// extra configuration. Extra verification keys let tokens signed by a retired key keep
+// working until they expire. With no keys configured the set falls back to HS256 with
+// the shared JWT_SECRET (local development only).
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key is one signing or verification key. ID is empty only for the legacy HS256 secret.
+type Key struct {
+	ID        string
+	Algorithm string
+	public    crypto.PublicKey
+	private   crypto.Signer
+	secret    []byte
+}
+
+// KeySet signs with one key and verifies with any of its keys
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+}
+
+var (
+	ErrNoSigningKey = errors.New("no JWT signing key configured")
+	ErrUnknownKey   = errors.New("unknown JWT key id")
+)
+
+// NewHMAC returns a key set using a shared HS256 secret, for local development
+func NewHMAC(secret string) *KeySet {
+	key := &Key{Algorithm: jwt.SigningMethodHS256.Alg(), secret: []byte(secret)}
+	return &KeySet{signing: key, keys: map[string]*Key{"": key}}
+}
+
+// NewKeySet builds a key set from an optional private signing key and any number of public keys
+func NewKeySet(signer crypto.Signer, verify ...crypto.PublicKey) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*Key)}
+
+	if signer != nil {
+		key, err := newKey(signer.Public())
+		if err != nil {
+			return nil, err
+		}
+		key.private = signer
+		ks.signing = key
+		ks.keys[key.ID] = key
+	}
+
+	for _, pub := range verify {
+		key, err := newKey(pub)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := ks.keys[key.ID]; !exists {
+			ks.keys[key.ID] = key
+		}
+	}
+
+	if len(ks.keys) == 0 {
+		return nil, errors.New("key set needs at least one key")
+	}
+	return ks, nil
+}
+
+func newKey(pub crypto.PublicKey) (*Key, error) {
+	var alg string
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		if k.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("RSA key is %d bits, need at least 2048", k.N.BitLen())
+		}
+		alg = jwt.SigningMethodRS256.Alg()
+	case ed25519.PublicKey:
+		alg = jwt.SigningMethodEdDSA.Alg()
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", pub)
+	}
+
+	kid, err := thumbprint(pub)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{ID: kid, Algorithm: alg, public: pub}, nil
+}
+
+// Sign signs claims with the signing key, setting the kid header
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	key := ks.signing
+	if key == nil {
+		return "", ErrNoSigningKey
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	if key.secret != nil {
+		return token.SignedString(key.secret)
+	}
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.private)
+}
+
+// Keyfunc resolves the verification key for a token by kid and rejects any algorithm other
+// than the one that key was loaded for, so an RSA public key can never be used as an HMAC secret.
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method %q for key %q", token.Method.Alg(), kid)
+	}
+	if key.secret != nil {
+		return key.secret, nil
+	}
+	return key.public, nil
+}
+
+// Algorithms lists the algorithms of all verification keys, for jwt.WithValidMethods
+func (ks *KeySet) Algorithms() []string {
+	seen := make(map[string]bool)
+	var algs []string
+	for _, key := range ks.keys {
+		if !seen[key.Algorithm] {
+			seen[key.Algorithm] = true
+			algs = append(algs, key.Algorithm)
+		}
+	}
+	sort.Strings(algs)
+	return algs
+}
+
+// SigningKeyID returns the kid of the signing key, or "" for HS256 or a verify-only set
+func (ks *KeySet) SigningKeyID() string {
+	if ks.signing == nil {
+		return ""
+	}
+	return ks.signing.ID
+}
+
+// JWK is a public key in JSON Web Key format
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public verification keys. The HS256 secret is never published.
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for _, key := range ks.keys {
+		if key.public == nil {
+			continue
+		}
+		jwk := JWK{KeyID: key.ID, Use: "sig", Algorithm: key.Algorithm}
+		switch pub := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = b64(pub.N.Bytes())
+			jwk.E = b64(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = b64(pub)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+	return set
+}
+
+// thumbprint computes the RFC 7638 JWK thumbprint: SHA-256 over the required members in lexical order
+func thumbprint(pub crypto.PublicKey) (string, error) {
+	var members interface{}
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{b64(big.NewInt(int64(k.E)).Bytes()), "RSA", b64(k.N.Bytes())}
+	case ed25519.PublicKey:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{"Ed25519", "OKP", b64(k)}
+	default:
+		return "", fmt.Errorf("unsupported key type %T", pub)
+	}
+
+	body, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(body)
+	return b64(sum[:]), nil
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
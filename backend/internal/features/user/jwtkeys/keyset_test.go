@@ -0,0 +1,172 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestThumbprint(t *testing.T) {
+	// The example keys from RFC 7638 section 3.1 and RFC 8037 appendix A.3
+	n, _ := base64.RawURLEncoding.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	x, _ := base64.RawURLEncoding.DecodeString("11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo")
+
+	tests := []struct {
+		name string
+		pub  crypto.PublicKey
+		kid  string
+	}{
+		{"RSA", &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"},
+		{"Ed25519", ed25519.PublicKey(x), "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := thumbprint(tt.pub)
+			if err != nil {
+				t.Fatalf("thumbprint: %v", err)
+			}
+			if got != tt.kid {
+				t.Errorf("thumbprint = %s, want %s", got, tt.kid)
+			}
+		})
+	}
+}
+
+func newEd25519(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv
+}
+
+func sign(t *testing.T, ks *KeySet) string {
+	t.Helper()
+	token, err := ks.Sign(jwt.RegisteredClaims{Subject: "user", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))})
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	return token
+}
+
+func verify(ks *KeySet, token string) error {
+	_, err := jwt.ParseWithClaims(token, &jwt.RegisteredClaims{}, ks.Keyfunc, jwt.WithValidMethods(ks.Algorithms()))
+	return err
+}
+
+func TestKeyRotation(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldKey, newKey, otherKey := newEd25519(t), newEd25519(t), newEd25519(t)
+
+	old, _ := NewKeySet(oldKey)
+	rotated, _ := NewKeySet(newKey, oldKey.Public())
+	other, _ := NewKeySet(otherKey)
+	withRSA, _ := NewKeySet(rsaKey)
+	verifyOnly, _ := NewKeySet(nil, newKey.Public())
+	hmac := NewHMAC("secret")
+
+	tests := []struct {
+		name   string
+		signer *KeySet
+		ks     *KeySet
+		valid  bool
+	}{
+		{"same key", old, old, true},
+		{"RSA", withRSA, withRSA, true},
+		{"retired key still verifies", old, rotated, true},
+		{"new key", rotated, rotated, true},
+		{"new key on a verify-only set", rotated, verifyOnly, true},
+		{"new key on the old set", rotated, old, false},
+		{"unknown key", other, rotated, false},
+		{"HS256 token on an asymmetric set", hmac, rotated, false},
+		{"asymmetric token on the HS256 set", rotated, hmac, false},
+		{"HS256", hmac, hmac, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verify(tt.ks, sign(t, tt.signer))
+			if tt.valid && err != nil {
+				t.Errorf("verify: %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("verify accepted the token")
+			}
+		})
+	}
+
+	if _, err := verifyOnly.Sign(jwt.RegisteredClaims{}); !errors.Is(err, ErrNoSigningKey) {
+		t.Errorf("verify-only Sign: err = %v, want ErrNoSigningKey", err)
+	}
+}
+
+func TestKeyfuncRejectsAlgorithmSwitch(t *testing.T) {
+	ks, _ := NewKeySet(newEd25519(t))
+
+	// A token claiming the Ed25519 kid but signed with HS256, using the public key as the secret
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{Subject: "user"})
+	token.Header["kid"] = ks.SigningKeyID()
+	signed, err := token.SignedString([]byte(ks.signing.public.(ed25519.PublicKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jwt.Parse(signed, ks.Keyfunc); err == nil {
+		t.Error("Keyfunc accepted an HS256 token for an Ed25519 key")
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	signing, retired := newEd25519(t), newEd25519(t)
+	ks, err := NewKeySet(signing, retired.Public(), signing.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwks := ks.JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("JWKS has %d keys, want the signing and retired keys once each", len(jwks.Keys))
+	}
+	if jwks.Keys[0].KeyID > jwks.Keys[1].KeyID {
+		t.Error("JWKS keys are not sorted by kid")
+	}
+	found := false
+	for _, key := range jwks.Keys {
+		if key.KeyType != "OKP" || key.Curve != "Ed25519" || key.Algorithm != "EdDSA" || key.Use != "sig" {
+			t.Errorf("key = %+v", key)
+		}
+		found = found || key.KeyID == ks.SigningKeyID()
+	}
+	if !found {
+		t.Errorf("JWKS does not publish the signing key %s", ks.SigningKeyID())
+	}
+
+	if keys := NewHMAC("secret").JWKS().Keys; len(keys) != 0 {
+		t.Errorf("HS256 JWKS = %+v, want no keys published", keys)
+	}
+}
+
+func TestNewKeySetRejectsWeakKeys(t *testing.T) {
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewKeySet(small); err == nil {
+		t.Error("NewKeySet accepted a 1024-bit RSA key")
+	}
+	if _, err := NewKeySet(nil); err == nil {
+		t.Error("NewKeySet accepted an empty key set")
+	}
+}
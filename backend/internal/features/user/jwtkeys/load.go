@@ -0,0 +1,134 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/jobping/backend/internal/config"
+)
+
+// Load builds the key set from config. Without asymmetric keys it falls back to HS256 with
+// JWT_SECRET, and refuses to do so in production while the secret is still the default.
+func Load(cfg *config.Config) (*KeySet, error) {
+	signingPEM, err := readPEM(cfg.JWTSigningKey, cfg.JWTSigningKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read JWT signing key: %w", err)
+	}
+
+	verifyPEM := []byte(unescape(cfg.JWTVerifyKeys))
+	for _, path := range cfg.JWTVerifyKeyFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read JWT verify key: %w", err)
+		}
+		verifyPEM = append(verifyPEM, '\n')
+		verifyPEM = append(verifyPEM, data...)
+	}
+
+	if len(signingPEM) == 0 && len(strings.TrimSpace(string(verifyPEM))) == 0 {
+		if cfg.IsProduction() && cfg.JWTSecret == config.DefaultJWTSecret {
+			return nil, errors.New("refusing to start in production with the default JWT_SECRET: set JWT_SIGNING_KEY or JWT_SIGNING_KEY_FILE")
+		}
+		if cfg.IsProduction() {
+			log.Printf("WARNING: signing access tokens with the shared HS256 JWT_SECRET; configure JWT_SIGNING_KEY instead")
+		}
+		return NewHMAC(cfg.JWTSecret), nil
+	}
+
+	var signer crypto.Signer
+	if len(signingPEM) > 0 {
+		if signer, err = parsePrivateKey(signingPEM); err != nil {
+			return nil, fmt.Errorf("parse JWT signing key: %w", err)
+		}
+	}
+
+	verify, err := parsePublicKeys(verifyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parse JWT verify keys: %w", err)
+	}
+
+	ks, err := NewKeySet(signer, verify...)
+	if err != nil {
+		return nil, err
+	}
+	if kid := ks.SigningKeyID(); kid != "" {
+		log.Printf("Signing access tokens with %s key %s (%d verification keys)", ks.signing.Algorithm, kid, len(ks.keys))
+	}
+	return ks, nil
+}
+
+// readPEM returns the inline value, or the file contents when only a path is set
+func readPEM(inline, path string) ([]byte, error) {
+	if inline != "" {
+		return []byte(unescape(inline)), nil
+	}
+	if path == "" {
+		return nil, nil
+	}
+	return os.ReadFile(path)
+}
+
+// unescape allows PEM in single-line env vars with literal \n separators
+func unescape(s string) string {
+	return strings.ReplaceAll(s, `\n`, "\n")
+}
+
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
+func parsePublicKeys(data []byte) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		var key interface{}
+		var err error
+		switch block.Type {
+		case "PUBLIC KEY":
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		default:
+			return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
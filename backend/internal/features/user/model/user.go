@@ -1,30 +1,298 @@
 package model
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/geo"
 )
 
 type User struct {
 	ID              uuid.UUID
 	Username        string
 	PasswordHash    string
+	Email           *string
+	EmailVerifiedAt *time.Time
 	AIPrompt        *string
 	DiscordWebhook  *string
+	SlackWebhook    *string
 	NotifyThreshold int
+	// Delivery settings for instant notifications. QuietHoursStart/End are
+	// hours of the day (0-23) in Timezone; nil means no quiet hours.
+	Timezone                string
+	QuietHoursStart         *int
+	QuietHoursEnd           *int
+	MaxNotificationsPerHour *int
+	// Role is RoleUser or RoleAdmin. A disabled user cannot sign in.
+	Role       string
+	DisabledAt *time.Time
+	// DeletionScheduledAt is when the account will be purged; nil unless the user asked for deletion
+	DeletionScheduledAt *time.Time
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// ValidRole reports whether role is a known role
+func ValidRole(role string) bool {
+	return role == RoleUser || role == RoleAdmin
+}
+
+// Scopes of personal API tokens. Signed-in sessions are not scoped.
+const (
+	ScopeReadProfile        = "read:profile"
+	ScopeWriteProfile       = "write:profile"
+	ScopeReadFilters        = "read:filters"
+	ScopeWriteFilters       = "write:filters"
+	ScopeReadMatches        = "read:matches"
+	ScopeReadNotifications  = "read:notifications"
+	ScopeWriteNotifications = "write:notifications"
+)
+
+// Scopes lists every API token scope
+var Scopes = []string{
+	ScopeReadProfile,
+	ScopeWriteProfile,
+	ScopeReadFilters,
+	ScopeWriteFilters,
+	ScopeReadMatches,
+	ScopeReadNotifications,
+	ScopeWriteNotifications,
+}
+
+// ValidScope reports whether scope is a known API token scope
+func ValidScope(scope string) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// UserFilter selects users for admin listing. Query matches username or email.
+type UserFilter struct {
+	Query  string
+	Role   string
+	Limit  int
+	Offset int
+}
+
+// NotificationSettings is the user-editable subset of delivery settings
+type NotificationSettings struct {
+	Timezone                string
+	QuietHoursStart         *int
+	QuietHoursEnd           *int
+	MaxNotificationsPerHour *int
+}
+
+type UserJobMatch struct {
+	ID            uuid.UUID
+	UserID        uuid.UUID
+	SavedSearchID uuid.UUID
+	JobID         uuid.UUID
+	Score         int
+	Analysis      map[string]interface{}
+	Notified      bool
+	// DeferredAt is set when instant delivery was held back and the match
+	// is waiting for the next digest
+	DeferredAt     *time.Time
+	DeferredReason *string
+	// PromptRevisionID is the prompt the score was computed against; nil for matches
+	// scored before prompt history was kept
+	PromptRevisionID *uuid.UUID
+	CreatedAt        time.Time
+}
+
+// SavedSearch is one named job search. Each active search is matched against new jobs
+// on its own, with its own threshold and notification channels.
+type SavedSearch struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	Name            string
+	AIPrompt        string
+	Filters         SearchFilters
+	NotifyThreshold int
+	Channels        []string
+	Active          bool
+	// IsDefault marks the search behind the legacy AIPrompt and NotifyThreshold on User
+	IsDefault bool
+	// PromptRevisionID is the revision holding the current AIPrompt, nil while it is empty
+	PromptRevisionID *uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// PromptRevision is one prompt a saved search has had
+type PromptRevision struct {
+	ID            uuid.UUID
+	SavedSearchID uuid.UUID
+	UserID        uuid.UUID
+	AIPrompt      string
+	CreatedAt     time.Time
+}
+
+// RescoreRun tracks recent jobs re-queued to user analysis for one saved search.
+// Completed and Failed count the analyzed jobs; the run is finished once they add up to Total.
+type RescoreRun struct {
+	ID               uuid.UUID
+	UserID           uuid.UUID
+	SavedSearchID    uuid.UUID
+	PromptRevisionID *uuid.UUID
+	Days             int
+	Total            int
+	Completed        int
+	Failed           int
+	CreatedAt        time.Time
+	FinishedAt       *time.Time
+}
+
+// ResumeProfile is what was derived from the user's uploaded resume. It seeds their prompt
+// and its skills are part of every match.
+type ResumeProfile struct {
+	UserID   uuid.UUID
+	FileName string
+	// Format is the detected file type: "pdf", "docx" or "text"
+	Format string
+	// Text is the extracted plain text the profile was derived from
+	Text      string
+	Skills    []string
+	Seniority string
+	Locations []string
+	// Expected yearly salary; nil when the resume does not say
+	SalaryMin       *int
+	SalaryMax       *int
+	SalaryCurrency  string
+	SuggestedPrompt string
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
 
-type UserJobMatch struct {
-	ID        uuid.UUID
-	UserID    uuid.UUID
-	JobID     uuid.UUID
-	Score     int
-	Analysis  map[string]interface{}
-	Notified  bool
-	CreatedAt time.Time
+// Seniority levels of a resume profile; empty when unknown
+const (
+	SeniorityJunior    = "junior"
+	SeniorityMid       = "mid"
+	SenioritySenior    = "senior"
+	SeniorityLead      = "lead"
+	SeniorityPrincipal = "principal"
+)
+
+// ValidSeniority reports whether level is one of the seniority levels
+func ValidSeniority(level string) bool {
+	switch level {
+	case SeniorityJunior, SeniorityMid, SenioritySenior, SeniorityLead, SeniorityPrincipal:
+		return true
+	}
+	return false
+}
+
+// SearchFilters narrow the jobs a saved search is matched against, before any AI call.
+// Empty fields match every job; text comparisons ignore case.
+type SearchFilters struct {
+	// Keywords: the title or description must contain at least one
+	Keywords         []string `json:"keywords,omitempty"`
+	ExcludeKeywords  []string `json:"exclude_keywords,omitempty"`
+	Companies        []string `json:"companies,omitempty"`
+	ExcludeCompanies []string `json:"exclude_companies,omitempty"`
+	// Locations: the job location must contain at least one
+	Locations  []string `json:"locations,omitempty"`
+	RemoteOnly bool     `json:"remote_only,omitempty"`
+
+	// Near: the job must be within RadiusKm of this place. NearPoint is its geocoded position, set
+	// when the search is saved. Remote jobs pass only with IncludeRemote.
+	Near          string     `json:"near,omitempty"`
+	RadiusKm      float64    `json:"radius_km,omitempty"`
+	IncludeRemote bool       `json:"include_remote,omitempty"`
+	NearPoint     *geo.Point `json:"near_point,omitempty"`
+}
+
+// IsEmpty reports whether the filters match every job
+func (f SearchFilters) IsEmpty() bool {
+	return len(f.Keywords) == 0 && len(f.ExcludeKeywords) == 0 && len(f.Companies) == 0 &&
+		len(f.ExcludeCompanies) == 0 && len(f.Locations) == 0 && !f.RemoteOnly && f.NearPoint == nil
+}
+
+// Match reports whether a job with these fields passes the filters. coords is the job's geocoded
+// location, nil when it is unknown.
+func (f SearchFilters) Match(title, company, location, description string, remote bool, coords *geo.Point) bool {
+	return f.FailedFilter(title, company, location, description, remote, coords) == ""
+}
+
+// FailedFilter returns the JSON name of the first filter the job fails, such as "keywords" or
+// "near", or "" if it passes them all
+func (f SearchFilters) FailedFilter(title, company, location, description string, remote bool, coords *geo.Point) string {
+	if f.RemoteOnly && !remote {
+		return "remote_only"
+	}
+	text := strings.ToLower(title + "\n" + description)
+	if len(f.Keywords) > 0 && !containsAny(text, f.Keywords) {
+		return "keywords"
+	}
+	if containsAny(text, f.ExcludeKeywords) {
+		return "exclude_keywords"
+	}
+	if len(f.Companies) > 0 && !equalsAny(company, f.Companies) {
+		return "companies"
+	}
+	if equalsAny(company, f.ExcludeCompanies) {
+		return "exclude_companies"
+	}
+	if len(f.Locations) > 0 && !containsAny(strings.ToLower(location), f.Locations) {
+		return "locations"
+	}
+	if f.NearPoint != nil {
+		if remote {
+			if !f.IncludeRemote {
+				return "near"
+			}
+		} else if coords == nil || geo.DistanceKm(*f.NearPoint, *coords) > f.RadiusKm {
+			return "near"
+		}
+	}
+	return ""
+}
+
+func containsAny(lowerText string, terms []string) bool {
+	for _, term := range terms {
+		if term != "" && strings.Contains(lowerText, strings.ToLower(term)) {
+			return true
+		}
+	}
+	return false
+}
+
+func equalsAny(value string, options []string) bool {
+	for _, option := range options {
+		if strings.EqualFold(strings.TrimSpace(value), strings.TrimSpace(option)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification channels a saved search can deliver to. They match the notification template channels.
+const (
+	ChannelDiscord = "discord"
+	ChannelSlack   = "slack"
+	ChannelEmail   = "email"
+)
+
+// Channels lists every notification channel
+var Channels = []string{ChannelDiscord, ChannelSlack, ChannelEmail}
+
+// ValidChannel reports whether channel is a known notification channel
+func ValidChannel(channel string) bool {
+	for _, c := range Channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
 }
 
 type Preference struct {
@@ -35,3 +303,158 @@ type Preference struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
+
+// Session is one refresh token. Tokens in the same FamilyID descend from a single login;
+// only the newest (not rotated, not revoked) token in a family can be exchanged.
+type Session struct {
+	ID        uuid.UUID
+	FamilyID  uuid.UUID
+	UserID    uuid.UUID
+	TokenHash string
+	UserAgent string
+	IPAddress string
+	StartedAt time.Time
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	RotatedAt *time.Time
+	RevokedAt *time.Time
+}
+
+// Purposes of UserToken
+const (
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposePasswordReset     = "password_reset"
+)
+
+// UserToken is a single-use token sent by email. Email is the address it was sent to,
+// so a verification link stops working once the user changes their address.
+type UserToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Purpose   string
+	TokenHash string
+	Email     string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// UserIdentity links an external login (provider + subject) to a user
+type UserIdentity struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Provider    string
+	Subject     string
+	Email       *string
+	CreatedAt   time.Time
+	LastLoginAt *time.Time
+}
+
+// OIDCLoginState is an in-flight external login. UserID is set when linking to an existing account.
+type OIDCLoginState struct {
+	StateHash    string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	UserID       *uuid.UUID
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+// Kinds of rows in auth_attempts
+const (
+	AttemptKindLogin    = "login"    // a failed login
+	AttemptKindRegister = "register" // a registration attempt
+)
+
+// AttemptStats summarizes the attempts for one key within a window
+type AttemptStats struct {
+	Count int
+	First time.Time
+	Last  time.Time
+}
+
+// APIToken is a personal access token. Only TokenHash is stored; TokenPrefix is shown in listings.
+type APIToken struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Name        string
+	TokenHash   string
+	TokenPrefix string
+	Scopes      []string
+	ExpiresAt   time.Time
+	LastUsedAt  *time.Time
+	CreatedAt   time.Time
+	RevokedAt   *time.Time
+}
+
+// JobExplanation walks the pipeline decisions that led, or did not lead, to a notification about
+// one job for one user. It is built from stored pipeline data; nothing is re-scored.
+type JobExplanation struct {
+	JobID    uuid.UUID
+	Title    string
+	Company  string
+	Notified bool
+	// Steps are the job-wide stages, shared by every user
+	Steps    []ExplanationStep
+	Searches []SearchExplanation
+}
+
+// SearchExplanation is the decision chain for one of the user's saved searches. Verdict is
+// "notified", "deferred", "pending" or "not_notified"; Reason is the detail of the step that
+// decided it.
+type SearchExplanation struct {
+	SavedSearchID uuid.UUID
+	Name          string
+	Verdict       string
+	Reason        string
+	Steps         []ExplanationStep
+}
+
+// ExplanationStep is one decision in the chain
+type ExplanationStep struct {
+	Step   string
+	Status string
+	Detail string
+	At     *time.Time
+}
+
+// Explanation step statuses
+const (
+	StepPassed  = "passed"
+	StepFailed  = "failed"
+	StepPending = "pending"
+	StepSkipped = "skipped" // not reached because an earlier step failed
+)
+
+// Search explanation verdicts
+const (
+	VerdictNotified    = "notified"
+	VerdictDeferred    = "deferred"
+	VerdictPending     = "pending"
+	VerdictNotNotified = "not_notified"
+)
+
+// JobEvaluation is the result of scoring one job against one of the user's saved searches on
+// demand. Created is true when the job was new and has been ingested by the evaluation.
+type JobEvaluation struct {
+	JobID         uuid.UUID
+	JobURL        string
+	Title         string
+	Company       string
+	Location      string
+	Created       bool
+	SavedSearchID uuid.UUID
+	SearchName    string
+	MatchID       uuid.UUID
+	Score         int
+	Threshold     int
+	Explanation   string
+	Pros          []string
+	Cons          []string
+	// CompanyResearch is "researched", "skipped_fresh" (research under 6 months old was reused)
+	// or "failed"; the match was then scored without company info
+	CompanyResearch string
+	CompanyInfo     map[string]interface{}
+	EvaluatedAt     time.Time
+}
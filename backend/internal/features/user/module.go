@@ -3,28 +3,86 @@ package user
 import (
 	"github.com/go-chi/chi/v5"
 	"github.com/jobping/backend/internal/features/user/handler"
+	"github.com/jobping/backend/internal/features/user/model"
 )
 
 func RegisterRoutes(r chi.Router, userHandler *handler.UserHandler, auth *handler.AuthMiddleware) {
 	// Public routes
 	r.Post("/register", userHandler.Register)
 	r.Post("/login", userHandler.Login)
+	r.Post("/auth/refresh", userHandler.Refresh)
+	r.Post("/auth/logout", userHandler.Logout)
+	r.Post("/auth/verify-email", userHandler.VerifyEmail)
+	r.Post("/auth/forgot-password", userHandler.ForgotPassword)
+	r.Post("/auth/reset-password", userHandler.ResetPassword)
+	r.Get("/auth/oidc/providers", userHandler.GetOIDCProviders)
+	r.Post("/auth/oidc/{provider}/start", userHandler.StartOIDCLogin)
+	r.Post("/auth/oidc/callback", userHandler.OIDCCallback)
 
-	// Protected routes
+	// Protected routes. API tokens reach only the routes that declare a scope.
 	r.Group(func(r chi.Router) {
 		r.Use(auth.Authenticate)
 
+		// Account security: signed-in sessions only
+		r.Group(func(r chi.Router) {
+			r.Use(auth.RequireSession)
+
+			// Sessions
+			r.Post("/auth/logout-all", userHandler.LogoutAll)
+			r.Get("/auth/sessions", userHandler.GetSessions)
+			r.Delete("/auth/sessions/{id}", userHandler.RevokeSession)
+
+			// Personal API tokens
+			r.Get("/users/me/tokens", userHandler.GetAPITokens)
+			r.Post("/users/me/tokens", userHandler.CreateAPIToken)
+			r.Delete("/users/me/tokens/{id}", userHandler.RevokeAPIToken)
+
+			// Linked external identities
+			r.Get("/users/me/identities", userHandler.GetIdentities)
+			r.Post("/users/me/identities/{provider}/start", userHandler.StartLinkIdentity)
+			r.Post("/users/me/identities/callback", userHandler.LinkIdentityCallback)
+			r.Delete("/users/me/identities/{id}", userHandler.UnlinkIdentity)
+
+			// Email
+			r.Put("/me/email", userHandler.UpdateEmail)
+			r.Post("/me/email/verification", userHandler.ResendVerification)
+
+			// Data export and account deletion
+			r.Get("/users/me/export", userHandler.ExportData)
+			r.Delete("/users/me", userHandler.DeleteAccount)
+			r.Post("/users/me/deletion/cancel", userHandler.CancelDeletion)
+		})
+
 		// Profile and settings
-		r.Get("/me", userHandler.GetProfile)
-		r.Put("/me/prompt", userHandler.UpdatePrompt)
-		r.Put("/me/discord", userHandler.UpdateDiscord)
-		r.Put("/me/threshold", userHandler.UpdateThreshold)
-		r.Get("/me/matches", userHandler.GetMatches)
+		r.With(auth.RequireScope(model.ScopeReadProfile)).Get("/me", userHandler.GetProfile)
+		r.With(auth.RequireScope(model.ScopeWriteFilters)).Put("/me/prompt", userHandler.UpdatePrompt)
+		r.With(auth.RequireScope(model.ScopeWriteProfile)).Put("/me/discord", userHandler.UpdateDiscord)
+		r.With(auth.RequireScope(model.ScopeWriteProfile)).Put("/me/slack", userHandler.UpdateSlack)
+		r.With(auth.RequireScope(model.ScopeWriteFilters)).Put("/me/threshold", userHandler.UpdateThreshold)
+		r.With(auth.RequireScope(model.ScopeWriteProfile)).Put("/me/notification-settings", userHandler.UpdateNotificationSettings)
+		r.With(auth.RequireScope(model.ScopeReadProfile)).Get("/users/me/resume", userHandler.GetResume)
+		r.With(auth.RequireScope(model.ScopeWriteProfile)).Post("/users/me/resume", userHandler.UploadResume)
+		r.With(auth.RequireScope(model.ScopeWriteProfile)).Delete("/users/me/resume", userHandler.DeleteResume)
+		r.With(auth.RequireScope(model.ScopeReadMatches)).Get("/me/matches", userHandler.GetMatches)
+		r.With(auth.RequireScope(model.ScopeReadMatches)).Get("/users/me/jobs/{id}/explain", userHandler.GetJobExplanation)
+		r.With(auth.RequireScope(model.ScopeReadMatches)).Post("/users/me/evaluate", userHandler.EvaluateJob)
+
+		// Saved searches
+		r.With(auth.RequireScope(model.ScopeReadFilters)).Get("/users/me/searches", userHandler.GetSavedSearches)
+		r.With(auth.RequireScope(model.ScopeWriteFilters)).Post("/users/me/searches", userHandler.CreateSavedSearch)
+		r.With(auth.RequireScope(model.ScopeReadFilters)).Get("/users/me/searches/{id}", userHandler.GetSavedSearch)
+		r.With(auth.RequireScope(model.ScopeWriteFilters)).Put("/users/me/searches/{id}", userHandler.UpdateSavedSearch)
+		r.With(auth.RequireScope(model.ScopeWriteFilters)).Delete("/users/me/searches/{id}", userHandler.DeleteSavedSearch)
+		r.With(auth.RequireScope(model.ScopeReadMatches)).Get("/users/me/searches/{id}/matches", userHandler.GetSavedSearchMatches)
+		r.With(auth.RequireScope(model.ScopeReadFilters)).Get("/users/me/searches/{id}/prompts", userHandler.GetPromptHistory)
+		r.With(auth.RequireScope(model.ScopeWriteFilters)).Post("/users/me/searches/{id}/rescore", userHandler.StartRescore)
+		r.With(auth.RequireScope(model.ScopeReadMatches)).Get("/users/me/rescores", userHandler.GetRescores)
+		r.With(auth.RequireScope(model.ScopeReadMatches)).Get("/users/me/rescores/{id}", userHandler.GetRescore)
 
 		// Preferences (legacy)
-		r.Get("/preferences", userHandler.GetPreferences)
-		r.Post("/preferences", userHandler.CreatePreference)
-		r.Put("/preferences/{id}", userHandler.UpdatePreference)
-		r.Delete("/preferences/{id}", userHandler.DeletePreference)
+		r.With(auth.RequireScope(model.ScopeReadFilters)).Get("/preferences", userHandler.GetPreferences)
+		r.With(auth.RequireScope(model.ScopeWriteFilters)).Post("/preferences", userHandler.CreatePreference)
+		r.With(auth.RequireScope(model.ScopeWriteFilters)).Put("/preferences/{id}", userHandler.UpdatePreference)
+		r.With(auth.RequireScope(model.ScopeWriteFilters)).Delete("/preferences/{id}", userHandler.DeletePreference)
 	})
 }
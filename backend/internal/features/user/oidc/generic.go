@@ -0,0 +1,194 @@
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// idTokenAlgorithms are the signing algorithms accepted on ID tokens. HMAC and "none" are never accepted.
+var idTokenAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "ES256", "ES384", "EdDSA"}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+// OIDCProvider is an OpenID Connect provider configured from its issuer's discovery document
+type OIDCProvider struct {
+	name         string
+	issuer       string
+	clientID     string
+	clientSecret string
+	scopes       []string
+
+	mu   sync.Mutex
+	meta *discovery
+	keys *remoteKeySet
+}
+
+func NewOIDCProvider(name, issuer, clientID, clientSecret string, scopes []string) *OIDCProvider {
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+	return &OIDCProvider{
+		name:         name,
+		issuer:       strings.TrimRight(issuer, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		scopes:       scopes,
+	}
+}
+
+func (p *OIDCProvider) Name() string {
+	return p.name
+}
+
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, req AuthRequest) (string, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	return authCodeURL(meta.AuthorizationEndpoint, p.clientID, p.scopes, req, url.Values{"nonce": {req.Nonce}})
+}
+
+func (p *OIDCProvider) Exchange(ctx context.Context, code, verifier, redirectURI, nonce string) (*Identity, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := exchangeCode(ctx, meta.TokenEndpoint, p.clientID, p.clientSecret, code, verifier, redirectURI)
+	if err != nil {
+		return nil, err
+	}
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("%w: token response has no id_token", ErrExchange)
+	}
+
+	claims, err := p.verifyIDToken(token.IDToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := claims.identity(p.name)
+	if identity.Email == "" && meta.UserinfoEndpoint != "" {
+		if err := p.fillFromUserinfo(ctx, meta.UserinfoEndpoint, token.AccessToken, identity); err != nil {
+			return nil, err
+		}
+	}
+	return identity, nil
+}
+
+// idTokenClaims are the ID token claims we use. email_verified is decoded loosely because
+// some providers send it as the string "true".
+type idTokenClaims struct {
+	Nonce             string      `json:"nonce"`
+	Email             string      `json:"email"`
+	EmailVerified     interface{} `json:"email_verified"`
+	Name              string      `json:"name"`
+	PreferredUsername string      `json:"preferred_username"`
+	jwt.RegisteredClaims
+}
+
+func (c *idTokenClaims) identity(provider string) *Identity {
+	verified, _ := c.EmailVerified.(bool)
+	if s, ok := c.EmailVerified.(string); ok {
+		verified = s == "true"
+	}
+	return &Identity{
+		Provider:          provider,
+		Subject:           c.Subject,
+		Email:             c.Email,
+		EmailVerified:     verified,
+		Name:              c.Name,
+		PreferredUsername: c.PreferredUsername,
+	}
+}
+
+func (p *OIDCProvider) verifyIDToken(raw, nonce string) (*idTokenClaims, error) {
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, p.keys.keyfunc,
+		jwt.WithValidMethods(idTokenAlgorithms),
+		jwt.WithIssuer(p.meta.Issuer),
+		jwt.WithAudience(p.clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid id_token: %v", ErrExchange, err)
+	}
+	if claims.Nonce != nonce {
+		return nil, fmt.Errorf("%w: id_token nonce mismatch", ErrExchange)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: id_token has no subject", ErrExchange)
+	}
+	return claims, nil
+}
+
+func (p *OIDCProvider) fillFromUserinfo(ctx context.Context, endpoint, accessToken string, identity *Identity) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	var info idTokenClaims
+	if err := doJSON(req, &info); err != nil {
+		return fmt.Errorf("%w: userinfo: %v", ErrExchange, err)
+	}
+	// Userinfo must describe the same subject as the verified ID token
+	if info.Subject != identity.Subject {
+		return fmt.Errorf("%w: userinfo subject mismatch", ErrExchange)
+	}
+
+	fromInfo := info.identity(identity.Provider)
+	identity.Email, identity.EmailVerified = fromInfo.Email, fromInfo.EmailVerified
+	if identity.Name == "" {
+		identity.Name = fromInfo.Name
+	}
+	if identity.PreferredUsername == "" {
+		identity.PreferredUsername = fromInfo.PreferredUsername
+	}
+	return nil
+}
+
+// discover fetches the discovery document once. Failures are not cached so a provider outage
+// at startup does not disable login until restart.
+func (p *OIDCProvider) discover(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.meta != nil {
+		return p.meta, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	var meta discovery
+	if err := doJSON(req, &meta); err != nil {
+		return nil, fmt.Errorf("%w: discovery for %s: %v", ErrExchange, p.name, err)
+	}
+	if strings.TrimRight(meta.Issuer, "/") != p.issuer {
+		return nil, fmt.Errorf("%w: discovery issuer %q does not match %q", ErrExchange, meta.Issuer, p.issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: incomplete discovery document for %s", ErrExchange, p.name)
+	}
+
+	p.meta = &meta
+	p.keys = newRemoteKeySet(meta.JWKSURI)
+	return p.meta, nil
+}
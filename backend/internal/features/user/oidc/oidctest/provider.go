@@ -0,0 +1,282 @@
+// Package oidctest is a minimal in-process OpenID Connect provider for tests and local development.
+// It implements discovery, an auto-approving authorization endpoint, the token endpoint with PKCE
+// (S256) checks, JWKS and userinfo. ID tokens are signed with a fresh Ed25519 key.
+package oidctest
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const keyID = "oidctest"
+
+// User is the account the provider logs everyone in as
+type User struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+type authorization struct {
+	clientID    string
+	redirectURI string
+	challenge   string
+	nonce       string
+	user        User
+	expiresAt   time.Time
+}
+
+// Provider serves the OIDC endpoints relative to Issuer. Mount it so requests reach it with the
+// issuer's path prefix stripped.
+type Provider struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+
+	key ed25519.PrivateKey
+
+	mu     sync.Mutex
+	user   User
+	codes  map[string]authorization
+	tokens map[string]User
+}
+
+// New creates a provider for the given issuer URL and client credentials
+func New(issuer, clientID, clientSecret string) *Provider {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return &Provider{
+		Issuer:       issuer,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		key:          key,
+		user: User{
+			Subject:           "mock-user-1",
+			Email:             "mock.user@example.com",
+			EmailVerified:     true,
+			Name:              "Mock User",
+			PreferredUsername: "mockuser",
+		},
+		codes:  make(map[string]authorization),
+		tokens: make(map[string]User),
+	}
+}
+
+// NewServer starts the provider on an httptest server. Close the server when done.
+func NewServer(clientID, clientSecret string) (*Provider, *httptest.Server) {
+	p := New("", clientID, clientSecret)
+	srv := httptest.NewServer(p)
+	p.Issuer = srv.URL
+	return p, srv
+}
+
+// SetUser changes the account subsequent logins resolve to
+func (p *Provider) SetUser(u User) {
+	p.mu.Lock()
+	p.user = u
+	p.mu.Unlock()
+}
+
+func (p *Provider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/.well-known/openid-configuration":
+		p.discovery(w)
+	case "/authorize":
+		p.authorize(w, r)
+	case "/token":
+		p.token(w, r)
+	case "/jwks":
+		p.jwks(w)
+	case "/userinfo":
+		p.userinfo(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (p *Provider) discovery(w http.ResponseWriter) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                p.Issuer,
+		"authorization_endpoint":                p.Issuer + "/authorize",
+		"token_endpoint":                        p.Issuer + "/token",
+		"jwks_uri":                              p.Issuer + "/jwks",
+		"userinfo_endpoint":                     p.Issuer + "/userinfo",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"EdDSA"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+// authorize approves immediately and redirects back with a code
+func (p *Provider) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirectURI := q.Get("redirect_uri")
+	if q.Get("client_id") != p.ClientID || redirectURI == "" {
+		http.Error(w, "invalid client_id or redirect_uri", http.StatusBadRequest)
+		return
+	}
+	if q.Get("response_type") != "code" || q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		http.Error(w, "authorization code flow with S256 PKCE required", http.StatusBadRequest)
+		return
+	}
+
+	code := randomString()
+	p.mu.Lock()
+	p.codes[code] = authorization{
+		clientID:    p.ClientID,
+		redirectURI: redirectURI,
+		challenge:   q.Get("code_challenge"),
+		nonce:       q.Get("nonce"),
+		user:        p.user,
+		expiresAt:   time.Now().Add(time.Minute),
+	}
+	p.mu.Unlock()
+
+	target, err := url.Parse(redirectURI)
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	params := target.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	target.RawQuery = params.Encode()
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+func (p *Provider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type")
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != p.ClientID || subtle.ConstantTimeCompare([]byte(clientSecret), []byte(p.ClientSecret)) != 1 {
+		tokenError(w, "invalid_client")
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	p.mu.Lock()
+	auth, found := p.codes[code]
+	delete(p.codes, code) // codes are single-use
+	p.mu.Unlock()
+
+	if !found || time.Now().After(auth.expiresAt) || auth.redirectURI != r.PostForm.Get("redirect_uri") {
+		tokenError(w, "invalid_grant")
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != auth.challenge {
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{
+		"iss":                p.Issuer,
+		"sub":                auth.user.Subject,
+		"aud":                p.ClientID,
+		"iat":                now.Unix(),
+		"exp":                now.Add(5 * time.Minute).Unix(),
+		"nonce":              auth.nonce,
+		"email":              auth.user.Email,
+		"email_verified":     auth.user.EmailVerified,
+		"name":               auth.user.Name,
+		"preferred_username": auth.user.PreferredUsername,
+	})
+	idToken.Header["kid"] = keyID
+	signed, err := idToken.SignedString(p.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	accessToken := randomString()
+	p.mu.Lock()
+	p.tokens[accessToken] = auth.user
+	p.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     signed,
+	})
+}
+
+func (p *Provider) jwks(w http.ResponseWriter) {
+	pub := p.key.Public().(ed25519.PublicKey)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "OKP",
+			"crv": "Ed25519",
+			"kid": keyID,
+			"use": "sig",
+			"alg": "EdDSA",
+			"x":   base64.RawURLEncoding.EncodeToString(pub),
+		}},
+	})
+}
+
+func (p *Provider) userinfo(w http.ResponseWriter, r *http.Request) {
+	const prefix = "Bearer "
+	header := r.Header.Get("Authorization")
+	if len(header) <= len(prefix) || header[:len(prefix)] != prefix {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	p.mu.Lock()
+	user, ok := p.tokens[header[len(prefix):]]
+	p.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"sub":                user.Subject,
+		"email":              user.Email,
+		"email_verified":     user.EmailVerified,
+		"name":               user.Name,
+		"preferred_username": user.PreferredUsername,
+	})
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func randomString() string {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,177 @@
+// Package oidc implements the client side of external login: authorization-code flow with PKCE
+// against OpenID Connect providers (discovered from their issuer) and GitHub's OAuth2 API.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jobping/backend/internal/config"
+)
+
+// ErrExchange wraps any failure to turn an authorization code into a verified identity
+var ErrExchange = errors.New("external login failed")
+
+// Identity is the external account a login resolved to
+type Identity struct {
+	Provider          string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+// AuthRequest holds the per-login values sent to the provider's authorization endpoint
+type AuthRequest struct {
+	State         string
+	Nonce         string
+	CodeChallenge string
+	RedirectURI   string
+}
+
+type Provider interface {
+	Name() string
+	// AuthCodeURL returns the URL to send the browser to
+	AuthCodeURL(ctx context.Context, req AuthRequest) (string, error)
+	// Exchange redeems the code with the PKCE verifier and returns the verified identity.
+	// nonce must match the one sent in AuthCodeURL (ignored by providers without ID tokens).
+	Exchange(ctx context.Context, code, verifier, redirectURI, nonce string) (*Identity, error)
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// New builds a provider from config
+func New(cfg config.OIDCProvider) (Provider, error) {
+	switch cfg.Kind {
+	case "oidc":
+		if cfg.Issuer == "" {
+			return nil, fmt.Errorf("OIDC provider %q has no issuer", cfg.Name)
+		}
+		return NewOIDCProvider(cfg.Name, cfg.Issuer, cfg.ClientID, cfg.ClientSecret, cfg.Scopes), nil
+	case "github":
+		return NewGitHubProvider(cfg.ClientID, cfg.ClientSecret, cfg.Scopes), nil
+	default:
+		return nil, fmt.Errorf("unknown provider kind %q for %q", cfg.Kind, cfg.Name)
+	}
+}
+
+// NewVerifier returns a PKCE code verifier (RFC 7636, 43 characters)
+func NewVerifier() (string, error) {
+	return RandomString(32)
+}
+
+// Challenge returns the S256 code challenge for a verifier
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// RandomString returns n random bytes, base64url encoded
+func RandomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func authCodeURL(endpoint, clientID string, scopes []string, req AuthRequest, extra url.Values) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", clientID)
+	q.Set("redirect_uri", req.RedirectURI)
+	q.Set("scope", strings.Join(scopes, " "))
+	q.Set("state", req.State)
+	q.Set("code_challenge", req.CodeChallenge)
+	q.Set("code_challenge_method", "S256")
+	for k, v := range extra {
+		q[k] = v
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	TokenType        string `json:"token_type"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func exchangeCode(ctx context.Context, tokenURL, clientID, clientSecret, code, verifier, redirectURI string) (*tokenResponse, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token tokenResponse
+	if err := doJSON(req, &token); err != nil && token.Error == "" {
+		return nil, fmt.Errorf("%w: token request: %v", ErrExchange, err)
+	}
+	if token.Error != "" {
+		return nil, fmt.Errorf("%w: %s: %s", ErrExchange, token.Error, token.ErrorDescription)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("%w: token response has no access_token", ErrExchange)
+	}
+	return &token, nil
+}
+
+// doJSON performs the request and decodes the JSON body. The body is decoded even on
+// error statuses so OAuth error responses can be reported.
+func doJSON(req *http.Request, v interface{}) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	decodeErr := json.Unmarshal(body, v)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %d", req.URL.Host, resp.StatusCode)
+	}
+	return decodeErr
+}
+
+// NewProviders builds every configured provider
+func NewProviders(cfgs []config.OIDCProvider) ([]Provider, error) {
+	providers := make([]Provider, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		p, err := New(cfg)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, p)
+	}
+	return providers, nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jobping/backend/internal/features/user/model"
+)
+
+type APITokenRepository interface {
+	Create(ctx context.Context, token *model.APIToken) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*model.APIToken, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]model.APIToken, error)
+	CountActive(ctx context.Context, userID uuid.UUID, now time.Time) (int, error)
+	TouchLastUsed(ctx context.Context, id uuid.UUID, now time.Time) error
+	Revoke(ctx context.Context, userID, id uuid.UUID, now time.Time) (bool, error)
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID, now time.Time) (int64, error)
+}
+
+const apiTokenColumns = `id, user_id, name, token_hash, token_prefix, scopes, expires_at, last_used_at, created_at, revoked_at`
+
+func scanAPIToken(row pgx.Row) (*model.APIToken, error) {
+	var t model.APIToken
+	err := row.Scan(
+		&t.ID, &t.UserID, &t.Name, &t.TokenHash, &t.TokenPrefix, &t.Scopes,
+		&t.ExpiresAt, &t.LastUsedAt, &t.CreatedAt, &t.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+type postgresAPITokenRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAPITokenRepository(db *pgxpool.Pool) APITokenRepository {
+	return &postgresAPITokenRepository{db: db}
+}
+
+func (r *postgresAPITokenRepository) Create(ctx context.Context, token *model.APIToken) error {
+	query := `
+		INSERT INTO api_tokens (id, user_id, name, token_hash, token_prefix, scopes, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.db.Exec(ctx, query,
+		token.ID, token.UserID, token.Name, token.TokenHash, token.TokenPrefix, token.Scopes, token.ExpiresAt, token.CreatedAt,
+	)
+	return err
+}
+
+func (r *postgresAPITokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*model.APIToken, error) {
+	query := `SELECT ` + apiTokenColumns + ` FROM api_tokens WHERE token_hash = $1`
+	token, err := scanAPIToken(r.db.QueryRow(ctx, query, tokenHash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// GetByUserID returns the user's tokens that have not been revoked, newest first. Expired
+// tokens are included so users can see what stopped working.
+func (r *postgresAPITokenRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]model.APIToken, error) {
+	query := `SELECT ` + apiTokenColumns + ` FROM api_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []model.APIToken
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+	return tokens, rows.Err()
+}
+
+func (r *postgresAPITokenRepository) CountActive(ctx context.Context, userID uuid.UUID, now time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM api_tokens WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2`
+	var count int
+	err := r.db.QueryRow(ctx, query, userID, now).Scan(&count)
+	return count, err
+}
+
+// TouchLastUsed records a use, at most once a minute per token to keep writes off the hot path
+func (r *postgresAPITokenRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, now time.Time) error {
+	query := `
+		UPDATE api_tokens SET last_used_at = $2
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $2 - INTERVAL '1 minute')
+	`
+	_, err := r.db.Exec(ctx, query, id, now)
+	return err
+}
+
+func (r *postgresAPITokenRepository) Revoke(ctx context.Context, userID, id uuid.UUID, now time.Time) (bool, error) {
+	query := `UPDATE api_tokens SET revoked_at = $3 WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+	tag, err := r.db.Exec(ctx, query, id, userID, now)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *postgresAPITokenRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID, now time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `UPDATE api_tokens SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL`, userID, now)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
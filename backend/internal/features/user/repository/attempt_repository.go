@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jobping/backend/internal/features/user/model"
+)
+
+// AttemptRepository stores recent login failures and registrations for rate limiting
+type AttemptRepository interface {
+	Record(ctx context.Context, kind, username, ipAddress string, at time.Time) error
+	StatsByUsername(ctx context.Context, kind, username string, since time.Time) (model.AttemptStats, error)
+	StatsByIP(ctx context.Context, kind, ipAddress string, since time.Time) (model.AttemptStats, error)
+	ClearUsername(ctx context.Context, kind, username string) error
+	DeleteBefore(ctx context.Context, before time.Time) error
+}
+
+type postgresAttemptRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAttemptRepository(db *pgxpool.Pool) AttemptRepository {
+	return &postgresAttemptRepository{db: db}
+}
+
+func (r *postgresAttemptRepository) Record(ctx context.Context, kind, username, ipAddress string, at time.Time) error {
+	query := `INSERT INTO auth_attempts (kind, username, ip_address, created_at) VALUES ($1, $2, $3, $4)`
+	_, err := r.db.Exec(ctx, query, kind, username, ipAddress, at)
+	return err
+}
+
+func (r *postgresAttemptRepository) StatsByUsername(ctx context.Context, kind, username string, since time.Time) (model.AttemptStats, error) {
+	query := `
+		SELECT COUNT(*), COALESCE(MIN(created_at), 'epoch'), COALESCE(MAX(created_at), 'epoch')
+		FROM auth_attempts WHERE kind = $1 AND username = $2 AND created_at > $3
+	`
+	var stats model.AttemptStats
+	err := r.db.QueryRow(ctx, query, kind, username, since).Scan(&stats.Count, &stats.First, &stats.Last)
+	return stats, err
+}
+
+func (r *postgresAttemptRepository) StatsByIP(ctx context.Context, kind, ipAddress string, since time.Time) (model.AttemptStats, error) {
+	query := `
+		SELECT COUNT(*), COALESCE(MIN(created_at), 'epoch'), COALESCE(MAX(created_at), 'epoch')
+		FROM auth_attempts WHERE kind = $1 AND ip_address = $2 AND created_at > $3
+	`
+	var stats model.AttemptStats
+	err := r.db.QueryRow(ctx, query, kind, ipAddress, since).Scan(&stats.Count, &stats.First, &stats.Last)
+	return stats, err
+}
+
+func (r *postgresAttemptRepository) ClearUsername(ctx context.Context, kind, username string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM auth_attempts WHERE kind = $1 AND username = $2`, kind, username)
+	return err
+}
+
+func (r *postgresAttemptRepository) DeleteBefore(ctx context.Context, before time.Time) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM auth_attempts WHERE created_at < $1`, before)
+	return err
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// UserDataRepository reads and erases everything stored about a user, across features
+type UserDataRepository interface {
+	// Export returns each section of the user's data as a JSON document, keyed by section name
+	Export(ctx context.Context, userID uuid.UUID) (map[string]json.RawMessage, error)
+	// Purge deletes the user and their data, and strips identifying details from audit entries
+	Purge(ctx context.Context, userID uuid.UUID, username string) error
+}
+
+// exportSection is one part of a data export. Queries select explicit columns so secrets
+// (password and token hashes, PKCE verifiers) never leave the database.
+type exportSection struct {
+	name  string
+	query string
+	// single sections hold one object rather than a list
+	single bool
+}
+
+// exportSections lists every table holding a user's rows. A feature that adds such a table
+// must add its section here too, or the export silently leaves that data out.
+var exportSections = []exportSection{
+	{name: "profile", single: true, query: `
+		SELECT id, username, email, email_verified_at, role, ai_prompt, discord_webhook, slack_webhook, notify_threshold,
+			timezone, quiet_hours_start, quiet_hours_end, max_notifications_per_hour,
+			disabled_at, deletion_scheduled_at, created_at, updated_at
+		FROM users WHERE id = $1`},
+	{name: "preferences", query: `
+		SELECT key, value, created_at, updated_at
+		FROM preferences WHERE user_id = $1 ORDER BY created_at`},
+	{name: "resume", single: true, query: `
+		SELECT file_name, format, resume_text, skills, seniority, locations, salary_min, salary_max, salary_currency,
+			suggested_prompt, created_at, updated_at
+		FROM resume_profiles WHERE user_id = $1`},
+	{name: "saved_searches", query: `
+		SELECT id, name, ai_prompt, filters, notify_threshold, channels, active, is_default, created_at, updated_at
+		FROM saved_searches WHERE user_id = $1 ORDER BY created_at`},
+	{name: "prompt_revisions", query: `
+		SELECT id, saved_search_id, ai_prompt, created_at
+		FROM prompt_revisions WHERE user_id = $1 ORDER BY created_at`},
+	{name: "rescore_runs", query: `
+		SELECT id, saved_search_id, prompt_revision_id, days, total, completed, failed, created_at, finished_at
+		FROM rescore_runs WHERE user_id = $1 ORDER BY created_at`},
+	{name: "matches", query: `
+		SELECT m.id, m.saved_search_id, m.job_id, j.title AS job_title, j.company, j.job_url, m.score, m.analysis,
+			m.notified, m.deferred_at, m.deferred_reason, m.prompt_revision_id, m.created_at
+		FROM user_job_matches m JOIN jobs j ON j.id = m.job_id
+		WHERE m.user_id = $1 ORDER BY m.created_at`},
+	{name: "job_evaluations", query: `
+		SELECT id, created_at
+		FROM job_evaluations WHERE user_id = $1 ORDER BY created_at`},
+	{name: "pipeline_events", query: `
+		SELECT id, job_id, saved_search_id, stage, outcome, details, error, created_at
+		FROM job_pipeline_events WHERE user_id = $1 ORDER BY created_at`},
+	{name: "notifications", query: `
+		SELECT id, job_id, match_id, job_title, company, job_url, matching_score, ai_analysis,
+			delivery, channels, read_at, created_at
+		FROM notifications WHERE user_id = $1 ORDER BY created_at`},
+	{name: "notification_deliveries", query: `
+		SELECT id, created_at
+		FROM notification_deliveries WHERE user_id = $1 ORDER BY created_at`},
+	{name: "notification_templates", query: `
+		SELECT channel, subject, body, text_body, created_at, updated_at
+		FROM notification_templates WHERE user_id = $1 ORDER BY channel`},
+	{name: "sessions", query: `
+		SELECT family_id, user_agent, ip_address, started_at, created_at, expires_at, rotated_at, revoked_at
+		FROM sessions WHERE user_id = $1 ORDER BY created_at`},
+	{name: "linked_identities", query: `
+		SELECT provider, email, created_at, last_login_at
+		FROM user_identities WHERE user_id = $1 ORDER BY created_at`},
+	{name: "api_tokens", query: `
+		SELECT name, token_prefix, scopes, expires_at, last_used_at, created_at, revoked_at
+		FROM api_tokens WHERE user_id = $1 ORDER BY created_at`},
+	{name: "security_log", query: `
+		SELECT action, ip_address, user_agent, metadata, created_at
+		FROM audit_log WHERE actor_id = $1 OR (target_type = 'user' AND target_id = $1::text)
+		ORDER BY created_at`},
+}
+
+type postgresUserDataRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewUserDataRepository(db *pgxpool.Pool) UserDataRepository {
+	return &postgresUserDataRepository{db: db}
+}
+
+func (r *postgresUserDataRepository) Export(ctx context.Context, userID uuid.UUID) (map[string]json.RawMessage, error) {
+	// One snapshot, so sections agree with each other
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	if _, err := tx.Exec(ctx, `SET TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY`); err != nil {
+		return nil, err
+	}
+
+	export := make(map[string]json.RawMessage, len(exportSections))
+	for _, section := range exportSections {
+		query := `SELECT COALESCE(json_agg(t), '[]'::json) FROM (` + section.query + `) t`
+		if section.single {
+			// A missing row (no resume, say) exports as null
+			query = `SELECT (SELECT row_to_json(t) FROM (` + section.query + `) t)`
+		}
+
+		var data []byte
+		if err := tx.QueryRow(ctx, query, userID).Scan(&data); err != nil {
+			return nil, fmt.Errorf("export %s: %w", section.name, err)
+		}
+		if data == nil {
+			data = []byte("null")
+		}
+		export[section.name] = data
+	}
+	return export, tx.Commit(ctx)
+}
+
+func (r *postgresUserDataRepository) Purge(ctx context.Context, userID uuid.UUID, username string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Audit entries stay for security review but no longer identify the person. Entries by
+	// another actor (an admin acting on this user) keep that actor's details.
+	scrubAudit := `
+		UPDATE audit_log SET
+			metadata = metadata - 'username',
+			ip_address = CASE WHEN actor_id IS NULL OR actor_id = $1 THEN NULL ELSE ip_address END,
+			user_agent = CASE WHEN actor_id IS NULL OR actor_id = $1 THEN NULL ELSE user_agent END
+		WHERE actor_id = $1 OR (target_type = 'user' AND target_id = $1::text)
+	`
+	if _, err := tx.Exec(ctx, scrubAudit, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM auth_attempts WHERE username = LOWER($1)`, username); err != nil {
+		return err
+	}
+	// Everything else referencing the user is removed by ON DELETE CASCADE
+	if _, err := tx.Exec(ctx, `DELETE FROM users WHERE id = $1`, userID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
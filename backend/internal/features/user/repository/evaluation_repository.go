@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jobping/backend/internal/features/user/model"
+)
+
+// EvaluationRepository counts on-demand job evaluations for the per-user rate limit
+type EvaluationRepository interface {
+	// RecordWithinLimit records an evaluation at the given time unless the user already has limit
+	// evaluations since the window start. It returns the window's stats before recording, and
+	// whether it recorded. A limit of 0 records unconditionally.
+	RecordWithinLimit(ctx context.Context, userID uuid.UUID, at, since time.Time, limit int) (model.AttemptStats, bool, error)
+	DeleteBefore(ctx context.Context, before time.Time) error
+}
+
+type postgresEvaluationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewEvaluationRepository(db *pgxpool.Pool) EvaluationRepository {
+	return &postgresEvaluationRepository{db: db}
+}
+
+// RecordWithinLimit counts and inserts under a per-user advisory lock, so concurrent requests of
+// one user cannot both pass the check
+func (r *postgresEvaluationRepository) RecordWithinLimit(ctx context.Context, userID uuid.UUID, at, since time.Time, limit int) (model.AttemptStats, bool, error) {
+	var stats model.AttemptStats
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return stats, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	if limit > 0 {
+		// Held until commit; the lock key is shared by every request for this user
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtextextended('job_evaluations:' || $1::text, 0))`, userID); err != nil {
+			return stats, false, err
+		}
+		query := `
+			SELECT COUNT(*), COALESCE(MIN(created_at), 'epoch'), COALESCE(MAX(created_at), 'epoch')
+			FROM job_evaluations WHERE user_id = $1 AND created_at > $2
+		`
+		if err := tx.QueryRow(ctx, query, userID, since).Scan(&stats.Count, &stats.First, &stats.Last); err != nil {
+			return stats, false, err
+		}
+		if stats.Count >= limit {
+			return stats, false, nil
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `INSERT INTO job_evaluations (user_id, created_at) VALUES ($1, $2)`, userID, at); err != nil {
+		return stats, false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return stats, false, err
+	}
+	return stats, true, nil
+}
+
+func (r *postgresEvaluationRepository) DeleteBefore(ctx context.Context, before time.Time) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM job_evaluations WHERE created_at < $1`, before)
+	return err
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jobping/backend/internal/features/user/model"
+)
+
+type IdentityRepository interface {
+	Create(ctx context.Context, identity *model.UserIdentity) error
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*model.UserIdentity, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]model.UserIdentity, error)
+	TouchLogin(ctx context.Context, id uuid.UUID, email *string) error
+	Delete(ctx context.Context, userID, id uuid.UUID) (bool, error)
+
+	CreateState(ctx context.Context, state *model.OIDCLoginState) error
+	ConsumeState(ctx context.Context, stateHash string, now time.Time) (*model.OIDCLoginState, error)
+}
+
+const identityColumns = `id, user_id, provider, subject, email, created_at, last_login_at`
+
+func scanIdentity(row pgx.Row) (*model.UserIdentity, error) {
+	var i model.UserIdentity
+	if err := row.Scan(&i.ID, &i.UserID, &i.Provider, &i.Subject, &i.Email, &i.CreatedAt, &i.LastLoginAt); err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+type postgresIdentityRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewIdentityRepository(db *pgxpool.Pool) IdentityRepository {
+	return &postgresIdentityRepository{db: db}
+}
+
+func (r *postgresIdentityRepository) Create(ctx context.Context, identity *model.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (id, user_id, provider, subject, email, created_at, last_login_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := r.db.Exec(ctx, query,
+		identity.ID, identity.UserID, identity.Provider, identity.Subject, identity.Email, identity.CreatedAt, identity.LastLoginAt,
+	)
+	return err
+}
+
+func (r *postgresIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*model.UserIdentity, error) {
+	query := `SELECT ` + identityColumns + ` FROM user_identities WHERE provider = $1 AND subject = $2`
+	identity, err := scanIdentity(r.db.QueryRow(ctx, query, provider, subject))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+func (r *postgresIdentityRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]model.UserIdentity, error) {
+	query := `SELECT ` + identityColumns + ` FROM user_identities WHERE user_id = $1 ORDER BY created_at`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var identities []model.UserIdentity
+	for rows.Next() {
+		identity, err := scanIdentity(rows)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, *identity)
+	}
+	return identities, rows.Err()
+}
+
+// TouchLogin records a login and refreshes the email the provider reported
+func (r *postgresIdentityRepository) TouchLogin(ctx context.Context, id uuid.UUID, email *string) error {
+	query := `UPDATE user_identities SET last_login_at = NOW(), email = COALESCE($2, email) WHERE id = $1`
+	_, err := r.db.Exec(ctx, query, id, email)
+	return err
+}
+
+func (r *postgresIdentityRepository) Delete(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM user_identities WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *postgresIdentityRepository) CreateState(ctx context.Context, state *model.OIDCLoginState) error {
+	query := `
+		INSERT INTO oidc_login_states (state_hash, provider, nonce, code_verifier, user_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := r.db.Exec(ctx, query,
+		state.StateHash, state.Provider, state.Nonce, state.CodeVerifier, state.UserID, state.ExpiresAt, state.CreatedAt,
+	)
+	return err
+}
+
+// ConsumeState deletes and returns a login state. Expired states are deleted too but not returned,
+// and abandoned ones older than a day are cleaned up along the way.
+func (r *postgresIdentityRepository) ConsumeState(ctx context.Context, stateHash string, now time.Time) (*model.OIDCLoginState, error) {
+	if _, err := r.db.Exec(ctx, `DELETE FROM oidc_login_states WHERE expires_at < $1`, now.Add(-24*time.Hour)); err != nil {
+		return nil, err
+	}
+
+	query := `
+		DELETE FROM oidc_login_states WHERE state_hash = $1
+		RETURNING state_hash, provider, nonce, code_verifier, user_id, expires_at, created_at
+	`
+	var s model.OIDCLoginState
+	err := r.db.QueryRow(ctx, query, stateHash).Scan(
+		&s.StateHash, &s.Provider, &s.Nonce, &s.CodeVerifier, &s.UserID, &s.ExpiresAt, &s.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !s.ExpiresAt.After(now) {
+		return nil, nil
+	}
+	return &s, nil
+}
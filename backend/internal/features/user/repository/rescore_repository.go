@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jobping/backend/internal/features/user/model"
+)
+
+type RescoreRepository interface {
+	Create(ctx context.Context, run *model.RescoreRun) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.RescoreRun, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]model.RescoreRun, error)
+	// GetUnfinished returns the search's newest run that is still in progress and was started after since
+	GetUnfinished(ctx context.Context, searchID uuid.UUID, since time.Time) (*model.RescoreRun, error)
+	// RecordProgress counts one analyzed job, finishing the run when every job is counted
+	RecordProgress(ctx context.Context, id uuid.UUID, failed bool) error
+}
+
+const rescoreColumns = `id, user_id, saved_search_id, prompt_revision_id, days, total, completed, failed, created_at, finished_at`
+
+func scanRescoreRun(row pgx.Row) (*model.RescoreRun, error) {
+	var run model.RescoreRun
+	err := row.Scan(
+		&run.ID, &run.UserID, &run.SavedSearchID, &run.PromptRevisionID, &run.Days,
+		&run.Total, &run.Completed, &run.Failed, &run.CreatedAt, &run.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+type postgresRescoreRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRescoreRepository(db *pgxpool.Pool) RescoreRepository {
+	return &postgresRescoreRepository{db: db}
+}
+
+func (r *postgresRescoreRepository) Create(ctx context.Context, run *model.RescoreRun) error {
+	query := `
+		INSERT INTO rescore_runs (id, user_id, saved_search_id, prompt_revision_id, days, total, completed, failed, created_at, finished_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err := r.db.Exec(ctx, query,
+		run.ID, run.UserID, run.SavedSearchID, run.PromptRevisionID, run.Days,
+		run.Total, run.Completed, run.Failed, run.CreatedAt, run.FinishedAt,
+	)
+	return err
+}
+
+func (r *postgresRescoreRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.RescoreRun, error) {
+	query := `SELECT ` + rescoreColumns + ` FROM rescore_runs WHERE id = $1`
+	return r.queryOne(ctx, query, id)
+}
+
+func (r *postgresRescoreRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]model.RescoreRun, error) {
+	query := `SELECT ` + rescoreColumns + ` FROM rescore_runs WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2`
+	rows, err := r.db.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []model.RescoreRun
+	for rows.Next() {
+		run, err := scanRescoreRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, *run)
+	}
+	return runs, rows.Err()
+}
+
+func (r *postgresRescoreRepository) GetUnfinished(ctx context.Context, searchID uuid.UUID, since time.Time) (*model.RescoreRun, error) {
+	query := `
+		SELECT ` + rescoreColumns + ` FROM rescore_runs
+		WHERE saved_search_id = $1 AND finished_at IS NULL AND created_at > $2
+		ORDER BY created_at DESC LIMIT 1
+	`
+	return r.queryOne(ctx, query, searchID, since)
+}
+
+func (r *postgresRescoreRepository) RecordProgress(ctx context.Context, id uuid.UUID, failed bool) error {
+	query := `
+		UPDATE rescore_runs SET
+			completed = completed + CASE WHEN $2 THEN 0 ELSE 1 END,
+			failed = failed + CASE WHEN $2 THEN 1 ELSE 0 END,
+			finished_at = CASE WHEN completed + failed + 1 >= total THEN NOW() ELSE finished_at END
+		WHERE id = $1 AND finished_at IS NULL
+	`
+	_, err := r.db.Exec(ctx, query, id, failed)
+	return err
+}
+
+func (r *postgresRescoreRepository) queryOne(ctx context.Context, query string, args ...interface{}) (*model.RescoreRun, error) {
+	run, err := scanRescoreRun(r.db.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jobping/backend/internal/features/user/model"
+)
+
+type ResumeRepository interface {
+	// Upsert stores the user's profile, replacing any earlier one
+	Upsert(ctx context.Context, profile *model.ResumeProfile) error
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*model.ResumeProfile, error)
+	Delete(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+type postgresResumeRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewResumeRepository(db *pgxpool.Pool) ResumeRepository {
+	return &postgresResumeRepository{db: db}
+}
+
+func (r *postgresResumeRepository) Upsert(ctx context.Context, profile *model.ResumeProfile) error {
+	query := `
+		INSERT INTO resume_profiles (user_id, file_name, format, resume_text, skills, seniority, locations,
+			salary_min, salary_max, salary_currency, suggested_prompt, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (user_id) DO UPDATE SET
+			file_name = $2, format = $3, resume_text = $4, skills = $5, seniority = $6, locations = $7,
+			salary_min = $8, salary_max = $9, salary_currency = $10, suggested_prompt = $11, updated_at = $13
+		RETURNING created_at
+	`
+	return r.db.QueryRow(ctx, query,
+		profile.UserID, profile.FileName, profile.Format, profile.Text, nonNil(profile.Skills), profile.Seniority,
+		nonNil(profile.Locations), profile.SalaryMin, profile.SalaryMax, profile.SalaryCurrency, profile.SuggestedPrompt,
+		profile.CreatedAt, profile.UpdatedAt,
+	).Scan(&profile.CreatedAt)
+}
+
+func (r *postgresResumeRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*model.ResumeProfile, error) {
+	query := `
+		SELECT user_id, file_name, format, resume_text, skills, seniority, locations,
+			salary_min, salary_max, salary_currency, suggested_prompt, created_at, updated_at
+		FROM resume_profiles WHERE user_id = $1
+	`
+	var p model.ResumeProfile
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&p.UserID, &p.FileName, &p.Format, &p.Text, &p.Skills, &p.Seniority, &p.Locations,
+		&p.SalaryMin, &p.SalaryMax, &p.SalaryCurrency, &p.SuggestedPrompt, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *postgresResumeRepository) Delete(ctx context.Context, userID uuid.UUID) (bool, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM resume_profiles WHERE user_id = $1`, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// nonNil stores a nil slice as an empty array, for NOT NULL array columns
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
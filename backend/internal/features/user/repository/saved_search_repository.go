@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jobping/backend/internal/features/user/model"
+)
+
+type SavedSearchRepository interface {
+	Create(ctx context.Context, search *model.SavedSearch) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.SavedSearch, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]model.SavedSearch, error)
+	GetDefault(ctx context.Context, userID uuid.UUID) (*model.SavedSearch, error)
+	GetActive(ctx context.Context) ([]model.SavedSearch, error)
+	Update(ctx context.Context, search *model.SavedSearch) (bool, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) (bool, error)
+	SyncDefault(ctx context.Context, userID uuid.UUID) error
+	GetRevisions(ctx context.Context, searchID uuid.UUID, limit int) ([]model.PromptRevision, error)
+}
+
+const savedSearchColumns = `s.id, s.user_id, s.name, s.ai_prompt, s.filters, s.notify_threshold, s.channels,
+	s.active, s.is_default, s.prompt_revision_id, s.created_at, s.updated_at`
+
+func scanSavedSearch(row pgx.Row) (*model.SavedSearch, error) {
+	var s model.SavedSearch
+	err := row.Scan(
+		&s.ID, &s.UserID, &s.Name, &s.AIPrompt, &s.Filters, &s.NotifyThreshold, &s.Channels,
+		&s.Active, &s.IsDefault, &s.PromptRevisionID, &s.CreatedAt, &s.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+type postgresSavedSearchRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSavedSearchRepository(db *pgxpool.Pool) SavedSearchRepository {
+	return &postgresSavedSearchRepository{db: db}
+}
+
+// Create inserts the search with its prompt as the first revision, and sets search.PromptRevisionID
+func (r *postgresSavedSearchRepository) Create(ctx context.Context, search *model.SavedSearch) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO saved_searches (id, user_id, name, ai_prompt, filters, notify_threshold, channels, active, is_default, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+	if _, err := tx.Exec(ctx, query,
+		search.ID, search.UserID, search.Name, search.AIPrompt, search.Filters, search.NotifyThreshold, search.Channels,
+		search.Active, search.IsDefault, search.CreatedAt, search.UpdatedAt,
+	); err != nil {
+		return err
+	}
+	if search.PromptRevisionID, err = recordRevision(ctx, tx, search.ID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *postgresSavedSearchRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.SavedSearch, error) {
+	query := `SELECT ` + savedSearchColumns + ` FROM saved_searches s WHERE s.id = $1`
+	return r.queryOne(ctx, query, id)
+}
+
+func (r *postgresSavedSearchRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]model.SavedSearch, error) {
+	query := `SELECT ` + savedSearchColumns + ` FROM saved_searches s WHERE s.user_id = $1 ORDER BY s.is_default DESC, s.created_at`
+	return r.queryMany(ctx, query, userID)
+}
+
+func (r *postgresSavedSearchRepository) GetDefault(ctx context.Context, userID uuid.UUID) (*model.SavedSearch, error) {
+	query := `SELECT ` + savedSearchColumns + ` FROM saved_searches s WHERE s.user_id = $1 AND s.is_default`
+	return r.queryOne(ctx, query, userID)
+}
+
+// GetActive returns the searches new jobs are matched against: active, with a prompt, and
+// owned by users who are neither disabled nor scheduled for deletion
+func (r *postgresSavedSearchRepository) GetActive(ctx context.Context) ([]model.SavedSearch, error) {
+	query := `
+		SELECT ` + savedSearchColumns + `
+		FROM saved_searches s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.active AND s.ai_prompt <> ''
+			AND u.disabled_at IS NULL AND u.deletion_scheduled_at IS NULL
+		ORDER BY s.user_id, s.created_at
+	`
+	return r.queryMany(ctx, query)
+}
+
+// Update saves the editable fields of one of the user's searches. A changed prompt is
+// recorded as a new revision and search.PromptRevisionID is set to it.
+func (r *postgresSavedSearchRepository) Update(ctx context.Context, search *model.SavedSearch) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE saved_searches
+		SET name = $3, ai_prompt = $4, filters = $5, notify_threshold = $6, channels = $7, active = $8, updated_at = $9
+		WHERE id = $1 AND user_id = $2
+	`
+	tag, err := tx.Exec(ctx, query,
+		search.ID, search.UserID, search.Name, search.AIPrompt, search.Filters, search.NotifyThreshold, search.Channels,
+		search.Active, search.UpdatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+	if search.PromptRevisionID, err = recordRevision(ctx, tx, search.ID); err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
+
+// Delete removes one of the user's searches, with its matches and their notifications
+func (r *postgresSavedSearchRepository) Delete(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM saved_searches WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// SyncDefault copies the user's legacy prompt and threshold into their default search,
+// creating it if needed. Changing the prompt re-activates the search unless it is now empty.
+func (r *postgresSavedSearchRepository) SyncDefault(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO saved_searches (id, user_id, name, ai_prompt, notify_threshold, active, is_default, created_at, updated_at)
+		SELECT $2, u.id, 'Default', COALESCE(u.ai_prompt, ''), COALESCE(u.notify_threshold, 70),
+			COALESCE(u.ai_prompt, '') <> '', TRUE, NOW(), NOW()
+		FROM users u WHERE u.id = $1
+		ON CONFLICT (user_id) WHERE is_default DO UPDATE SET
+			ai_prompt = EXCLUDED.ai_prompt,
+			notify_threshold = EXCLUDED.notify_threshold,
+			active = CASE WHEN saved_searches.ai_prompt = EXCLUDED.ai_prompt THEN saved_searches.active ELSE EXCLUDED.active END,
+			updated_at = NOW()
+		RETURNING id
+	`
+	var searchID uuid.UUID
+	err = tx.QueryRow(ctx, query, userID, uuid.New()).Scan(&searchID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := recordRevision(ctx, tx, searchID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// GetRevisions returns the search's prompt history, newest first
+func (r *postgresSavedSearchRepository) GetRevisions(ctx context.Context, searchID uuid.UUID, limit int) ([]model.PromptRevision, error) {
+	query := `
+		SELECT id, saved_search_id, user_id, ai_prompt, created_at
+		FROM prompt_revisions
+		WHERE saved_search_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+	rows, err := r.db.Query(ctx, query, searchID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []model.PromptRevision
+	for rows.Next() {
+		var rev model.PromptRevision
+		if err := rows.Scan(&rev.ID, &rev.SavedSearchID, &rev.UserID, &rev.AIPrompt, &rev.CreatedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
+// recordRevision adds the search's prompt to its history if it differs from the current
+// revision, and returns the current revision afterwards (nil while the prompt is empty)
+func recordRevision(ctx context.Context, tx pgx.Tx, searchID uuid.UUID) (*uuid.UUID, error) {
+	var prompt string
+	var current *uuid.UUID
+	var currentPrompt *string
+	err := tx.QueryRow(ctx, `
+		SELECT s.ai_prompt, s.prompt_revision_id, r.ai_prompt
+		FROM saved_searches s
+		LEFT JOIN prompt_revisions r ON r.id = s.prompt_revision_id
+		WHERE s.id = $1
+		FOR UPDATE OF s
+	`, searchID).Scan(&prompt, &current, &currentPrompt)
+	if err != nil {
+		return nil, err
+	}
+
+	if prompt == "" {
+		_, err := tx.Exec(ctx, `UPDATE saved_searches SET prompt_revision_id = NULL WHERE id = $1`, searchID)
+		return nil, err
+	}
+	if current != nil && currentPrompt != nil && *currentPrompt == prompt {
+		return current, nil
+	}
+
+	revisionID := uuid.New()
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO prompt_revisions (id, saved_search_id, user_id, ai_prompt, created_at)
+		SELECT $1, id, user_id, ai_prompt, NOW() FROM saved_searches WHERE id = $2
+	`, revisionID, searchID); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE saved_searches SET prompt_revision_id = $1 WHERE id = $2`, revisionID, searchID); err != nil {
+		return nil, err
+	}
+	return &revisionID, nil
+}
+
+func (r *postgresSavedSearchRepository) queryOne(ctx context.Context, query string, args ...interface{}) (*model.SavedSearch, error) {
+	search, err := scanSavedSearch(r.db.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+func (r *postgresSavedSearchRepository) queryMany(ctx context.Context, query string, args ...interface{}) ([]model.SavedSearch, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var searches []model.SavedSearch
+	for rows.Next() {
+		search, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, err
+		}
+		searches = append(searches, *search)
+	}
+	return searches, rows.Err()
+}
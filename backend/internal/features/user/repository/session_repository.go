@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jobping/backend/internal/features/user/model"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *model.Session) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*model.Session, error)
+	MarkRotated(ctx context.Context, tokenHash string, now time.Time) (*model.Session, error)
+	GetActiveByUserID(ctx context.Context, userID uuid.UUID) ([]model.Session, error)
+	RevokeFamily(ctx context.Context, userID, familyID uuid.UUID) (bool, error)
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+const sessionColumns = `id, family_id, user_id, token_hash, COALESCE(user_agent, ''), COALESCE(ip_address, ''),
+	started_at, created_at, expires_at, rotated_at, revoked_at`
+
+func scanSession(row pgx.Row) (*model.Session, error) {
+	var s model.Session
+	err := row.Scan(
+		&s.ID, &s.FamilyID, &s.UserID, &s.TokenHash, &s.UserAgent, &s.IPAddress,
+		&s.StartedAt, &s.CreatedAt, &s.ExpiresAt, &s.RotatedAt, &s.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+type postgresSessionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSessionRepository(db *pgxpool.Pool) SessionRepository {
+	return &postgresSessionRepository{db: db}
+}
+
+func (r *postgresSessionRepository) Create(ctx context.Context, session *model.Session) error {
+	query := `
+		INSERT INTO sessions (id, family_id, user_id, token_hash, user_agent, ip_address, started_at, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7, $8, $9)
+	`
+	_, err := r.db.Exec(ctx, query,
+		session.ID, session.FamilyID, session.UserID, session.TokenHash, session.UserAgent, session.IPAddress,
+		session.StartedAt, session.CreatedAt, session.ExpiresAt,
+	)
+	return err
+}
+
+func (r *postgresSessionRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*model.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE token_hash = $1`
+	session, err := scanSession(r.db.QueryRow(ctx, query, tokenHash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// MarkRotated atomically retires a refresh token if it is still usable and returns it.
+// It returns nil when the token is unknown, expired, already rotated or revoked, so of
+// two concurrent refreshes with the same token only one succeeds.
+func (r *postgresSessionRepository) MarkRotated(ctx context.Context, tokenHash string, now time.Time) (*model.Session, error) {
+	query := `
+		UPDATE sessions SET rotated_at = $2
+		WHERE token_hash = $1 AND rotated_at IS NULL AND revoked_at IS NULL AND expires_at > $2
+		RETURNING ` + sessionColumns
+	session, err := scanSession(r.db.QueryRow(ctx, query, tokenHash, now))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// GetActiveByUserID returns the current token of each signed-in family, most recently used first
+func (r *postgresSessionRepository) GetActiveByUserID(ctx context.Context, userID uuid.UUID) ([]model.Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sessions
+		WHERE user_id = $1 AND rotated_at IS NULL AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []model.Session
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, rows.Err()
+}
+
+func (r *postgresSessionRepository) RevokeFamily(ctx context.Context, userID, familyID uuid.UUID) (bool, error) {
+	query := `UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND family_id = $2 AND revoked_at IS NULL`
+	tag, err := r.db.Exec(ctx, query, userID, familyID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// RevokeAllForUser revokes every token of the user and returns how many signed-in sessions were ended
+func (r *postgresSessionRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	query := `
+		WITH revoked AS (
+			UPDATE sessions SET revoked_at = NOW()
+			WHERE user_id = $1 AND revoked_at IS NULL
+			RETURNING rotated_at, expires_at
+		)
+		SELECT COUNT(*) FROM revoked WHERE rotated_at IS NULL AND expires_at > NOW()
+	`
+	var count int64
+	err := r.db.QueryRow(ctx, query, userID).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jobping/backend/internal/features/user/model"
+)
+
+type TokenRepository interface {
+	Create(ctx context.Context, token *model.UserToken) error
+	Consume(ctx context.Context, purpose, tokenHash string, now time.Time) (*model.UserToken, error)
+	InvalidateForUser(ctx context.Context, userID uuid.UUID, purpose string) error
+}
+
+type postgresTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTokenRepository(db *pgxpool.Pool) TokenRepository {
+	return &postgresTokenRepository{db: db}
+}
+
+func (r *postgresTokenRepository) Create(ctx context.Context, token *model.UserToken) error {
+	query := `
+		INSERT INTO user_tokens (id, user_id, purpose, token_hash, email, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := r.db.Exec(ctx, query,
+		token.ID, token.UserID, token.Purpose, token.TokenHash, token.Email, token.ExpiresAt, token.CreatedAt,
+	)
+	return err
+}
+
+// Consume marks an unused, unexpired token as used and returns it. It returns nil when the
+// token is unknown, expired or already used, so each token works exactly once.
+func (r *postgresTokenRepository) Consume(ctx context.Context, purpose, tokenHash string, now time.Time) (*model.UserToken, error) {
+	query := `
+		UPDATE user_tokens SET used_at = $3
+		WHERE purpose = $1 AND token_hash = $2 AND used_at IS NULL AND expires_at > $3
+		RETURNING id, user_id, purpose, token_hash, email, expires_at, used_at, created_at
+	`
+	var t model.UserToken
+	err := r.db.QueryRow(ctx, query, purpose, tokenHash, now).Scan(
+		&t.ID, &t.UserID, &t.Purpose, &t.TokenHash, &t.Email, &t.ExpiresAt, &t.UsedAt, &t.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// InvalidateForUser expires the user's outstanding tokens for a purpose, e.g. older reset links
+// once a new one is sent or the password has been reset
+func (r *postgresTokenRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID, purpose string) error {
+	query := `UPDATE user_tokens SET used_at = NOW() WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL`
+	_, err := r.db.Exec(ctx, query, userID, purpose)
+	return err
+}
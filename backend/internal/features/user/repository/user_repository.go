@@ -3,10 +3,12 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	eventrepo "github.com/jobping/backend/internal/features/event/repository"
 	"github.com/jobping/backend/internal/features/user/model"
 )
 
@@ -16,16 +18,32 @@ type UserRepository interface {
 	GetUserByID(ctx context.Context, id uuid.UUID) (*model.User, error)
 	UpdateAIPrompt(ctx context.Context, userID uuid.UUID, prompt string) error
 	UpdateDiscordWebhook(ctx context.Context, userID uuid.UUID, webhook string) error
+	UpdateSlackWebhook(ctx context.Context, userID uuid.UUID, webhook string) error
 	UpdateNotifyThreshold(ctx context.Context, userID uuid.UUID, threshold int) error
 	GetUsersWithPrompts(ctx context.Context) ([]model.User, error)
+	UpdateNotificationSettings(ctx context.Context, userID uuid.UUID, settings *model.NotificationSettings) error
+	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
+	UpdateEmail(ctx context.Context, userID uuid.UUID, email string) error
+	MarkEmailVerified(ctx context.Context, userID uuid.UUID, email string) (bool, error)
+	UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error
+	ListUsers(ctx context.Context, filter model.UserFilter) ([]model.User, int, error)
+	UpdateRole(ctx context.Context, userID uuid.UUID, role string) (bool, error)
+	SetDisabled(ctx context.Context, userID uuid.UUID, disabledAt *time.Time) (bool, error)
+	ScheduleDeletion(ctx context.Context, userID uuid.UUID, at *time.Time) (bool, error)
+	GetDueForDeletion(ctx context.Context, now time.Time, limit int) ([]model.User, error)
 }
 
 type UserJobMatchRepository interface {
 	Create(ctx context.Context, match *model.UserJobMatch) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.UserJobMatch, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]model.UserJobMatch, error)
+	GetBySavedSearchID(ctx context.Context, userID, searchID uuid.UUID) ([]model.UserJobMatch, error)
 	GetByUserAndJob(ctx context.Context, userID, jobID uuid.UUID) (*model.UserJobMatch, error)
+	GetBySearchAndJob(ctx context.Context, searchID, jobID uuid.UUID) (*model.UserJobMatch, error)
 	MarkNotified(ctx context.Context, id uuid.UUID) error
+	MarkDeferred(ctx context.Context, id uuid.UUID, reason string) error
 	GetUnnotifiedAboveThreshold(ctx context.Context) ([]model.UserJobMatch, error)
+	GetDeferred(ctx context.Context) ([]model.UserJobMatch, error)
 }
 
 type PreferenceRepository interface {
@@ -47,53 +65,44 @@ func NewUserRepository(db *pgxpool.Pool) UserRepository {
 
 func (r *postgresUserRepository) CreateUser(ctx context.Context, user *model.User) error {
 	query := `
-		INSERT INTO users (id, username, password_hash, notify_threshold, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO users (id, username, password_hash, email, email_verified_at, notify_threshold, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 	threshold := user.NotifyThreshold
 	if threshold == 0 {
 		threshold = 70
 	}
+	if user.Role == "" {
+		user.Role = model.RoleUser
+	}
 	_, err := r.db.Exec(ctx, query,
-		user.ID, user.Username, user.PasswordHash, threshold, user.CreatedAt, user.UpdatedAt,
+		user.ID, user.Username, user.PasswordHash, user.Email, user.EmailVerifiedAt, threshold, user.Role, user.CreatedAt, user.UpdatedAt,
 	)
 	return err
 }
 
 func (r *postgresUserRepository) GetUserByUsername(ctx context.Context, username string) (*model.User, error) {
-	query := `
-		SELECT id, username, password_hash, ai_prompt, discord_webhook, COALESCE(notify_threshold, 70), created_at, updated_at
-		FROM users WHERE username = $1
-	`
-	var user model.User
-	err := r.db.QueryRow(ctx, query, username).Scan(
-		&user.ID, &user.Username, &user.PasswordHash, &user.AIPrompt, &user.DiscordWebhook, &user.NotifyThreshold, &user.CreatedAt, &user.UpdatedAt,
-	)
+	query := `SELECT ` + userColumns + ` FROM users WHERE username = $1`
+	user, err := scanUser(r.db.QueryRow(ctx, query, username))
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-	return &user, nil
+	return user, nil
 }
 
 func (r *postgresUserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*model.User, error) {
-	query := `
-		SELECT id, username, password_hash, ai_prompt, discord_webhook, COALESCE(notify_threshold, 70), created_at, updated_at
-		FROM users WHERE id = $1
-	`
-	var user model.User
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&user.ID, &user.Username, &user.PasswordHash, &user.AIPrompt, &user.DiscordWebhook, &user.NotifyThreshold, &user.CreatedAt, &user.UpdatedAt,
-	)
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+	user, err := scanUser(r.db.QueryRow(ctx, query, id))
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-	return &user, nil
+	return user, nil
 }
 
 func (r *postgresUserRepository) UpdateAIPrompt(ctx context.Context, userID uuid.UUID, prompt string) error {
@@ -108,6 +117,12 @@ func (r *postgresUserRepository) UpdateDiscordWebhook(ctx context.Context, userI
 	return err
 }
 
+func (r *postgresUserRepository) UpdateSlackWebhook(ctx context.Context, userID uuid.UUID, webhook string) error {
+	query := `UPDATE users SET slack_webhook = $1, updated_at = NOW() WHERE id = $2`
+	_, err := r.db.Exec(ctx, query, webhook, userID)
+	return err
+}
+
 func (r *postgresUserRepository) UpdateNotifyThreshold(ctx context.Context, userID uuid.UUID, threshold int) error {
 	query := `UPDATE users SET notify_threshold = $1, updated_at = NOW() WHERE id = $2`
 	_, err := r.db.Exec(ctx, query, threshold, userID)
@@ -115,11 +130,117 @@ func (r *postgresUserRepository) UpdateNotifyThreshold(ctx context.Context, user
 }
 
 func (r *postgresUserRepository) GetUsersWithPrompts(ctx context.Context) ([]model.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users
+		WHERE ai_prompt IS NOT NULL AND ai_prompt != '' AND disabled_at IS NULL AND deletion_scheduled_at IS NULL`
+	return r.queryUsers(ctx, query)
+}
+
+func (r *postgresUserRepository) UpdateNotificationSettings(ctx context.Context, userID uuid.UUID, settings *model.NotificationSettings) error {
+	query := `
+		UPDATE users
+		SET timezone = $1, quiet_hours_start = $2, quiet_hours_end = $3, max_notifications_per_hour = $4, updated_at = NOW()
+		WHERE id = $5
+	`
+	_, err := r.db.Exec(ctx, query,
+		settings.Timezone, settings.QuietHoursStart, settings.QuietHoursEnd, settings.MaxNotificationsPerHour, userID,
+	)
+	return err
+}
+
+func (r *postgresUserRepository) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE LOWER(email) = LOWER($1)`
+	user, err := scanUser(r.db.QueryRow(ctx, query, email))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// UpdateEmail sets a new address, which starts out unverified. An empty email removes it.
+func (r *postgresUserRepository) UpdateEmail(ctx context.Context, userID uuid.UUID, email string) error {
+	query := `UPDATE users SET email = NULLIF($1, ''), email_verified_at = NULL, updated_at = NOW() WHERE id = $2`
+	_, err := r.db.Exec(ctx, query, email, userID)
+	return err
+}
+
+// MarkEmailVerified verifies the user's address only if it is still the one the token was sent to
+func (r *postgresUserRepository) MarkEmailVerified(ctx context.Context, userID uuid.UUID, email string) (bool, error) {
 	query := `
-		SELECT id, username, password_hash, ai_prompt, discord_webhook, COALESCE(notify_threshold, 70), created_at, updated_at
-		FROM users WHERE ai_prompt IS NOT NULL AND ai_prompt != ''
+		UPDATE users SET email_verified_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND LOWER(email) = LOWER($2)
+	`
+	tag, err := r.db.Exec(ctx, query, userID, email)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *postgresUserRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	query := `UPDATE users SET password_hash = $1, updated_at = NOW() WHERE id = $2`
+	_, err := r.db.Exec(ctx, query, passwordHash, userID)
+	return err
+}
+
+// ListUsers returns a page of users, newest first, and the total number matching the filter
+func (r *postgresUserRepository) ListUsers(ctx context.Context, filter model.UserFilter) ([]model.User, int, error) {
+	where := `
+		WHERE ($1 = '' OR username ILIKE '%' || $1 || '%' OR email ILIKE '%' || $1 || '%')
+		  AND ($2 = '' OR role = $2)
 	`
-	rows, err := r.db.Query(ctx, query)
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM users`+where, filter.Query, filter.Role).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users` + where + ` ORDER BY created_at DESC LIMIT $3 OFFSET $4`
+	users, err := r.queryUsers(ctx, query, filter.Query, filter.Role, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (r *postgresUserRepository) UpdateRole(ctx context.Context, userID uuid.UUID, role string) (bool, error) {
+	tag, err := r.db.Exec(ctx, `UPDATE users SET role = $1, updated_at = NOW() WHERE id = $2`, role, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// SetDisabled disables the user at disabledAt, or re-enables them when it is nil
+func (r *postgresUserRepository) SetDisabled(ctx context.Context, userID uuid.UUID, disabledAt *time.Time) (bool, error) {
+	tag, err := r.db.Exec(ctx, `UPDATE users SET disabled_at = $1, updated_at = NOW() WHERE id = $2`, disabledAt, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ScheduleDeletion sets when the account will be purged, or cancels the deletion when at is nil
+func (r *postgresUserRepository) ScheduleDeletion(ctx context.Context, userID uuid.UUID, at *time.Time) (bool, error) {
+	tag, err := r.db.Exec(ctx, `UPDATE users SET deletion_scheduled_at = $1, updated_at = NOW() WHERE id = $2`, at, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetDueForDeletion returns users whose grace period has ended, oldest first
+func (r *postgresUserRepository) GetDueForDeletion(ctx context.Context, now time.Time, limit int) ([]model.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users
+		WHERE deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= $1
+		ORDER BY deletion_scheduled_at LIMIT $2`
+	return r.queryUsers(ctx, query, now, limit)
+}
+
+func (r *postgresUserRepository) queryUsers(ctx context.Context, query string, args ...interface{}) ([]model.User, error) {
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -127,17 +248,32 @@ func (r *postgresUserRepository) GetUsersWithPrompts(ctx context.Context) ([]mod
 
 	var users []model.User
 	for rows.Next() {
-		var user model.User
-		if err := rows.Scan(
-			&user.ID, &user.Username, &user.PasswordHash, &user.AIPrompt, &user.DiscordWebhook, &user.NotifyThreshold, &user.CreatedAt, &user.UpdatedAt,
-		); err != nil {
+		user, err := scanUser(rows)
+		if err != nil {
 			return nil, err
 		}
-		users = append(users, user)
+		users = append(users, *user)
 	}
 	return users, rows.Err()
 }
 
+// userColumns is the column list scanned by scanUser
+const userColumns = `id, username, password_hash, email, email_verified_at, ai_prompt, discord_webhook, slack_webhook, COALESCE(notify_threshold, 70),
+	timezone, quiet_hours_start, quiet_hours_end, max_notifications_per_hour, role, disabled_at, deletion_scheduled_at, created_at, updated_at`
+
+func scanUser(row pgx.Row) (*model.User, error) {
+	var user model.User
+	err := row.Scan(
+		&user.ID, &user.Username, &user.PasswordHash, &user.Email, &user.EmailVerifiedAt, &user.AIPrompt, &user.DiscordWebhook, &user.SlackWebhook, &user.NotifyThreshold,
+		&user.Timezone, &user.QuietHoursStart, &user.QuietHoursEnd, &user.MaxNotificationsPerHour, &user.Role, &user.DisabledAt,
+		&user.DeletionScheduledAt, &user.CreatedAt, &user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 type postgresPreferenceRepository struct {
 	db *pgxpool.Pool
 }
@@ -246,75 +382,132 @@ func NewUserJobMatchRepository(db *pgxpool.Pool) UserJobMatchRepository {
 }
 
 func (r *postgresUserJobMatchRepository) Create(ctx context.Context, match *model.UserJobMatch) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
-		INSERT INTO user_job_matches (id, user_id, job_id, score, analysis, notified, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		ON CONFLICT (user_id, job_id) DO UPDATE SET score = $4, analysis = $5
+		INSERT INTO user_job_matches (id, user_id, saved_search_id, job_id, score, analysis, notified, prompt_revision_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (saved_search_id, job_id) DO UPDATE SET score = $5, analysis = $6, prompt_revision_id = $8
+		RETURNING id
 	`
-	_, err := r.db.Exec(ctx, query,
-		match.ID, match.UserID, match.JobID, match.Score, match.Analysis, match.Notified, match.CreatedAt,
-	)
-	return err
+	var matchID uuid.UUID
+	if err := tx.QueryRow(ctx, query,
+		match.ID, match.UserID, match.SavedSearchID, match.JobID, match.Score, match.Analysis, match.Notified,
+		match.PromptRevisionID, match.CreatedAt,
+	).Scan(&matchID); err != nil {
+		return err
+	}
+
+	if err := eventrepo.Publish(ctx, tx, match.UserID, eventrepo.TypeMatchCreated, map[string]interface{}{
+		"match_id":           matchID,
+		"saved_search_id":    match.SavedSearchID,
+		"prompt_revision_id": match.PromptRevisionID,
+		"job_id":             match.JobID,
+		"score":              match.Score,
+		"analysis":           match.Analysis,
+		"created_at":         match.CreatedAt,
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 func (r *postgresUserJobMatchRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]model.UserJobMatch, error) {
 	query := `
-		SELECT id, user_id, job_id, score, analysis, notified, created_at
-		FROM user_job_matches WHERE user_id = $1
-		ORDER BY score DESC, created_at DESC
+		SELECT ` + matchColumns + `
+		FROM user_job_matches m WHERE m.user_id = $1
+		ORDER BY m.score DESC, m.created_at DESC
 	`
-	rows, err := r.db.Query(ctx, query, userID)
+	return r.queryMatches(ctx, query, userID)
+}
+
+func (r *postgresUserJobMatchRepository) GetBySavedSearchID(ctx context.Context, userID, searchID uuid.UUID) ([]model.UserJobMatch, error) {
+	query := `
+		SELECT ` + matchColumns + `
+		FROM user_job_matches m WHERE m.user_id = $1 AND m.saved_search_id = $2
+		ORDER BY m.score DESC, m.created_at DESC
+	`
+	return r.queryMatches(ctx, query, userID, searchID)
+}
+
+func (r *postgresUserJobMatchRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.UserJobMatch, error) {
+	query := `SELECT ` + matchColumns + ` FROM user_job_matches m WHERE m.id = $1`
+	match, err := scanMatch(r.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	return match, nil
+}
 
-	var matches []model.UserJobMatch
-	for rows.Next() {
-		var match model.UserJobMatch
-		if err := rows.Scan(
-			&match.ID, &match.UserID, &match.JobID, &match.Score, &match.Analysis, &match.Notified, &match.CreatedAt,
-		); err != nil {
-			return nil, err
-		}
-		matches = append(matches, match)
+// GetByUserAndJob returns the user's best-scoring match for the job across their saved searches
+func (r *postgresUserJobMatchRepository) GetByUserAndJob(ctx context.Context, userID, jobID uuid.UUID) (*model.UserJobMatch, error) {
+	query := `SELECT ` + matchColumns + ` FROM user_job_matches m WHERE m.user_id = $1 AND m.job_id = $2
+		ORDER BY m.score DESC LIMIT 1`
+	match, err := scanMatch(r.db.QueryRow(ctx, query, userID, jobID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
 	}
-	return matches, rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return match, nil
 }
 
-func (r *postgresUserJobMatchRepository) GetByUserAndJob(ctx context.Context, userID, jobID uuid.UUID) (*model.UserJobMatch, error) {
-	query := `
-		SELECT id, user_id, job_id, score, analysis, notified, created_at
-		FROM user_job_matches WHERE user_id = $1 AND job_id = $2
-	`
-	var match model.UserJobMatch
-	err := r.db.QueryRow(ctx, query, userID, jobID).Scan(
-		&match.ID, &match.UserID, &match.JobID, &match.Score, &match.Analysis, &match.Notified, &match.CreatedAt,
-	)
+func (r *postgresUserJobMatchRepository) GetBySearchAndJob(ctx context.Context, searchID, jobID uuid.UUID) (*model.UserJobMatch, error) {
+	query := `SELECT ` + matchColumns + ` FROM user_job_matches m WHERE m.saved_search_id = $1 AND m.job_id = $2`
+	match, err := scanMatch(r.db.QueryRow(ctx, query, searchID, jobID))
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-	return &match, nil
+	return match, nil
 }
 
 func (r *postgresUserJobMatchRepository) MarkNotified(ctx context.Context, id uuid.UUID) error {
-	query := `UPDATE user_job_matches SET notified = TRUE WHERE id = $1`
+	query := `UPDATE user_job_matches SET notified = TRUE, deferred_at = NULL WHERE id = $1`
 	_, err := r.db.Exec(ctx, query, id)
 	return err
 }
 
+func (r *postgresUserJobMatchRepository) MarkDeferred(ctx context.Context, id uuid.UUID, reason string) error {
+	query := `UPDATE user_job_matches SET deferred_at = NOW(), deferred_reason = $1 WHERE id = $2 AND notified = FALSE`
+	_, err := r.db.Exec(ctx, query, reason, id)
+	return err
+}
+
 func (r *postgresUserJobMatchRepository) GetUnnotifiedAboveThreshold(ctx context.Context) ([]model.UserJobMatch, error) {
 	query := `
-		SELECT m.id, m.user_id, m.job_id, m.score, m.analysis, m.notified, m.created_at
+		SELECT ` + matchColumns + `
 		FROM user_job_matches m
-		JOIN users u ON m.user_id = u.id
-		WHERE m.notified = FALSE AND m.score >= u.notify_threshold
+		JOIN saved_searches s ON m.saved_search_id = s.id
+		WHERE m.notified = FALSE AND m.score >= s.notify_threshold
 		ORDER BY m.created_at DESC
 	`
-	rows, err := r.db.Query(ctx, query)
+	return r.queryMatches(ctx, query)
+}
+
+func (r *postgresUserJobMatchRepository) GetDeferred(ctx context.Context) ([]model.UserJobMatch, error) {
+	query := `
+		SELECT ` + matchColumns + `
+		FROM user_job_matches m
+		WHERE m.notified = FALSE AND m.deferred_at IS NOT NULL
+		ORDER BY m.user_id, m.score DESC
+	`
+	return r.queryMatches(ctx, query)
+}
+
+func (r *postgresUserJobMatchRepository) queryMatches(ctx context.Context, query string, args ...interface{}) ([]model.UserJobMatch, error) {
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -322,13 +515,26 @@ func (r *postgresUserJobMatchRepository) GetUnnotifiedAboveThreshold(ctx context
 
 	var matches []model.UserJobMatch
 	for rows.Next() {
-		var match model.UserJobMatch
-		if err := rows.Scan(
-			&match.ID, &match.UserID, &match.JobID, &match.Score, &match.Analysis, &match.Notified, &match.CreatedAt,
-		); err != nil {
+		match, err := scanMatch(rows)
+		if err != nil {
 			return nil, err
 		}
-		matches = append(matches, match)
+		matches = append(matches, *match)
 	}
 	return matches, rows.Err()
 }
+
+// matchColumns is the column list scanned by scanMatch; queries alias user_job_matches as m
+const matchColumns = `m.id, m.user_id, m.saved_search_id, m.job_id, m.score, m.analysis, m.notified, m.deferred_at, m.deferred_reason, m.prompt_revision_id, m.created_at`
+
+func scanMatch(row pgx.Row) (*model.UserJobMatch, error) {
+	var match model.UserJobMatch
+	err := row.Scan(
+		&match.ID, &match.UserID, &match.SavedSearchID, &match.JobID, &match.Score, &match.Analysis, &match.Notified,
+		&match.DeferredAt, &match.DeferredReason, &match.PromptRevisionID, &match.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
@@ -0,0 +1,76 @@
+package resume
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// docxMainPart is where Word keeps the document body
+const docxMainPart = "word/document.xml"
+
+// extractDOCX reads the text runs of the document body. Tabs and line breaks are kept,
+// and each paragraph ends a line.
+func extractDOCX(data []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", ErrUnsupportedFormat
+	}
+
+	var part *zip.File
+	for _, f := range archive.File {
+		if f.Name == docxMainPart {
+			part = f
+			break
+		}
+	}
+	if part == nil {
+		return "", ErrUnsupportedFormat
+	}
+
+	r, err := part.Open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	var text strings.Builder
+	inText := false
+	decoder := xml.NewDecoder(io.LimitReader(r, 10<<20))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", ErrUnsupportedFormat
+		}
+
+		// Elements are matched by local name; the WordprocessingML namespace is not checked
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				text.WriteByte('\t')
+			case "br", "cr":
+				text.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(t)
+			}
+		}
+	}
+	return text.String(), nil
+}
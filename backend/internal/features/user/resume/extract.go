@@ -0,0 +1,105 @@
+// Package resume extracts plain text from uploaded resumes (PDF, DOCX or plain text)
+// without calling out to any service.
+package resume
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Formats recognised by Detect
+const (
+	FormatPDF  = "pdf"
+	FormatDOCX = "docx"
+	FormatText = "text"
+)
+
+// MaxTextLength caps the extracted text; longer resumes are cut off
+const MaxTextLength = 20000
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported resume format; upload a PDF, DOCX or plain text file")
+	// ErrNoText is returned for files without extractable text, such as scanned PDFs
+	ErrNoText = errors.New("no readable text found in the resume")
+)
+
+// minTextLength is the least text a resume must yield to be worth analysing
+const minTextLength = 20
+
+// Detect returns the format of data from its leading bytes, or "" if it is not supported
+func Detect(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF-")):
+		return FormatPDF
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		// DOCX is a zip archive; extractDOCX checks for the document part
+		return FormatDOCX
+	case utf8.Valid(data) && !bytes.ContainsRune(data, 0):
+		return FormatText
+	}
+	return ""
+}
+
+// Extract detects the format of data and returns it with the document's text
+func Extract(data []byte) (string, string, error) {
+	format := Detect(data)
+
+	var text string
+	var err error
+	switch format {
+	case FormatPDF:
+		text, err = extractPDF(data)
+	case FormatDOCX:
+		text, err = extractDOCX(data)
+	case FormatText:
+		text = string(data)
+	default:
+		return "", "", ErrUnsupportedFormat
+	}
+	if err != nil {
+		return format, "", err
+	}
+
+	text = normalize(text)
+	if utf8.RuneCountInString(text) < minTextLength {
+		return format, "", ErrNoText
+	}
+	return format, text, nil
+}
+
+// normalize drops control characters, collapses runs of spaces, trims each line,
+// removes blank lines and truncates to MaxTextLength
+func normalize(text string) string {
+	var out strings.Builder
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r", "\n"), "\n") {
+		line = strings.Join(strings.FieldsFunc(line, func(r rune) bool {
+			return unicode.IsSpace(r) || !unicode.IsPrint(r)
+		}), " ")
+		if line == "" {
+			continue
+		}
+		if out.Len() > 0 {
+			out.WriteByte('\n')
+		}
+		out.WriteString(line)
+		if out.Len() >= MaxTextLength {
+			break
+		}
+	}
+	return truncateRunes(out.String(), MaxTextLength)
+}
+
+func truncateRunes(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	// Back up to the start of a rune so the result stays valid UTF-8
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
@@ -0,0 +1,272 @@
+package resume
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxStreamSize caps how much a single PDF stream may inflate to
+	maxStreamSize = 10 << 20
+	// maxDecodedSize caps how much all of a document's streams may inflate to together, so a
+	// small upload of many highly compressed streams cannot exhaust memory
+	maxDecodedSize = 20 << 20
+)
+
+// extractPDF pulls the text drawn by the page content streams. It handles the common case,
+// uncompressed or Flate-compressed streams showing text with Tj/TJ in a single-byte encoding,
+// and skips everything else, so scanned or CID-keyed PDFs usually yield ErrNoText.
+func extractPDF(data []byte) (string, error) {
+	var text strings.Builder
+	eachPDFStream(data, func(stream []byte) {
+		if bytes.Contains(stream, []byte("BT")) {
+			pdfContentText(stream, &text)
+		}
+	})
+	return text.String(), nil
+}
+
+// eachPDFStream calls fn with the decoded body of each of the file's streams in turn, so only
+// one decoded stream is held at a time. Streams with filters other than FlateDecode (images,
+// mostly) are left out. Inflating draws on maxDecodedSize for the whole file; once it is used
+// up, the remaining streams are skipped.
+func eachPDFStream(data []byte, fn func(stream []byte)) {
+	budget := maxDecodedSize
+	rest := data
+	for budget > 0 {
+		i := bytes.Index(rest, []byte("stream"))
+		if i < 0 {
+			break
+		}
+		if i >= 3 && string(rest[i-3:i]) == "end" {
+			rest = rest[i+len("stream"):]
+			continue
+		}
+
+		// The stream dictionary lies between "N 0 obj" and the stream keyword
+		dict := rest[:i]
+		if objStart := bytes.LastIndex(dict, []byte("obj")); objStart >= 0 {
+			dict = dict[objStart:]
+		}
+
+		start := i + len("stream")
+		if start < len(rest) && rest[start] == '\r' {
+			start++
+		}
+		if start < len(rest) && rest[start] == '\n' {
+			start++
+		}
+		end := bytes.Index(rest[start:], []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		body := rest[start : start+end]
+		rest = rest[start+end+len("endstream"):]
+
+		switch {
+		case bytes.Contains(dict, []byte("/FlateDecode")):
+			decoded := inflate(body, min(maxStreamSize, budget))
+			budget -= len(decoded)
+			if len(decoded) > 0 {
+				fn(decoded)
+			}
+		case !bytes.Contains(dict, []byte("/Filter")):
+			fn(body)
+		}
+	}
+}
+
+// inflate decompresses a Flate stream up to limit bytes, keeping whatever was read before any error
+func inflate(body []byte, limit int) []byte {
+	r, err := zlib.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+	defer r.Close()
+	decoded, _ := io.ReadAll(io.LimitReader(r, int64(limit)))
+	return decoded
+}
+
+// pdfContentText appends the strings shown by a content stream's text operators to out.
+// Line-positioning operators start a new line; large negative TJ offsets become spaces.
+func pdfContentText(content []byte, out *strings.Builder) {
+	var pending []string
+	inArray := false
+
+	newline := func() {
+		if out.Len() > 0 && !strings.HasSuffix(out.String(), "\n") {
+			out.WriteByte('\n')
+		}
+	}
+
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case c == '(':
+			s, next := pdfLiteralString(content, i)
+			pending = append(pending, s)
+			i = next
+		case c == '<' && i+1 < len(content) && content[i+1] == '<':
+			i += 2
+		case c == '<':
+			s, next := pdfHexString(content, i)
+			pending = append(pending, s)
+			i = next
+		case c == '[':
+			inArray = true
+			i++
+		case c == ']':
+			inArray = false
+			i++
+		case c == '%':
+			for i < len(content) && content[i] != '\n' && content[i] != '\r' {
+				i++
+			}
+		case c == '/':
+			i++
+			for i < len(content) && isPDFRegular(content[i]) {
+				i++
+			}
+		case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+			start := i
+			i++
+			for i < len(content) && (content[i] == '.' || (content[i] >= '0' && content[i] <= '9')) {
+				i++
+			}
+			// In a TJ array, a large negative offset is the gap between two words
+			if inArray {
+				if n, err := strconv.ParseFloat(string(content[start:i]), 64); err == nil && n < -200 {
+					pending = append(pending, " ")
+				}
+			}
+		case isPDFRegular(c):
+			start := i
+			for i < len(content) && isPDFRegular(content[i]) {
+				i++
+			}
+			switch op := string(content[start:i]); op {
+			case "Tj", "TJ":
+				out.WriteString(strings.Join(pending, ""))
+			case "'", "\"":
+				newline()
+				out.WriteString(strings.Join(pending, ""))
+			case "Td", "TD", "T*", "Tm", "ET":
+				newline()
+			case "BI":
+				// Inline image data is binary; skip to the end marker
+				if end := bytes.Index(content[i:], []byte("EI")); end >= 0 {
+					i += end + 2
+				} else {
+					i = len(content)
+				}
+			}
+			pending = pending[:0]
+		default:
+			i++
+		}
+	}
+	newline()
+}
+
+// pdfLiteralString decodes the (...) string starting at content[start] and returns it
+// with the index just past its closing parenthesis
+func pdfLiteralString(content []byte, start int) (string, int) {
+	var s []byte
+	depth := 0
+	i := start
+	for i < len(content) {
+		c := content[i]
+		switch {
+		case c == '(':
+			if depth > 0 {
+				s = append(s, c)
+			}
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return decodePDFBytes(s), i + 1
+			}
+			s = append(s, c)
+		case c == '\\' && i+1 < len(content):
+			i++
+			switch e := content[i]; e {
+			case 'n':
+				s = append(s, '\n')
+			case 'r':
+				s = append(s, '\r')
+			case 't':
+				s = append(s, '\t')
+			case 'b', 'f':
+			case '\r', '\n':
+				// Line continuation
+				if e == '\r' && i+1 < len(content) && content[i+1] == '\n' {
+					i++
+				}
+			default:
+				if e >= '0' && e <= '7' {
+					n := 0
+					for j := 0; j < 3 && i < len(content) && content[i] >= '0' && content[i] <= '7'; j++ {
+						n = n*8 + int(content[i]-'0')
+						i++
+					}
+					i--
+					s = append(s, byte(n))
+				} else {
+					s = append(s, e)
+				}
+			}
+		default:
+			s = append(s, c)
+		}
+		i++
+	}
+	return decodePDFBytes(s), i
+}
+
+// pdfHexString decodes the <...> string starting at content[start]
+func pdfHexString(content []byte, start int) (string, int) {
+	end := bytes.IndexByte(content[start:], '>')
+	if end < 0 {
+		return "", len(content)
+	}
+	var digits []byte
+	for _, c := range content[start+1 : start+end] {
+		if (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F') {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	s := make([]byte, 0, len(digits)/2)
+	for i := 0; i < len(digits); i += 2 {
+		n, _ := strconv.ParseUint(string(digits[i:i+2]), 16, 8)
+		s = append(s, byte(n))
+	}
+	return decodePDFBytes(s), start + end + 1
+}
+
+// decodePDFBytes reads string bytes as Latin-1, which covers the printable range of the
+// standard PDF text encodings. Zero bytes, as in two-byte encodings of ASCII, are dropped.
+func decodePDFBytes(s []byte) string {
+	var b strings.Builder
+	for _, c := range s {
+		if c != 0 {
+			b.WriteRune(rune(c))
+		}
+	}
+	return b.String()
+}
+
+// isPDFRegular reports whether c can be part of a PDF keyword or name
+func isPDFRegular(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n', '\f', 0, '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return false
+	}
+	return true
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/repository"
+	"github.com/jobping/backend/internal/features/user/usererr"
+	"github.com/jobping/backend/internal/mailer"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	verificationTTL  = 48 * time.Hour
+	passwordResetTTL = time.Hour
+)
+
+// AccountService handles the email address lifecycle: verification links and password resets
+type AccountService struct {
+	userRepo  repository.UserRepository
+	tokenRepo repository.TokenRepository
+	sessions  *SessionService
+	policy    *PasswordPolicy
+	mailer    mailer.Mailer
+	baseURL   string
+}
+
+func NewAccountService(
+	userRepo repository.UserRepository,
+	tokenRepo repository.TokenRepository,
+	sessions *SessionService,
+	policy *PasswordPolicy,
+	m mailer.Mailer,
+	baseURL string,
+) *AccountService {
+	return &AccountService{
+		userRepo:  userRepo,
+		tokenRepo: tokenRepo,
+		sessions:  sessions,
+		policy:    policy,
+		mailer:    m,
+		baseURL:   strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// SetEmail changes the user's address and sends a verification link to it. An empty email removes it.
+func (s *AccountService) SetEmail(ctx context.Context, userID uuid.UUID, email string) error {
+	if email != "" {
+		normalized, err := NormalizeEmail(email)
+		if err != nil {
+			return err
+		}
+		email = normalized
+
+		existing, err := s.userRepo.GetUserByEmail(ctx, email)
+		if err != nil {
+			return err
+		}
+		if existing != nil && existing.ID != userID {
+			return usererr.ErrEmailAlreadyExists
+		}
+	}
+
+	if err := s.userRepo.UpdateEmail(ctx, userID, email); err != nil {
+		return err
+	}
+	if err := s.tokenRepo.InvalidateForUser(ctx, userID, model.TokenPurposeEmailVerification); err != nil {
+		return err
+	}
+	if email == "" {
+		return nil
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return usererr.ErrUserNotFound
+	}
+	return s.SendVerification(ctx, user)
+}
+
+// SendVerification emails a verification link to the user's current address
+func (s *AccountService) SendVerification(ctx context.Context, user *model.User) error {
+	if user.Email == nil {
+		return usererr.ErrEmailNotSet
+	}
+	if user.EmailVerifiedAt != nil {
+		return usererr.ErrEmailAlreadyVerified
+	}
+
+	token, err := s.issueToken(ctx, user.ID, model.TokenPurposeEmailVerification, *user.Email, verificationTTL)
+	if err != nil {
+		return err
+	}
+
+	link := s.link("/verify-email", token)
+	return s.mailer.Send(ctx, mailer.Message{
+		To:       *user.Email,
+		Subject:  "Verify your JobPing email address",
+		TextBody: fmt.Sprintf("Hi %s,\n\nConfirm this address for your JobPing account:\n%s\n\nThe link expires in 48 hours. If you didn't add this address, ignore this email.\n", user.Username, link),
+	})
+}
+
+// ResendVerification sends a new verification link, invalidating earlier ones
+func (s *AccountService) ResendVerification(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return usererr.ErrUserNotFound
+	}
+	if err := s.tokenRepo.InvalidateForUser(ctx, userID, model.TokenPurposeEmailVerification); err != nil {
+		return err
+	}
+	return s.SendVerification(ctx, user)
+}
+
+// VerifyEmail consumes a verification token. It fails if the user has changed address since it was sent.
+func (s *AccountService) VerifyEmail(ctx context.Context, rawToken string) error {
+	token, err := s.tokenRepo.Consume(ctx, model.TokenPurposeEmailVerification, hashToken(rawToken), time.Now())
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return usererr.ErrInvalidToken
+	}
+
+	verified, err := s.userRepo.MarkEmailVerified(ctx, token.UserID, token.Email)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return usererr.ErrInvalidToken
+	}
+	return nil
+}
+
+// ForgotPassword emails a reset link if the address belongs to an account and is verified.
+// It reports success either way so callers cannot probe which addresses are registered.
+func (s *AccountService) ForgotPassword(ctx context.Context, email string) error {
+	normalized, err := NormalizeEmail(email)
+	if err != nil {
+		return nil
+	}
+
+	user, err := s.userRepo.GetUserByEmail(ctx, normalized)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.Email == nil || user.EmailVerifiedAt == nil {
+		log.Printf("Password reset requested for unknown or unverified email")
+		return nil
+	}
+
+	// Only the newest link works
+	if err := s.tokenRepo.InvalidateForUser(ctx, user.ID, model.TokenPurposePasswordReset); err != nil {
+		return err
+	}
+	token, err := s.issueToken(ctx, user.ID, model.TokenPurposePasswordReset, *user.Email, passwordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	link := s.link("/reset-password", token)
+	return s.mailer.Send(ctx, mailer.Message{
+		To:       *user.Email,
+		Subject:  "Reset your JobPing password",
+		TextBody: fmt.Sprintf("Hi %s,\n\nReset your JobPing password here:\n%s\n\nThe link expires in 1 hour and can be used once. If you didn't ask for this, ignore this email.\n", user.Username, link),
+	})
+}
+
+// ResetPassword consumes a reset token, sets the new password and signs the user out everywhere
+func (s *AccountService) ResetPassword(ctx context.Context, rawToken, newPassword string) error {
+	// Checked before consuming the token so a rejected password doesn't burn the link
+	if err := s.policy.Validate(newPassword); err != nil {
+		return err
+	}
+
+	token, err := s.tokenRepo.Consume(ctx, model.TokenPurposePasswordReset, hashToken(rawToken), time.Now())
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return usererr.ErrInvalidToken
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.UpdatePassword(ctx, token.UserID, string(hash)); err != nil {
+		return err
+	}
+	if err := s.tokenRepo.InvalidateForUser(ctx, token.UserID, model.TokenPurposePasswordReset); err != nil {
+		return err
+	}
+	if _, err := s.sessions.LogoutAll(ctx, token.UserID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *AccountService) issueToken(ctx context.Context, userID uuid.UUID, purpose, email string, ttl time.Duration) (string, error) {
+	rawToken, err := newToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	err = s.tokenRepo.Create(ctx, &model.UserToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(rawToken),
+		Email:     email,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return "", err
+	}
+	return rawToken, nil
+}
+
+func (s *AccountService) link(path, token string) string {
+	return s.baseURL + path + "?token=" + url.QueryEscape(token)
+}
+
+// NormalizeEmail validates a bare address (no display name) and lowercases it
+func NormalizeEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || len(email) > 255 {
+		return "", usererr.ErrInvalidEmail
+	}
+	return strings.ToLower(email), nil
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/repository"
+	"github.com/jobping/backend/internal/features/user/usererr"
+	"github.com/jobping/backend/internal/mailer"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testBaseURL = "https://jobping.test"
+
+// memoryUsers implements the parts of UserRepository the account and OIDC flows use
+type memoryUsers struct {
+	repository.UserRepository
+
+	mu    sync.Mutex
+	users map[uuid.UUID]*model.User
+}
+
+func (r *memoryUsers) CreateUser(ctx context.Context, user *model.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *user
+	r.users[user.ID] = &stored
+	return nil
+}
+
+func (r *memoryUsers) GetUserByUsername(ctx context.Context, username string) (*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if u.Username == username {
+			found := *u
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryUsers) GetUserByID(ctx context.Context, id uuid.UUID) (*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if u, ok := r.users[id]; ok {
+		found := *u
+		return &found, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryUsers) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if u.Email != nil && *u.Email == email {
+			found := *u
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryUsers) UpdateEmail(ctx context.Context, userID uuid.UUID, email string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u := r.users[userID]
+	u.Email, u.EmailVerifiedAt = &email, nil
+	if email == "" {
+		u.Email = nil
+	}
+	return nil
+}
+
+func (r *memoryUsers) MarkEmailVerified(ctx context.Context, userID uuid.UUID, email string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u := r.users[userID]
+	if u == nil || u.Email == nil || *u.Email != email {
+		return false, nil
+	}
+	now := time.Now()
+	u.EmailVerifiedAt = &now
+	return true, nil
+}
+
+func (r *memoryUsers) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[userID].PasswordHash = passwordHash
+	return nil
+}
+
+type memoryTokens struct {
+	mu     sync.Mutex
+	tokens []*model.UserToken
+}
+
+func (r *memoryTokens) Create(ctx context.Context, token *model.UserToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *memoryTokens) Consume(ctx context.Context, purpose, tokenHash string, now time.Time) (*model.UserToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.Purpose == purpose && t.TokenHash == tokenHash && t.UsedAt == nil && now.Before(t.ExpiresAt) {
+			t.UsedAt = &now
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryTokens) InvalidateForUser(ctx context.Context, userID uuid.UUID, purpose string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.Purpose == purpose && t.UsedAt == nil {
+			t.UsedAt = &now
+		}
+	}
+	return nil
+}
+
+// memorySessions counts sign-outs; nothing else is used by the account flows
+type memorySessions struct {
+	repository.SessionRepository
+	revoked int
+}
+
+func (r *memorySessions) RevokeAllForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	r.revoked++
+	return 1, nil
+}
+
+type accountFixture struct {
+	service  *AccountService
+	users    *memoryUsers
+	sessions *memorySessions
+	mail     *mailer.MemoryMailer
+	user     *model.User
+}
+
+func newAccountFixture(t *testing.T) *accountFixture {
+	t.Helper()
+	policy, err := NewPasswordPolicy(8, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &model.User{ID: uuid.New(), Username: "ada"}
+	f := &accountFixture{
+		users:    &memoryUsers{users: map[uuid.UUID]*model.User{user.ID: user}},
+		sessions: &memorySessions{},
+		mail:     mailer.NewMemoryMailer(false),
+		user:     user,
+	}
+	f.service = NewAccountService(f.users, &memoryTokens{}, NewSessionService(f.sessions, time.Hour), policy, f.mail, testBaseURL+"/")
+	return f
+}
+
+// linkToken returns the token of the path link in the last email to the address
+func (f *accountFixture) linkToken(t *testing.T, to, path string) string {
+	t.Helper()
+	msg, ok := f.mail.Last(to)
+	if !ok {
+		t.Fatalf("no email sent to %s", to)
+	}
+	for _, line := range strings.Split(msg.TextBody, "\n") {
+		if !strings.HasPrefix(line, testBaseURL+path+"?") {
+			continue
+		}
+		link, err := url.Parse(line)
+		if err != nil {
+			t.Fatalf("parse link %q: %v", line, err)
+		}
+		return link.Query().Get("token")
+	}
+	t.Fatalf("email to %s has no %s link:\n%s", to, path, msg.TextBody)
+	return ""
+}
+
+func TestSetEmailSendsVerificationLink(t *testing.T) {
+	ctx := context.Background()
+	f := newAccountFixture(t)
+
+	if err := f.service.SetEmail(ctx, f.user.ID, " Ada@Example.com "); err != nil {
+		t.Fatalf("SetEmail: %v", err)
+	}
+
+	msg, _ := f.mail.Last("ada@example.com")
+	if msg.Subject != "Verify your JobPing email address" {
+		t.Errorf("subject = %q", msg.Subject)
+	}
+	token := f.linkToken(t, "ada@example.com", "/verify-email")
+
+	if err := f.service.VerifyEmail(ctx, token); err != nil {
+		t.Fatalf("VerifyEmail: %v", err)
+	}
+	if user, _ := f.users.GetUserByID(ctx, f.user.ID); user.EmailVerifiedAt == nil {
+		t.Error("email not marked verified")
+	}
+	if err := f.service.VerifyEmail(ctx, token); !errors.Is(err, usererr.ErrInvalidToken) {
+		t.Errorf("reused token: err = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestResendVerificationInvalidatesEarlierLinks(t *testing.T) {
+	ctx := context.Background()
+	f := newAccountFixture(t)
+
+	if err := f.service.SetEmail(ctx, f.user.ID, "ada@example.com"); err != nil {
+		t.Fatalf("SetEmail: %v", err)
+	}
+	first := f.linkToken(t, "ada@example.com", "/verify-email")
+	if err := f.service.ResendVerification(ctx, f.user.ID); err != nil {
+		t.Fatalf("ResendVerification: %v", err)
+	}
+	second := f.linkToken(t, "ada@example.com", "/verify-email")
+
+	if got := len(f.mail.Messages()); got != 2 {
+		t.Errorf("sent %d emails, want 2", got)
+	}
+	if err := f.service.VerifyEmail(ctx, first); !errors.Is(err, usererr.ErrInvalidToken) {
+		t.Errorf("first link: err = %v, want ErrInvalidToken", err)
+	}
+	if err := f.service.VerifyEmail(ctx, second); err != nil {
+		t.Errorf("second link: %v", err)
+	}
+}
+
+func TestForgotPasswordOnlyMailsVerifiedAddresses(t *testing.T) {
+	ctx := context.Background()
+	f := newAccountFixture(t)
+
+	if err := f.service.SetEmail(ctx, f.user.ID, "ada@example.com"); err != nil {
+		t.Fatalf("SetEmail: %v", err)
+	}
+	f.mail.Reset()
+
+	for _, email := range []string{"ada@example.com", "nobody@example.com", "not an email"} {
+		if err := f.service.ForgotPassword(ctx, email); err != nil {
+			t.Errorf("ForgotPassword(%q): %v", email, err)
+		}
+	}
+	if got := len(f.mail.Messages()); got != 0 {
+		t.Errorf("sent %d emails for unverified or unknown addresses, want 0", got)
+	}
+}
+
+func TestResetPasswordWithEmailedLink(t *testing.T) {
+	ctx := context.Background()
+	f := newAccountFixture(t)
+
+	if err := f.service.SetEmail(ctx, f.user.ID, "ada@example.com"); err != nil {
+		t.Fatalf("SetEmail: %v", err)
+	}
+	if err := f.service.VerifyEmail(ctx, f.linkToken(t, "ada@example.com", "/verify-email")); err != nil {
+		t.Fatalf("VerifyEmail: %v", err)
+	}
+
+	if err := f.service.ForgotPassword(ctx, "ada@example.com"); err != nil {
+		t.Fatalf("ForgotPassword: %v", err)
+	}
+	msg, _ := f.mail.Last("ada@example.com")
+	if msg.Subject != "Reset your JobPing password" {
+		t.Errorf("subject = %q", msg.Subject)
+	}
+	token := f.linkToken(t, "ada@example.com", "/reset-password")
+
+	// A rejected password must not use up the link
+	if err := f.service.ResetPassword(ctx, token, "short"); err == nil {
+		t.Fatal("short password accepted")
+	}
+	if err := f.service.ResetPassword(ctx, token, "correct horse battery"); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+
+	user, _ := f.users.GetUserByID(ctx, f.user.ID)
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte("correct horse battery")) != nil {
+		t.Error("password not updated")
+	}
+	if f.sessions.revoked != 1 {
+		t.Errorf("sessions revoked %d times, want 1", f.sessions.revoked)
+	}
+	if err := f.service.ResetPassword(ctx, token, "another good password"); !errors.Is(err, usererr.ErrInvalidToken) {
+		t.Errorf("reused link: err = %v, want ErrInvalidToken", err)
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/google/uuid"
+)
+
+// AnalysisQueue sends jobs to the user-analysis stage for one saved search
+type AnalysisQueue interface {
+	// EnqueueUserAnalysis queues a job for the search; rescoreID, unless nil, is the run whose progress it counts towards
+	EnqueueUserAnalysis(ctx context.Context, jobID, userID, searchID, rescoreID uuid.UUID) error
+}
+
+type sqsAnalysisQueue struct {
+	client   *sqs.Client
+	queueURL string
+}
+
+// NewSQSAnalysisQueue returns an AnalysisQueue for the user-analysis queue URL, or nil
+// if the URL is empty
+func NewSQSAnalysisQueue(queueURL string) (AnalysisQueue, error) {
+	if queueURL == "" {
+		return nil, nil
+	}
+
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(context.Background(), awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	// Override endpoint for LocalStack
+	if endpointURL := os.Getenv("AWS_ENDPOINT_URL"); endpointURL != "" {
+		cfg.BaseEndpoint = aws.String(endpointURL)
+	}
+	return &sqsAnalysisQueue{client: sqs.NewFromConfig(cfg), queueURL: queueURL}, nil
+}
+
+func (q *sqsAnalysisQueue) EnqueueUserAnalysis(ctx context.Context, jobID, userID, searchID, rescoreID uuid.UUID) error {
+	message := map[string]string{
+		"job_id":          jobID.String(),
+		"user_id":         userID.String(),
+		"saved_search_id": searchID.String(),
+	}
+	if rescoreID != uuid.Nil {
+		message["rescore_id"] = rescoreID.String()
+	}
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	_, err = q.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:    aws.String(q.queueURL),
+		MessageBody: aws.String(string(body)),
+	})
+	return err
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/repository"
+	"github.com/jobping/backend/internal/features/user/usererr"
+)
+
+const (
+	// APITokenPrefix starts every personal API token, so they are easy to tell from JWTs and to scan for in leaks
+	APITokenPrefix = "jpt_"
+
+	DefaultAPITokenExpiry = 90 * 24 * time.Hour
+	maxAPITokenExpiry     = 365 * 24 * time.Hour
+	maxAPITokensPerUser   = 25
+	maxAPITokenNameLength = 100
+	// apiTokenDisplayLength is how much of the token is kept in clear for listings
+	apiTokenDisplayLength = len(APITokenPrefix) + 8
+)
+
+// APITokenService manages personal API tokens. The token itself is only returned once, on creation.
+type APITokenService struct {
+	tokenRepo repository.APITokenRepository
+	userRepo  repository.UserRepository
+}
+
+func NewAPITokenService(tokenRepo repository.APITokenRepository, userRepo repository.UserRepository) *APITokenService {
+	return &APITokenService{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// Create issues a token with the given scopes and returns it along with the raw token
+func (s *APITokenService) Create(ctx context.Context, userID uuid.UUID, name string, scopes []string, expiresIn time.Duration) (*model.APIToken, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxAPITokenNameLength {
+		return nil, "", usererr.ErrInvalidTokenName
+	}
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	if expiresIn < 24*time.Hour || expiresIn > maxAPITokenExpiry {
+		return nil, "", usererr.ErrInvalidExpiry
+	}
+
+	now := time.Now()
+	active, err := s.tokenRepo.CountActive(ctx, userID, now)
+	if err != nil {
+		return nil, "", err
+	}
+	if active >= maxAPITokensPerUser {
+		return nil, "", usererr.ErrTooManyAPITokens
+	}
+
+	secret, err := newToken()
+	if err != nil {
+		return nil, "", err
+	}
+	rawToken := APITokenPrefix + secret
+
+	token := &model.APIToken{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Name:        name,
+		TokenHash:   hashToken(rawToken),
+		TokenPrefix: rawToken[:apiTokenDisplayLength],
+		Scopes:      scopes,
+		ExpiresAt:   now.Add(expiresIn),
+		CreatedAt:   now,
+	}
+	if err := s.tokenRepo.Create(ctx, token); err != nil {
+		return nil, "", err
+	}
+	return token, rawToken, nil
+}
+
+func (s *APITokenService) List(ctx context.Context, userID uuid.UUID) ([]model.APIToken, error) {
+	return s.tokenRepo.GetByUserID(ctx, userID)
+}
+
+func (s *APITokenService) Revoke(ctx context.Context, userID, tokenID uuid.UUID) error {
+	revoked, err := s.tokenRepo.Revoke(ctx, userID, tokenID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return usererr.ErrAPITokenNotFound
+	}
+	return nil
+}
+
+// Authenticate resolves a raw API token to the token and its user. It returns usererr.ErrInvalidToken
+// for unknown, expired or revoked tokens and usererr.ErrAccountDisabled for disabled users.
+func (s *APITokenService) Authenticate(ctx context.Context, rawToken string) (*model.APIToken, *model.User, error) {
+	if !strings.HasPrefix(rawToken, APITokenPrefix) {
+		return nil, nil, usererr.ErrInvalidToken
+	}
+
+	token, err := s.tokenRepo.GetByTokenHash(ctx, hashToken(rawToken))
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	if token == nil || token.RevokedAt != nil || !token.ExpiresAt.After(now) {
+		return nil, nil, usererr.ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, usererr.ErrInvalidToken
+	}
+	if user.DisabledAt != nil {
+		return nil, nil, usererr.ErrAccountDisabled
+	}
+
+	if err := s.tokenRepo.TouchLastUsed(ctx, token.ID, now); err != nil {
+		log.Printf("Failed to record API token use: %v", err)
+	}
+	return token, user, nil
+}
+
+// normalizeScopes validates scopes and removes duplicates, keeping the order given
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, usererr.ErrInvalidScope
+	}
+	seen := make(map[string]bool, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !model.ValidScope(scope) {
+			return nil, usererr.ErrInvalidScope
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized, nil
+}
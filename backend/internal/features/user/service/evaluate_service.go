@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	jobmodel "github.com/jobping/backend/internal/features/job/model"
+	"github.com/jobping/backend/internal/features/job/posting"
+	jobrepo "github.com/jobping/backend/internal/features/job/repository"
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/repository"
+	"github.com/jobping/backend/internal/features/user/usererr"
+	useranalysissvc "github.com/jobping/backend/internal/features/user_analysis/service"
+)
+
+const (
+	evaluationWindow = time.Hour
+	// companyInfoMaxAge matches the job-analysis stage: newer research is reused
+	companyInfoMaxAge = 6 * 30 * 24 * time.Hour
+)
+
+// CompanyResearcher researches a job's company. The job-analysis AI client implements it.
+type CompanyResearcher interface {
+	ResearchCompany(ctx context.Context, company, title, description string) (map[string]interface{}, error)
+}
+
+// JobMatcher scores a job against a saved search. The user-analysis AI client implements it.
+type JobMatcher interface {
+	MatchJobToUser(ctx context.Context, job *useranalysissvc.JobMatchInput, user *useranalysissvc.UserMatchInput) (*useranalysissvc.UserMatchResult, error)
+}
+
+// EvaluationLimits configures EvaluateService. A zero MaxPerHour disables the rate limit.
+type EvaluationLimits struct {
+	MaxPerHour int
+	// Timeout bounds fetching, company research and matching together
+	Timeout time.Duration
+}
+
+// EvaluateInput is a job to evaluate: a URL, pasted text, or both. Pasted fields win over what the
+// page says. SavedSearchID picks the search to score against; nil means the default search.
+type EvaluateInput struct {
+	JobURL        string
+	Title         string
+	Company       string
+	Location      string
+	Description   string
+	SavedSearchID *uuid.UUID
+}
+
+// EvaluateService scores a job the scraper did not find against one of the user's saved searches,
+// synchronously. New jobs are stored but are not fanned out to other users' searches until the
+// scraper finds them too and promotes them.
+type EvaluateService struct {
+	jobRepo     jobrepo.JobRepository
+	events      jobrepo.PipelineEventRepository
+	searchRepo  repository.SavedSearchRepository
+	matchRepo   repository.UserJobMatchRepository
+	resumeRepo  repository.ResumeRepository
+	evaluations repository.EvaluationRepository
+	fetcher     posting.Fetcher
+	researcher  CompanyResearcher
+	matcher     JobMatcher
+	limits      EvaluationLimits
+}
+
+func NewEvaluateService(
+	jobRepo jobrepo.JobRepository,
+	events jobrepo.PipelineEventRepository,
+	searchRepo repository.SavedSearchRepository,
+	matchRepo repository.UserJobMatchRepository,
+	resumeRepo repository.ResumeRepository,
+	evaluations repository.EvaluationRepository,
+	fetcher posting.Fetcher,
+	researcher CompanyResearcher,
+	matcher JobMatcher,
+	limits EvaluationLimits,
+) *EvaluateService {
+	return &EvaluateService{
+		jobRepo:     jobRepo,
+		events:      events,
+		searchRepo:  searchRepo,
+		matchRepo:   matchRepo,
+		resumeRepo:  resumeRepo,
+		evaluations: evaluations,
+		fetcher:     fetcher,
+		researcher:  researcher,
+		matcher:     matcher,
+		limits:      limits,
+	}
+}
+
+// Evaluate ingests the job if it is new, researches its company unless recent research exists,
+// and scores it against the saved search. The score is stored as the search's match, marked
+// notified: the caller has just been shown it.
+func (s *EvaluateService) Evaluate(ctx context.Context, userID uuid.UUID, input EvaluateInput) (*model.JobEvaluation, error) {
+	input.JobURL = strings.TrimSpace(input.JobURL)
+	input.Title = strings.TrimSpace(input.Title)
+	input.Company = strings.TrimSpace(input.Company)
+	input.Location = strings.TrimSpace(input.Location)
+	input.Description = posting.Truncate(strings.TrimSpace(input.Description))
+	if input.JobURL == "" && (input.Title == "" || input.Description == "") {
+		return nil, usererr.ErrEvaluationInputMissing
+	}
+	if input.JobURL != "" {
+		u, err := posting.ParseURL(input.JobURL)
+		if err != nil {
+			return nil, usererr.ErrInvalidJobURL
+		}
+		input.JobURL = u.String()
+	}
+
+	search, err := s.savedSearch(ctx, userID, input.SavedSearchID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.allow(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	evalCtx, cancel := context.WithTimeout(ctx, s.limits.Timeout)
+	defer cancel()
+
+	job, created, err := s.ingest(ctx, evalCtx, input)
+	if err != nil {
+		return nil, err
+	}
+	research := s.researchCompany(ctx, evalCtx, job)
+
+	matchInput := &useranalysissvc.JobMatchInput{
+		Title:       job.Title,
+		Company:     job.Company,
+		Description: job.Description,
+		CompanyInfo: job.CompanyInfo,
+	}
+	userInput := &useranalysissvc.UserMatchInput{Prompt: search.AIPrompt}
+	profile, err := s.resumeRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if profile != nil {
+		userInput.Skills = profile.Skills
+		userInput.Seniority = profile.Seniority
+	}
+
+	result, err := s.matcher.MatchJobToUser(evalCtx, matchInput, userInput)
+	if err != nil {
+		log.Printf("Failed to evaluate job %s for user %s: %v", job.ID, userID, err)
+		event := evaluationEvent(job.ID, userID, search.ID, jobmodel.PipelineOutcomeFailed, nil)
+		event.Error = err.Error()
+		s.events.RecordBestEffort(ctx, event)
+		if errors.Is(evalCtx.Err(), context.DeadlineExceeded) {
+			return nil, usererr.ErrEvaluationTimeout
+		}
+		return nil, usererr.ErrEvaluationFailed
+	}
+
+	now := time.Now()
+	match := &model.UserJobMatch{
+		ID:               uuid.New(),
+		UserID:           userID,
+		SavedSearchID:    search.ID,
+		JobID:            job.ID,
+		Score:            result.Score,
+		Analysis:         result.Analysis,
+		Notified:         true,
+		PromptRevisionID: search.PromptRevisionID,
+		CreatedAt:        now,
+	}
+	if err := s.matchRepo.Create(ctx, match); err != nil {
+		return nil, err
+	}
+	// Create keeps the ID of a match the search already had for the job
+	if stored, err := s.matchRepo.GetBySearchAndJob(ctx, search.ID, job.ID); err == nil && stored != nil {
+		match.ID = stored.ID
+	}
+	s.events.RecordBestEffort(ctx, evaluationEvent(job.ID, userID, search.ID, jobmodel.PipelineOutcomeAnalyzed, map[string]interface{}{
+		"score":     result.Score,
+		"threshold": search.NotifyThreshold,
+		"evaluated": true,
+		"notify":    false,
+	}))
+	log.Printf("Evaluated job %s for user %s (search %q) with score %d", job.Title, userID, search.Name, result.Score)
+
+	return &model.JobEvaluation{
+		JobID:           job.ID,
+		JobURL:          job.JobURL,
+		Title:           job.Title,
+		Company:         job.Company,
+		Location:        job.Location,
+		Created:         created,
+		SavedSearchID:   search.ID,
+		SearchName:      search.Name,
+		MatchID:         match.ID,
+		Score:           result.Score,
+		Threshold:       search.NotifyThreshold,
+		Explanation:     result.Explanation,
+		Pros:            result.Pros,
+		Cons:            result.Cons,
+		CompanyResearch: research,
+		CompanyInfo:     job.CompanyInfo,
+		EvaluatedAt:     now,
+	}, nil
+}
+
+// savedSearch returns the search to score against, which must belong to the user and have a prompt
+func (s *EvaluateService) savedSearch(ctx context.Context, userID uuid.UUID, searchID *uuid.UUID) (*model.SavedSearch, error) {
+	var search *model.SavedSearch
+	var err error
+	if searchID == nil {
+		search, err = s.searchRepo.GetDefault(ctx, userID)
+	} else {
+		search, err = s.searchRepo.GetByID(ctx, *searchID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if search == nil || search.UserID != userID {
+		return nil, usererr.ErrSavedSearchNotFound
+	}
+	if search.AIPrompt == "" {
+		return nil, usererr.ErrInvalidSearchPrompt
+	}
+	return search, nil
+}
+
+// allow counts an evaluation, or returns a *usererr.LockedError once the user has used up the
+// hour's evaluations. Failed evaluations count too: fetching and research cost the same.
+func (s *EvaluateService) allow(ctx context.Context, userID uuid.UUID) error {
+	now := time.Now()
+	stats, recorded, err := s.evaluations.RecordWithinLimit(ctx, userID, now, now.Add(-evaluationWindow), s.limits.MaxPerHour)
+	if err != nil {
+		return err
+	}
+	if !recorded {
+		// The window slides: the oldest evaluation leaving it frees a slot
+		return &usererr.LockedError{RetryAfter: stats.First.Add(evaluationWindow).Sub(now)}
+	}
+	if err := s.evaluations.DeleteBefore(ctx, now.Add(-evaluationWindow)); err != nil {
+		log.Printf("Failed to prune job evaluations: %v", err)
+	}
+	return nil
+}
+
+// ingest returns the job with the input's URL, creating it if it is new. Pasted jobs are keyed
+// by a hash of their text. The page is fetched, with evalCtx, only when the text was not pasted.
+func (s *EvaluateService) ingest(ctx, evalCtx context.Context, input EvaluateInput) (*jobmodel.Job, bool, error) {
+	jobURL := input.JobURL
+	if jobURL == "" {
+		jobURL = pastedJobURL(input)
+	}
+	existing, err := s.jobRepo.GetByURL(ctx, jobURL)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		return existing, false, nil
+	}
+
+	if input.Title == "" || input.Description == "" {
+		page, err := s.fetcher.Fetch(evalCtx, jobURL)
+		if err != nil {
+			log.Printf("Failed to read job page %s: %v", jobURL, err)
+			if errors.Is(evalCtx.Err(), context.DeadlineExceeded) {
+				return nil, false, usererr.ErrEvaluationTimeout
+			}
+			return nil, false, usererr.ErrJobPageUnreadable
+		}
+		input.Title = firstNonEmpty(input.Title, page.Title)
+		input.Company = firstNonEmpty(input.Company, page.Company)
+		input.Location = firstNonEmpty(input.Location, page.Location)
+		input.Description = firstNonEmpty(input.Description, page.Description)
+		if page.IsRemote && input.Location == "" {
+			input.Location = "Remote"
+		}
+	}
+
+	now := time.Now()
+	job := &jobmodel.Job{
+		ID:          uuid.New(),
+		Title:       input.Title,
+		Company:     input.Company,
+		Location:    input.Location,
+		JobURL:      jobURL,
+		Source:      jobmodel.JobSourceUser,
+		Description: input.Description,
+		IsRemote:    strings.Contains(strings.ToLower(input.Location), "remote"),
+		PostedAt:    now,
+		Status:      jobmodel.JobStatusPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	job.Place = job.ParsePlace()
+	job.LocationParsedAt = &now
+
+	if err := s.jobRepo.Create(ctx, job); err != nil {
+		// Someone submitted the same job at the same moment
+		if existing, getErr := s.jobRepo.GetByURL(ctx, jobURL); getErr == nil && existing != nil {
+			return existing, false, nil
+		}
+		return nil, false, err
+	}
+	s.events.RecordBestEffort(ctx, jobmodel.PipelineEvent{
+		JobID:   job.ID,
+		Stage:   jobmodel.PipelineStageIngested,
+		Outcome: jobmodel.PipelineOutcomeCreated,
+		Details: map[string]interface{}{"source": job.Source},
+	})
+	return job, true, nil
+}
+
+// researchCompany fills in the job's company info unless it is recent, and returns the outcome.
+// Failures are recorded and the job is scored without company info.
+func (s *EvaluateService) researchCompany(ctx, evalCtx context.Context, job *jobmodel.Job) string {
+	if job.CompanyInfo != nil && job.CompanyInfoUpdatedAt != nil && time.Since(*job.CompanyInfoUpdatedAt) < companyInfoMaxAge {
+		return jobmodel.PipelineOutcomeSkippedFresh
+	}
+
+	event := jobmodel.PipelineEvent{
+		JobID:   job.ID,
+		Stage:   jobmodel.PipelineStageCompanyResearch,
+		Outcome: jobmodel.PipelineOutcomeResearched,
+	}
+	// Leave at least half the time for matching
+	researchCtx, cancel := context.WithTimeout(evalCtx, s.limits.Timeout/2)
+	defer cancel()
+	companyInfo, err := s.researcher.ResearchCompany(researchCtx, job.Company, job.Title, job.Description)
+	if err == nil {
+		err = s.jobRepo.UpdateCompanyInfo(ctx, job.ID, companyInfo)
+	}
+	if err != nil {
+		log.Printf("Company research failed for job %s: %v", job.ID, err)
+		event.Outcome, event.Error = jobmodel.PipelineOutcomeFailed, err.Error()
+	} else {
+		job.CompanyInfo = companyInfo
+	}
+	s.events.RecordBestEffort(ctx, event)
+	return event.Outcome
+}
+
+// evaluationEvent builds the user-analysis event of an evaluation
+func evaluationEvent(jobID, userID, searchID uuid.UUID, outcome string, details map[string]interface{}) jobmodel.PipelineEvent {
+	return jobmodel.PipelineEvent{
+		JobID:         jobID,
+		UserID:        &userID,
+		SavedSearchID: &searchID,
+		Stage:         jobmodel.PipelineStageUserAnalysis,
+		Outcome:       outcome,
+		Details:       details,
+	}
+}
+
+// pastedJobURL is the key of a pasted job: the same text pasted twice is the same job
+func pastedJobURL(input EvaluateInput) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(input.Title + "\n" + input.Company + "\n" + input.Description)))
+	return jobmodel.PastedURLPrefix + hex.EncodeToString(sum[:16])
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
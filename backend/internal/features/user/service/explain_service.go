@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/job/joberr"
+	jobmodel "github.com/jobping/backend/internal/features/job/model"
+	jobrepo "github.com/jobping/backend/internal/features/job/repository"
+	notificationsvc "github.com/jobping/backend/internal/features/notification/service"
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/repository"
+)
+
+// ExplainService answers "why wasn't I notified about this job" from the job, the user's saved
+// searches and matches, and the job's pipeline events. It never calls the AI.
+type ExplainService struct {
+	jobRepo    jobrepo.JobRepository
+	events     jobrepo.PipelineEventRepository
+	searchRepo repository.SavedSearchRepository
+	matchRepo  repository.UserJobMatchRepository
+}
+
+func NewExplainService(
+	jobRepo jobrepo.JobRepository,
+	events jobrepo.PipelineEventRepository,
+	searchRepo repository.SavedSearchRepository,
+	matchRepo repository.UserJobMatchRepository,
+) *ExplainService {
+	return &ExplainService{
+		jobRepo:    jobRepo,
+		events:     events,
+		searchRepo: searchRepo,
+		matchRepo:  matchRepo,
+	}
+}
+
+// jobEvents holds the latest event of each stage. Per-user stages are keyed by saved search.
+type jobEvents struct {
+	research, fanout       *jobmodel.PipelineEvent
+	analysis, notification map[uuid.UUID]*jobmodel.PipelineEvent
+}
+
+func groupEvents(events []jobmodel.PipelineEvent) jobEvents {
+	grouped := jobEvents{
+		analysis:     make(map[uuid.UUID]*jobmodel.PipelineEvent),
+		notification: make(map[uuid.UUID]*jobmodel.PipelineEvent),
+	}
+	for i := range events {
+		event := &events[i]
+		var searchID uuid.UUID
+		if event.SavedSearchID != nil {
+			searchID = *event.SavedSearchID
+		}
+		switch event.Stage {
+		case jobmodel.PipelineStageCompanyResearch:
+			grouped.research = event
+		case jobmodel.PipelineStageFanout:
+			grouped.fanout = event
+		case jobmodel.PipelineStageUserAnalysis:
+			grouped.analysis[searchID] = event
+		case jobmodel.PipelineStageNotification:
+			grouped.notification[searchID] = event
+		}
+	}
+	return grouped
+}
+
+// Explain walks the decision chain for the user and job: ingestion, company research, fanout,
+// and for each of the user's saved searches its filters, score against the threshold and delivery.
+// Filters are evaluated as they are now, which may differ from when the job was fanned out.
+func (s *ExplainService) Explain(ctx context.Context, userID, jobID uuid.UUID) (*model.JobExplanation, error) {
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, joberr.ErrJobNotFound
+	}
+	events, err := s.events.GetForUser(ctx, jobID, userID)
+	if err != nil {
+		return nil, err
+	}
+	searches, err := s.searchRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	grouped := groupEvents(events)
+	steps := jobSteps(job, grouped)
+	fanoutFailed := steps[len(steps)-1].Status == model.StepFailed
+	explanation := &model.JobExplanation{
+		JobID:   job.ID,
+		Title:   job.Title,
+		Company: job.Company,
+		Steps:   steps,
+	}
+	for _, search := range searches {
+		match, err := s.matchRepo.GetBySearchAndJob(ctx, search.ID, jobID)
+		if err != nil {
+			return nil, err
+		}
+		searchExplanation := explainSearch(job, search, match, grouped, fanoutFailed)
+		if searchExplanation.Verdict == model.VerdictNotified {
+			explanation.Notified = true
+		}
+		explanation.Searches = append(explanation.Searches, searchExplanation)
+	}
+	return explanation, nil
+}
+
+// jobSteps explains the stages every user shares, ending with the fanout
+func jobSteps(job *jobmodel.Job, events jobEvents) []model.ExplanationStep {
+	ingested := model.ExplanationStep{Step: "ingested", Status: model.StepPassed, At: &job.CreatedAt}
+	if job.Source != "" {
+		ingested.Detail = fmt.Sprintf("Scraped from %s", job.Source)
+	}
+
+	research := model.ExplanationStep{Step: "company_research"}
+	switch event := events.research; {
+	case event == nil:
+		research.Status, research.Detail = model.StepPending, "Job analysis has not run yet"
+	case event.Outcome == jobmodel.PipelineOutcomeFailed:
+		research.Status, research.Detail, research.At = model.StepFailed, event.Error, &event.CreatedAt
+	case event.Outcome == jobmodel.PipelineOutcomeSkippedFresh:
+		research.Status, research.Detail, research.At = model.StepPassed, "Reused company research less than 6 months old", &event.CreatedAt
+	default:
+		research.Status, research.Detail, research.At = model.StepPassed, "Researched the company", &event.CreatedAt
+	}
+
+	fanout := model.ExplanationStep{Step: "fanout"}
+	switch event := events.fanout; {
+	case event != nil && event.Outcome == jobmodel.PipelineOutcomeFannedOut:
+		fanout.Status, fanout.At = model.StepPassed, &event.CreatedAt
+		fanout.Detail = fmt.Sprintf("Sent to %v of %v active saved searches", event.Details["enqueued"], event.Details["searches"])
+	case event != nil && event.Outcome == jobmodel.PipelineOutcomeSkipped:
+		fanout.Status, fanout.At = model.StepFailed, &event.CreatedAt
+		fanout.Detail = fmt.Sprintf("Not fanned out: the job was %s", job.Lifecycle)
+	case event != nil:
+		fanout.Status, fanout.Detail, fanout.At = model.StepFailed, event.Error, &event.CreatedAt
+	case job.Lifecycle != jobmodel.JobLifecycleActive:
+		fanout.Status = model.StepFailed
+		fanout.Detail = fmt.Sprintf("The job is %s; only active jobs are fanned out", job.Lifecycle)
+	default:
+		fanout.Status, fanout.Detail = model.StepPending, "Waiting for job analysis to finish"
+	}
+	return []model.ExplanationStep{ingested, research, fanout}
+}
+
+// explainSearch walks one saved search's chain. Steps after the deciding one are skipped.
+func explainSearch(job *jobmodel.Job, search model.SavedSearch, match *model.UserJobMatch, events jobEvents, fanoutFailed bool) model.SearchExplanation {
+	chain := searchChain{explanation: model.SearchExplanation{SavedSearchID: search.ID, Name: search.Name}}
+	fannedOut := events.fanout != nil && events.fanout.Outcome == jobmodel.PipelineOutcomeFannedOut
+
+	// A match means the search took part, whatever else changed since
+	if match == nil {
+		switch {
+		case !search.Active || search.AIPrompt == "":
+			chain.fail("search", "The search is paused or has no prompt")
+		case fannedOut && search.CreatedAt.After(events.fanout.CreatedAt):
+			chain.fail("search", "The search was created after the job was fanned out; start a rescore to score it")
+		default:
+			chain.pass("search", "The search is active", nil)
+		}
+
+		if failed := search.Filters.FailedFilter(job.Title, job.Company, job.Location, job.Description, job.IsRemote, job.Place.Coords); failed != "" {
+			chain.fail("filters", fmt.Sprintf("The job does not pass the search's %q filter", failed))
+		} else {
+			chain.pass("filters", "The job passes the search's filters", nil)
+		}
+	} else {
+		chain.pass("search", "The search took part", nil)
+		chain.pass("filters", "The job passed the search's filters", nil)
+	}
+
+	analysis := events.analysis[search.ID]
+	switch {
+	case match != nil:
+		chain.pass("analysis", fmt.Sprintf("Scored %d", match.Score), &match.CreatedAt)
+	case analysis != nil && analysis.Outcome == jobmodel.PipelineOutcomeFailed:
+		chain.failAt("analysis", "Scoring failed: "+analysis.Error, &analysis.CreatedAt)
+	case analysis != nil && analysis.Outcome == jobmodel.PipelineOutcomeSkipped:
+		chain.failAt("analysis", fmt.Sprintf("Not scored: %v", analysis.Details["reason"]), &analysis.CreatedAt)
+	case fanoutFailed:
+		chain.fail("analysis", "The job was not fanned out")
+	default:
+		chain.wait("analysis", "Waiting to be scored")
+	}
+
+	if match != nil {
+		if match.Score >= search.NotifyThreshold {
+			chain.pass("threshold", fmt.Sprintf("Score %d is at or above the threshold of %d", match.Score, search.NotifyThreshold), nil)
+		} else {
+			chain.fail("threshold", fmt.Sprintf("Score %d is below the threshold of %d", match.Score, search.NotifyThreshold))
+		}
+	} else {
+		chain.wait("threshold", fmt.Sprintf("The threshold is %d", search.NotifyThreshold))
+	}
+
+	notification := events.notification[search.ID]
+	switch {
+	case match != nil && match.Notified:
+		var at *time.Time
+		if notification != nil && notification.Outcome == jobmodel.PipelineOutcomeSent {
+			at = &notification.CreatedAt
+		}
+		chain.pass("notification", "Notified", at)
+	case match != nil && match.DeferredAt != nil:
+		chain.hold("notification", deferDetail(match.DeferredReason), match.DeferredAt)
+	case notification != nil && notification.Outcome == jobmodel.PipelineOutcomeFailed:
+		chain.failAt("notification", "Delivery failed: "+notification.Error, &notification.CreatedAt)
+	default:
+		chain.wait("notification", "Waiting to be delivered")
+	}
+
+	return chain.explanation
+}
+
+func deferDetail(reason *string) string {
+	if reason == nil {
+		return "Held back for the next digest"
+	}
+	switch *reason {
+	case notificationsvc.DeferReasonQuietHours:
+		return "Held back by your quiet hours; it will be in the next digest"
+	case notificationsvc.DeferReasonRateLimited:
+		return "Held back by your hourly notification limit; it will be in the next digest"
+	}
+	return "Held back for the next digest: " + *reason
+}
+
+// searchChain appends steps and sets the verdict from the first step that is not passed.
+// Once decided, later steps are recorded as skipped.
+type searchChain struct {
+	explanation model.SearchExplanation
+}
+
+func (c *searchChain) add(step, status, detail string, at *time.Time, verdict string) {
+	if c.explanation.Verdict != "" {
+		status, detail, at = model.StepSkipped, "", nil
+	} else if verdict != "" {
+		c.explanation.Verdict, c.explanation.Reason = verdict, detail
+	}
+	c.explanation.Steps = append(c.explanation.Steps, model.ExplanationStep{Step: step, Status: status, Detail: detail, At: at})
+}
+
+func (c *searchChain) pass(step, detail string, at *time.Time) {
+	verdict := ""
+	if step == "notification" {
+		verdict = model.VerdictNotified
+	}
+	c.add(step, model.StepPassed, detail, at, verdict)
+}
+
+func (c *searchChain) fail(step, detail string) {
+	c.failAt(step, detail, nil)
+}
+
+func (c *searchChain) failAt(step, detail string, at *time.Time) {
+	c.add(step, model.StepFailed, detail, at, model.VerdictNotNotified)
+}
+
+func (c *searchChain) wait(step, detail string) {
+	c.add(step, model.StepPending, detail, nil, model.VerdictPending)
+}
+
+func (c *searchChain) hold(step, detail string, at *time.Time) {
+	c.add(step, model.StepPending, detail, at, model.VerdictDeferred)
+}
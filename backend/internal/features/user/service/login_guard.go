@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/audit"
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/repository"
+	"github.com/jobping/backend/internal/features/user/usererr"
+)
+
+// LoginLimits configures LoginGuard. A zero maximum disables that limit.
+type LoginLimits struct {
+	MaxFailuresPerUser int
+	MaxFailuresPerIP   int
+	RegisterPerIP      int
+	Window             time.Duration
+	// Lockout is the first lockout once a limit is reached; each further failure doubles it, up to Window
+	Lockout time.Duration
+}
+
+// LoginGuard rate limits logins and registrations with sliding windows over recent attempts.
+//
+// Failed logins are counted per username and per IP. Once either count reaches its limit, further
+// logins for that key are refused until the lockout after the latest failure has passed. A failure
+// after a lockout raises the count and doubles the next lockout. A successful login clears the
+// username's failures; the IP's failures age out of the window.
+type LoginGuard struct {
+	attempts repository.AttemptRepository
+	audit    audit.Recorder
+	limits   LoginLimits
+}
+
+func NewLoginGuard(attempts repository.AttemptRepository, recorder audit.Recorder, limits LoginLimits) *LoginGuard {
+	return &LoginGuard{
+		attempts: attempts,
+		audit:    recorder,
+		limits:   limits,
+	}
+}
+
+// CheckLogin returns a *usererr.LockedError while the username or IP is locked out
+func (g *LoginGuard) CheckLogin(ctx context.Context, username string, client ClientInfo) error {
+	now := time.Now()
+	since := now.Add(-g.limits.Window)
+	username = throttleKey(username)
+
+	byUser, err := g.attempts.StatsByUsername(ctx, model.AttemptKindLogin, username, since)
+	if err != nil {
+		return err
+	}
+	byIP, err := g.attempts.StatsByIP(ctx, model.AttemptKindLogin, client.IPAddress, since)
+	if err != nil {
+		return err
+	}
+
+	wait := g.lockedFor(byUser, g.limits.MaxFailuresPerUser, now)
+	if ipWait := g.lockedFor(byIP, g.limits.MaxFailuresPerIP, now); ipWait > wait {
+		wait = ipWait
+	}
+	if wait <= 0 {
+		return nil
+	}
+
+	g.record(ctx, &audit.Entry{
+		Action:    audit.ActionLoginLocked,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+		Metadata: map[string]interface{}{
+			"username":            username,
+			"username_fails":      byUser.Count,
+			"ip_fails":            byIP.Count,
+			"retry_after_seconds": int(math.Ceil(wait.Seconds())),
+		},
+	})
+	return &usererr.LockedError{RetryAfter: wait}
+}
+
+// LoginFailed counts a failed login and writes an audit entry. userID is nil for unknown usernames.
+func (g *LoginGuard) LoginFailed(ctx context.Context, username string, userID *uuid.UUID, reason string, client ClientInfo) {
+	now := time.Now()
+	username = throttleKey(username)
+
+	if err := g.attempts.Record(ctx, model.AttemptKindLogin, username, client.IPAddress, now); err != nil {
+		log.Printf("Failed to record login attempt: %v", err)
+	}
+
+	entry := &audit.Entry{
+		Action:    audit.ActionLoginFailed,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+		Metadata:  map[string]interface{}{"username": username, "reason": reason},
+	}
+	if userID != nil {
+		entry.TargetType = "user"
+		entry.TargetID = userID.String()
+	}
+	g.record(ctx, entry)
+}
+
+// LoginSucceeded clears the username's failure count
+func (g *LoginGuard) LoginSucceeded(ctx context.Context, username string) error {
+	return g.attempts.ClearUsername(ctx, model.AttemptKindLogin, throttleKey(username))
+}
+
+// AllowRegister counts a registration attempt from the client's IP, or returns a
+// *usererr.LockedError when the IP has used up its registrations for the window
+func (g *LoginGuard) AllowRegister(ctx context.Context, username string, client ClientInfo) error {
+	now := time.Now()
+
+	if g.limits.RegisterPerIP > 0 {
+		stats, err := g.attempts.StatsByIP(ctx, model.AttemptKindRegister, client.IPAddress, now.Add(-g.limits.Window))
+		if err != nil {
+			return err
+		}
+		if stats.Count >= g.limits.RegisterPerIP {
+			// The window slides: the oldest attempt leaving it frees a slot
+			wait := stats.First.Add(g.limits.Window).Sub(now)
+			g.record(ctx, &audit.Entry{
+				Action:    audit.ActionRegisterLimited,
+				IPAddress: client.IPAddress,
+				UserAgent: client.UserAgent,
+				Metadata:  map[string]interface{}{"username": username, "attempts": stats.Count},
+			})
+			return &usererr.LockedError{RetryAfter: wait}
+		}
+	}
+
+	return g.attempts.Record(ctx, model.AttemptKindRegister, throttleKey(username), client.IPAddress, now)
+}
+
+// Prune deletes attempts that have left the window. It runs on a schedule rather than on every
+// attempt, since it scans the whole table.
+func (g *LoginGuard) Prune(ctx context.Context) error {
+	return g.attempts.DeleteBefore(ctx, time.Now().Add(-g.limits.Window))
+}
+
+// RunPrunes calls Prune every interval until ctx is done. The local server uses it;
+// in AWS the scheduled account purge event prunes too.
+func (g *LoginGuard) RunPrunes(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := g.Prune(ctx); err != nil {
+			log.Printf("Failed to prune login attempts: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// lockedFor returns how much longer a key with these failures is locked out
+func (g *LoginGuard) lockedFor(stats model.AttemptStats, limit int, now time.Time) time.Duration {
+	if limit <= 0 || stats.Count < limit {
+		return 0
+	}
+
+	lockout := g.limits.Lockout
+	for i := limit; i < stats.Count && lockout < g.limits.Window; i++ {
+		lockout *= 2
+	}
+	if lockout > g.limits.Window {
+		lockout = g.limits.Window
+	}
+	return stats.Last.Add(lockout).Sub(now)
+}
+
+func (g *LoginGuard) record(ctx context.Context, entry *audit.Entry) {
+	if err := g.audit.Record(ctx, entry); err != nil {
+		log.Printf("Failed to write audit entry %s: %v", entry.Action, err)
+	}
+}
+
+// throttleKey normalizes usernames so case variations share one counter
+func throttleKey(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jobping/backend/internal/audit"
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/repository"
+	"github.com/jobping/backend/internal/features/user/usererr"
+)
+
+// memoryAttempts keeps attempts in a slice
+type memoryAttempts struct {
+	repository.AttemptRepository
+	attempts []attempt
+}
+
+type attempt struct {
+	kind, username, ip string
+	at                 time.Time
+}
+
+func (r *memoryAttempts) Record(ctx context.Context, kind, username, ipAddress string, at time.Time) error {
+	r.attempts = append(r.attempts, attempt{kind, username, ipAddress, at})
+	return nil
+}
+
+func (r *memoryAttempts) stats(since time.Time, match func(attempt) bool) model.AttemptStats {
+	var stats model.AttemptStats
+	for _, a := range r.attempts {
+		if !a.at.After(since) || !match(a) {
+			continue
+		}
+		if stats.Count == 0 || a.at.Before(stats.First) {
+			stats.First = a.at
+		}
+		if a.at.After(stats.Last) {
+			stats.Last = a.at
+		}
+		stats.Count++
+	}
+	return stats
+}
+
+func (r *memoryAttempts) StatsByUsername(ctx context.Context, kind, username string, since time.Time) (model.AttemptStats, error) {
+	return r.stats(since, func(a attempt) bool { return a.kind == kind && a.username == username }), nil
+}
+
+func (r *memoryAttempts) StatsByIP(ctx context.Context, kind, ipAddress string, since time.Time) (model.AttemptStats, error) {
+	return r.stats(since, func(a attempt) bool { return a.kind == kind && a.ip == ipAddress }), nil
+}
+
+func (r *memoryAttempts) ClearUsername(ctx context.Context, kind, username string) error {
+	kept := r.attempts[:0]
+	for _, a := range r.attempts {
+		if a.kind != kind || a.username != username {
+			kept = append(kept, a)
+		}
+	}
+	r.attempts = kept
+	return nil
+}
+
+// memoryAudit collects audit entries
+type memoryAudit struct {
+	entries []*audit.Entry
+}
+
+func (r *memoryAudit) Record(ctx context.Context, entry *audit.Entry) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+var testLimits = LoginLimits{
+	MaxFailuresPerUser: 5,
+	MaxFailuresPerIP:   20,
+	RegisterPerIP:      2,
+	Window:             15 * time.Minute,
+	Lockout:            30 * time.Second,
+}
+
+func TestLockedForDoublesUpToWindow(t *testing.T) {
+	g := NewLoginGuard(&memoryAttempts{}, &memoryAudit{}, testLimits)
+	now := time.Now()
+
+	tests := []struct {
+		failures int
+		limit    int
+		want     time.Duration
+	}{
+		{4, 5, 0},
+		{5, 5, 30 * time.Second},
+		{6, 5, time.Minute},
+		{7, 5, 2 * time.Minute},
+		{9, 5, 8 * time.Minute},
+		// 16 minutes is capped at the 15 minute window
+		{10, 5, 15 * time.Minute},
+		{40, 5, 15 * time.Minute},
+		// A zero limit disables the check
+		{100, 0, 0},
+	}
+
+	for _, tt := range tests {
+		stats := model.AttemptStats{Count: tt.failures, First: now, Last: now}
+		if got := g.lockedFor(stats, tt.limit, now); got != tt.want {
+			t.Errorf("lockedFor(%d failures, limit %d) = %s, want %s", tt.failures, tt.limit, got, tt.want)
+		}
+	}
+
+	// The lockout counts from the latest failure
+	stats := model.AttemptStats{Count: 5, Last: now.Add(-20 * time.Second)}
+	if got := g.lockedFor(stats, 5, now); got != 10*time.Second {
+		t.Errorf("lockedFor 20s after the last failure = %s, want 10s", got)
+	}
+	stats.Last = now.Add(-time.Minute)
+	if got := g.lockedFor(stats, 5, now); got > 0 {
+		t.Errorf("lockedFor after the lockout = %s, want unlocked", got)
+	}
+}
+
+func TestLoginGuardLocksUsernameAndIP(t *testing.T) {
+	ctx := context.Background()
+	attempts := &memoryAttempts{}
+	recorder := &memoryAudit{}
+	g := NewLoginGuard(attempts, recorder, testLimits)
+	client := ClientInfo{IPAddress: "203.0.113.7"}
+
+	for i := 0; i < testLimits.MaxFailuresPerUser; i++ {
+		if err := g.CheckLogin(ctx, "Ada", client); err != nil {
+			t.Fatalf("attempt %d: %v", i, err)
+		}
+		g.LoginFailed(ctx, "Ada", nil, "wrong_password", client)
+	}
+
+	// Case variations share the username's counter
+	var locked *usererr.LockedError
+	if err := g.CheckLogin(ctx, " ADA ", ClientInfo{IPAddress: "198.51.100.1"}); !errors.As(err, &locked) {
+		t.Fatalf("err = %v, want a LockedError", err)
+	}
+	if locked.RetryAfter <= 0 || locked.RetryAfter > testLimits.Lockout {
+		t.Errorf("RetryAfter = %s, want up to %s", locked.RetryAfter, testLimits.Lockout)
+	}
+	if last := recorder.entries[len(recorder.entries)-1]; last.Action != audit.ActionLoginLocked {
+		t.Errorf("last audit action = %s, want %s", last.Action, audit.ActionLoginLocked)
+	}
+
+	// Other usernames from the same IP are only limited by the IP's own count
+	if err := g.CheckLogin(ctx, "grace", client); err != nil {
+		t.Errorf("other username: %v", err)
+	}
+
+	if err := g.LoginSucceeded(ctx, "ada"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CheckLogin(ctx, "Ada", client); err != nil {
+		t.Errorf("after a successful login: %v", err)
+	}
+}
+
+func TestAllowRegisterLimitsPerIP(t *testing.T) {
+	ctx := context.Background()
+	g := NewLoginGuard(&memoryAttempts{}, &memoryAudit{}, testLimits)
+	client := ClientInfo{IPAddress: "203.0.113.7"}
+
+	for i := 0; i < testLimits.RegisterPerIP; i++ {
+		if err := g.AllowRegister(ctx, "user", client); err != nil {
+			t.Fatalf("registration %d: %v", i, err)
+		}
+	}
+	if err := g.AllowRegister(ctx, "user", client); !errors.Is(err, usererr.ErrTooManyAttempts) {
+		t.Errorf("err = %v, want ErrTooManyAttempts", err)
+	}
+	if err := g.AllowRegister(ctx, "user", ClientInfo{IPAddress: "198.51.100.1"}); err != nil {
+		t.Errorf("other IP: %v", err)
+	}
+}
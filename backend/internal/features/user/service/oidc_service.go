@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/oidc"
+	"github.com/jobping/backend/internal/features/user/repository"
+	"github.com/jobping/backend/internal/features/user/usererr"
+)
+
+// loginStateTTL bounds how long a user has to finish signing in at the provider
+const loginStateTTL = 10 * time.Minute
+
+// OIDCService signs users in with external providers and manages their linked identities.
+//
+// Begin stores the state (hashed), nonce and PKCE verifier server-side and returns the provider URL.
+// The frontend receives the provider's redirect at redirectURL and posts code and state back, which
+// CompleteLogin/CompleteLink consume exactly once.
+type OIDCService struct {
+	userRepo     repository.UserRepository
+	identityRepo repository.IdentityRepository
+	providers    map[string]oidc.Provider
+	order        []string
+	redirectURL  string
+}
+
+func NewOIDCService(
+	userRepo repository.UserRepository,
+	identityRepo repository.IdentityRepository,
+	providers []oidc.Provider,
+	redirectURL string,
+) *OIDCService {
+	s := &OIDCService{
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		providers:    make(map[string]oidc.Provider, len(providers)),
+		redirectURL:  redirectURL,
+	}
+	for _, p := range providers {
+		s.providers[p.Name()] = p
+		s.order = append(s.order, p.Name())
+	}
+	return s
+}
+
+// Providers returns the names of the configured providers in configuration order
+func (s *OIDCService) Providers() []string {
+	return s.order
+}
+
+// Begin starts a login with the provider and returns the URL to send the browser to.
+// When linkUserID is set the login links the identity to that user instead of signing in.
+func (s *OIDCService) Begin(ctx context.Context, providerName string, linkUserID *uuid.UUID) (string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", usererr.ErrUnknownProvider
+	}
+
+	state, err := oidc.RandomString(32)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := oidc.RandomString(16)
+	if err != nil {
+		return "", err
+	}
+	verifier, err := oidc.NewVerifier()
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, oidc.AuthRequest{
+		State:         state,
+		Nonce:         nonce,
+		CodeChallenge: oidc.Challenge(verifier),
+		RedirectURI:   s.redirectURL,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	err = s.identityRepo.CreateState(ctx, &model.OIDCLoginState{
+		StateHash:    hashToken(state),
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		UserID:       linkUserID,
+		ExpiresAt:    now.Add(loginStateTTL),
+		CreatedAt:    now,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return authURL, nil
+}
+
+// CompleteLogin finishes a login started without a user. It returns the signed-in user and
+// whether the account was created by this login.
+//
+// An unknown identity is linked to an existing account only when both the provider and the
+// account have verified the same email address; otherwise a new account is created.
+func (s *OIDCService) CompleteLogin(ctx context.Context, code, state string) (*model.User, bool, error) {
+	loginState, identity, err := s.exchange(ctx, code, state)
+	if err != nil {
+		return nil, false, err
+	}
+	if loginState.UserID != nil {
+		// A linking flow must be completed by the signed-in user it was started for
+		return nil, false, usererr.ErrInvalidLoginState
+	}
+
+	existing, err := s.identityRepo.GetByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		if err := s.identityRepo.TouchLogin(ctx, existing.ID, verifiedEmail(identity)); err != nil {
+			return nil, false, err
+		}
+		user, err := s.userRepo.GetUserByID(ctx, existing.UserID)
+		if err != nil {
+			return nil, false, err
+		}
+		if user == nil {
+			return nil, false, usererr.ErrUserNotFound
+		}
+		if user.DisabledAt != nil {
+			return nil, false, usererr.ErrAccountDisabled
+		}
+		return user, false, nil
+	}
+
+	email := verifiedEmail(identity)
+	if email != nil {
+		user, err := s.userRepo.GetUserByEmail(ctx, *email)
+		if err != nil {
+			return nil, false, err
+		}
+		if user != nil {
+			if user.EmailVerifiedAt == nil {
+				return nil, false, usererr.ErrEmailNeedsLinking
+			}
+			if user.DisabledAt != nil {
+				return nil, false, usererr.ErrAccountDisabled
+			}
+			if err := s.link(ctx, user.ID, identity); err != nil {
+				return nil, false, err
+			}
+			return user, false, nil
+		}
+	}
+
+	user, err := s.createUser(ctx, identity, email)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := s.link(ctx, user.ID, identity); err != nil {
+		return nil, false, err
+	}
+	return user, true, nil
+}
+
+// CompleteLink finishes a linking flow started by userID
+func (s *OIDCService) CompleteLink(ctx context.Context, userID uuid.UUID, code, state string) (*model.UserIdentity, error) {
+	loginState, identity, err := s.exchange(ctx, code, state)
+	if err != nil {
+		return nil, err
+	}
+	if loginState.UserID == nil || *loginState.UserID != userID {
+		return nil, usererr.ErrInvalidLoginState
+	}
+
+	existing, err := s.identityRepo.GetByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if existing.UserID != userID {
+			return nil, usererr.ErrIdentityLinked
+		}
+		return existing, nil
+	}
+
+	now := time.Now()
+	linked := &model.UserIdentity{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		Email:     verifiedEmail(identity),
+		CreatedAt: now,
+	}
+	if err := s.identityRepo.Create(ctx, linked); err != nil {
+		return nil, err
+	}
+	return linked, nil
+}
+
+func (s *OIDCService) GetIdentities(ctx context.Context, userID uuid.UUID) ([]model.UserIdentity, error) {
+	return s.identityRepo.GetByUserID(ctx, userID)
+}
+
+// Unlink removes a linked identity unless it is the user's only way to sign in
+func (s *OIDCService) Unlink(ctx context.Context, userID, identityID uuid.UUID) error {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return usererr.ErrUserNotFound
+	}
+
+	if user.PasswordHash == "" {
+		identities, err := s.identityRepo.GetByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if len(identities) <= 1 {
+			return usererr.ErrLastLoginMethod
+		}
+	}
+
+	deleted, err := s.identityRepo.Delete(ctx, userID, identityID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return usererr.ErrIdentityNotFound
+	}
+	return nil
+}
+
+// exchange consumes the login state and redeems the code at its provider
+func (s *OIDCService) exchange(ctx context.Context, code, state string) (*model.OIDCLoginState, *oidc.Identity, error) {
+	if code == "" || state == "" {
+		return nil, nil, usererr.ErrInvalidLoginState
+	}
+
+	loginState, err := s.identityRepo.ConsumeState(ctx, hashToken(state), time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	if loginState == nil {
+		return nil, nil, usererr.ErrInvalidLoginState
+	}
+
+	provider, ok := s.providers[loginState.Provider]
+	if !ok {
+		return nil, nil, usererr.ErrUnknownProvider
+	}
+
+	identity, err := provider.Exchange(ctx, code, loginState.CodeVerifier, s.redirectURL, loginState.Nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+	return loginState, identity, nil
+}
+
+func (s *OIDCService) link(ctx context.Context, userID uuid.UUID, identity *oidc.Identity) error {
+	now := time.Now()
+	return s.identityRepo.Create(ctx, &model.UserIdentity{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Provider:    identity.Provider,
+		Subject:     identity.Subject,
+		Email:       verifiedEmail(identity),
+		CreatedAt:   now,
+		LastLoginAt: &now,
+	})
+}
+
+// createUser creates an account for a first-time external login. It has no password, so
+// password login fails until the user sets one through a password reset.
+func (s *OIDCService) createUser(ctx context.Context, identity *oidc.Identity, email *string) (*model.User, error) {
+	username, err := s.availableUsername(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user := &model.User{
+		ID:        uuid.New(),
+		Username:  username,
+		Email:     email,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if email != nil {
+		user.EmailVerifiedAt = &now
+	}
+
+	if err := s.userRepo.CreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// availableUsername derives a username from the identity, adding a random suffix when taken
+func (s *OIDCService) availableUsername(ctx context.Context, identity *oidc.Identity) (string, error) {
+	base := usernameBase(identity)
+	candidate := base
+	for attempt := 0; attempt < 5; attempt++ {
+		existing, err := s.userRepo.GetUserByUsername(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if existing == nil {
+			return candidate, nil
+		}
+
+		suffix := make([]byte, 3)
+		if _, err := rand.Read(suffix); err != nil {
+			return "", err
+		}
+		candidate = base + "-" + hex.EncodeToString(suffix)
+	}
+	return "", fmt.Errorf("no free username for %q", base)
+}
+
+func usernameBase(identity *oidc.Identity) string {
+	candidates := []string{identity.PreferredUsername}
+	if at := strings.IndexByte(identity.Email, '@'); at > 0 {
+		candidates = append(candidates, identity.Email[:at])
+	}
+	candidates = append(candidates, identity.Name)
+
+	for _, c := range candidates {
+		if name := sanitizeUsername(c); len(name) >= 3 {
+			return name
+		}
+	}
+	return identity.Provider + "-user"
+}
+
+// sanitizeUsername keeps lowercase letters, digits, '.', '_' and '-', turning spaces into '.'
+func sanitizeUsername(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('.')
+		}
+		if b.Len() >= 32 {
+			break
+		}
+	}
+	return strings.Trim(b.String(), ".-_")
+}
+
+// verifiedEmail returns the identity's normalized email if the provider verified it
+func verifiedEmail(identity *oidc.Identity) *string {
+	if !identity.EmailVerified || identity.Email == "" {
+		return nil
+	}
+	email, err := NormalizeEmail(identity.Email)
+	if err != nil {
+		return nil
+	}
+	return &email
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/oidc"
+	"github.com/jobping/backend/internal/features/user/oidc/oidctest"
+	"github.com/jobping/backend/internal/features/user/usererr"
+)
+
+const testRedirectURL = "https://jobping.test/auth/callback"
+
+type memoryIdentities struct {
+	mu         sync.Mutex
+	identities map[uuid.UUID]*model.UserIdentity
+	states     map[string]*model.OIDCLoginState
+}
+
+func newMemoryIdentities() *memoryIdentities {
+	return &memoryIdentities{
+		identities: make(map[uuid.UUID]*model.UserIdentity),
+		states:     make(map[string]*model.OIDCLoginState),
+	}
+}
+
+func (r *memoryIdentities) Create(ctx context.Context, identity *model.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *identity
+	r.identities[identity.ID] = &stored
+	return nil
+}
+
+func (r *memoryIdentities) GetByProviderSubject(ctx context.Context, provider, subject string) (*model.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, i := range r.identities {
+		if i.Provider == provider && i.Subject == subject {
+			found := *i
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryIdentities) GetByUserID(ctx context.Context, userID uuid.UUID) ([]model.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var identities []model.UserIdentity
+	for _, i := range r.identities {
+		if i.UserID == userID {
+			identities = append(identities, *i)
+		}
+	}
+	return identities, nil
+}
+
+func (r *memoryIdentities) TouchLogin(ctx context.Context, id uuid.UUID, email *string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	r.identities[id].LastLoginAt = &now
+	return nil
+}
+
+func (r *memoryIdentities) Delete(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i, ok := r.identities[id]; !ok || i.UserID != userID {
+		return false, nil
+	}
+	delete(r.identities, id)
+	return true, nil
+}
+
+func (r *memoryIdentities) CreateState(ctx context.Context, state *model.OIDCLoginState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states[state.StateHash] = state
+	return nil
+}
+
+func (r *memoryIdentities) ConsumeState(ctx context.Context, stateHash string, now time.Time) (*model.OIDCLoginState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state, ok := r.states[stateHash]
+	delete(r.states, stateHash)
+	if !ok || now.After(state.ExpiresAt) {
+		return nil, nil
+	}
+	return state, nil
+}
+
+type oidcFixture struct {
+	service    *OIDCService
+	provider   *oidctest.Provider
+	users      *memoryUsers
+	identities *memoryIdentities
+}
+
+func newOIDCFixture(t *testing.T) *oidcFixture {
+	t.Helper()
+	provider, srv := oidctest.NewServer("jobping-test", "jobping-test-secret")
+	t.Cleanup(srv.Close)
+
+	f := &oidcFixture{
+		provider:   provider,
+		users:      &memoryUsers{users: make(map[uuid.UUID]*model.User)},
+		identities: newMemoryIdentities(),
+	}
+	client := oidc.NewOIDCProvider("mock", srv.URL, provider.ClientID, provider.ClientSecret, nil)
+	f.service = NewOIDCService(f.users, f.identities, []oidc.Provider{client}, testRedirectURL)
+	return f
+}
+
+// authorize runs Begin and follows the provider's redirect back, returning the code and state
+// the frontend would post to CompleteLogin or CompleteLink
+func (f *oidcFixture) authorize(t *testing.T, linkUserID *uuid.UUID) (string, string) {
+	t.Helper()
+	authURL, err := f.service.Begin(context.Background(), "mock", linkUserID)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize: status %d", resp.StatusCode)
+	}
+
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("parse callback: %v", err)
+	}
+	if got := callback.Scheme + "://" + callback.Host + callback.Path; got != testRedirectURL {
+		t.Fatalf("redirected to %s, want %s", got, testRedirectURL)
+	}
+	return callback.Query().Get("code"), callback.Query().Get("state")
+}
+
+func TestOIDCLoginCreatesAccountThenSignsIn(t *testing.T) {
+	ctx := context.Background()
+	f := newOIDCFixture(t)
+
+	code, state := f.authorize(t, nil)
+	user, created, err := f.service.CompleteLogin(ctx, code, state)
+	if err != nil {
+		t.Fatalf("first login: %v", err)
+	}
+	if !created {
+		t.Error("first login did not create an account")
+	}
+	if user.Username != "mockuser" {
+		t.Errorf("username = %q, want mockuser", user.Username)
+	}
+	if user.Email == nil || *user.Email != "mock.user@example.com" || user.EmailVerifiedAt == nil {
+		t.Errorf("email = %v (verified %v), want the provider's verified address", user.Email, user.EmailVerifiedAt)
+	}
+
+	code, state = f.authorize(t, nil)
+	again, created, err := f.service.CompleteLogin(ctx, code, state)
+	if err != nil {
+		t.Fatalf("second login: %v", err)
+	}
+	if created || again.ID != user.ID {
+		t.Errorf("second login: user %s created=%v, want existing user %s", again.ID, created, user.ID)
+	}
+}
+
+func TestOIDCLoginLinksAccountWithVerifiedEmail(t *testing.T) {
+	ctx := context.Background()
+	f := newOIDCFixture(t)
+
+	email := "mock.user@example.com"
+	now := time.Now()
+	existing := &model.User{ID: uuid.New(), Username: "ada", Email: &email, EmailVerifiedAt: &now, PasswordHash: "hash"}
+	f.users.CreateUser(ctx, existing)
+
+	code, state := f.authorize(t, nil)
+	user, created, err := f.service.CompleteLogin(ctx, code, state)
+	if err != nil {
+		t.Fatalf("CompleteLogin: %v", err)
+	}
+	if created || user.ID != existing.ID {
+		t.Errorf("signed in as %s created=%v, want existing user %s", user.ID, created, existing.ID)
+	}
+	identities, _ := f.identities.GetByUserID(ctx, existing.ID)
+	if len(identities) != 1 || identities[0].Subject != "mock-user-1" {
+		t.Errorf("identities = %+v, want the provider's subject linked", identities)
+	}
+}
+
+func TestOIDCLoginRefusesUnverifiedEmailMatch(t *testing.T) {
+	ctx := context.Background()
+	f := newOIDCFixture(t)
+
+	email := "mock.user@example.com"
+	f.users.CreateUser(ctx, &model.User{ID: uuid.New(), Username: "ada", Email: &email})
+
+	code, state := f.authorize(t, nil)
+	if _, _, err := f.service.CompleteLogin(ctx, code, state); !errors.Is(err, usererr.ErrEmailNeedsLinking) {
+		t.Errorf("err = %v, want ErrEmailNeedsLinking", err)
+	}
+}
+
+func TestOIDCLoginIgnoresUnverifiedProviderEmail(t *testing.T) {
+	ctx := context.Background()
+	f := newOIDCFixture(t)
+	f.provider.SetUser(oidctest.User{Subject: "unverified-1", Email: "someone@example.com", PreferredUsername: "someone"})
+
+	code, state := f.authorize(t, nil)
+	user, created, err := f.service.CompleteLogin(ctx, code, state)
+	if err != nil {
+		t.Fatalf("CompleteLogin: %v", err)
+	}
+	if !created || user.Email != nil {
+		t.Errorf("created=%v email=%v, want a new account without the unverified address", created, user.Email)
+	}
+}
+
+func TestOIDCLoginStateIsSingleUse(t *testing.T) {
+	ctx := context.Background()
+	f := newOIDCFixture(t)
+
+	code, state := f.authorize(t, nil)
+	if _, _, err := f.service.CompleteLogin(ctx, code, state); err != nil {
+		t.Fatalf("CompleteLogin: %v", err)
+	}
+	if _, _, err := f.service.CompleteLogin(ctx, code, state); !errors.Is(err, usererr.ErrInvalidLoginState) {
+		t.Errorf("replayed state: err = %v, want ErrInvalidLoginState", err)
+	}
+	if _, _, err := f.service.CompleteLogin(ctx, code, "forged"); !errors.Is(err, usererr.ErrInvalidLoginState) {
+		t.Errorf("unknown state: err = %v, want ErrInvalidLoginState", err)
+	}
+}
+
+func TestOIDCLinkOnlyCompletesForStartingUser(t *testing.T) {
+	ctx := context.Background()
+	f := newOIDCFixture(t)
+
+	owner := &model.User{ID: uuid.New(), Username: "ada", PasswordHash: "hash"}
+	f.users.CreateUser(ctx, owner)
+
+	code, state := f.authorize(t, &owner.ID)
+	if _, err := f.service.CompleteLink(ctx, uuid.New(), code, state); !errors.Is(err, usererr.ErrInvalidLoginState) {
+		t.Errorf("other user: err = %v, want ErrInvalidLoginState", err)
+	}
+
+	code, state = f.authorize(t, &owner.ID)
+	linked, err := f.service.CompleteLink(ctx, owner.ID, code, state)
+	if err != nil {
+		t.Fatalf("CompleteLink: %v", err)
+	}
+	if linked.UserID != owner.ID || linked.Provider != "mock" || linked.Subject != "mock-user-1" {
+		t.Errorf("linked = %+v", linked)
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jobping/backend/internal/features/user/usererr"
+)
+
+// maxPasswordBytes is bcrypt's input limit; longer passwords are rejected rather than truncated
+const maxPasswordBytes = 72
+
+// PasswordPolicy checks new passwords: a minimum length and a list of known breached passwords
+type PasswordPolicy struct {
+	minLength int
+	breached  map[[sha1.Size]byte]struct{}
+}
+
+// NewPasswordPolicy loads the breached password list from path when it is set. Each line is a
+// password, or its SHA-1 in hex optionally followed by ":count" (the Have I Been Pwned export
+// format). Blank lines and lines starting with '#' are ignored.
+func NewPasswordPolicy(minLength int, path string) (*PasswordPolicy, error) {
+	p := &PasswordPolicy{
+		minLength: minLength,
+		breached:  make(map[[sha1.Size]byte]struct{}),
+	}
+	if path == "" {
+		return p, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open breached password list: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p.breached[breachedKey(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read breached password list: %w", err)
+	}
+	return p, nil
+}
+
+// Validate returns an error wrapping usererr.ErrWeakPassword that says what to change
+func (p *PasswordPolicy) Validate(password string) error {
+	if utf8.RuneCountInString(password) < p.minLength {
+		return fmt.Errorf("%w: use at least %d characters", usererr.ErrWeakPassword, p.minLength)
+	}
+	if len(password) > maxPasswordBytes {
+		return fmt.Errorf("%w: use at most %d bytes", usererr.ErrWeakPassword, maxPasswordBytes)
+	}
+	if p.isBreached(password) {
+		return fmt.Errorf("%w: this password has appeared in a data breach, choose another", usererr.ErrWeakPassword)
+	}
+	return nil
+}
+
+// isBreached also checks the lowercased password since most lists only carry one casing
+func (p *PasswordPolicy) isBreached(password string) bool {
+	if len(p.breached) == 0 {
+		return false
+	}
+	if _, ok := p.breached[sha1.Sum([]byte(password))]; ok {
+		return true
+	}
+	_, ok := p.breached[sha1.Sum([]byte(strings.ToLower(password)))]
+	return ok
+}
+
+func breachedKey(line string) [sha1.Size]byte {
+	hash := line
+	if i := strings.IndexByte(hash, ':'); i == 2*sha1.Size {
+		hash = hash[:i]
+	}
+	if len(hash) == 2*sha1.Size {
+		var key [sha1.Size]byte
+		if _, err := hex.Decode(key[:], []byte(hash)); err == nil {
+			return key
+		}
+	}
+	return sha1.Sum([]byte(line))
+}
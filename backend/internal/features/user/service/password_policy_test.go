@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jobping/backend/internal/features/user/usererr"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	// A plain password and the HIBP line for "password", with comments and blank lines skipped
+	list := strings.Join([]string{
+		"# breached passwords",
+		"",
+		"hunter2-hunter2",
+		"5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824",
+	}, "\n")
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	if err := os.WriteFile(path, []byte(list), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := NewPasswordPolicy(8, path)
+	if err != nil {
+		t.Fatalf("NewPasswordPolicy: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		valid    bool
+	}{
+		{"long enough", "a sturdy passphrase", true},
+		{"too short", "short", false},
+		// Length counts characters, not bytes
+		{"eight runes", "ääääääää", true},
+		{"seven runes", "äääääää", false},
+		{"72 bytes", strings.Repeat("x", 72), true},
+		{"over the bcrypt limit", strings.Repeat("x", 73), false},
+		{"breached", "hunter2-hunter2", false},
+		{"breached in another case", "Hunter2-Hunter2", false},
+		{"breached by hash", "password", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(tt.password)
+			if tt.valid && err != nil {
+				t.Errorf("Validate: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, usererr.ErrWeakPassword) {
+				t.Errorf("err = %v, want ErrWeakPassword", err)
+			}
+		})
+	}
+}
+
+func TestBreachedKeyReadsSHA1Lines(t *testing.T) {
+	// SHA-1 of "password"
+	const hash = "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8"
+	policy := &PasswordPolicy{breached: map[[20]byte]struct{}{breachedKey(hash + ":9545824"): {}}}
+	if !policy.isBreached("password") {
+		t.Error("HIBP line with a count did not match its password")
+	}
+	policy.breached = map[[20]byte]struct{}{breachedKey(strings.ToLower(hash)): {}}
+	if !policy.isBreached("PASSWORD") {
+		t.Error("lowercase hex line did not match the lowercased password")
+	}
+	if policy.isBreached("passw0rd") {
+		t.Error("unlisted password matched")
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/audit"
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/repository"
+	"github.com/jobping/backend/internal/features/user/usererr"
+	"github.com/jobping/backend/internal/mailer"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// purgeBatchSize bounds how many accounts one PurgeDue call deletes
+const purgeBatchSize = 100
+
+// Audit actions for account deletion
+const (
+	ActionDeletionScheduled = "account.deletion_scheduled"
+	ActionDeletionCancelled = "account.deletion_cancelled"
+	ActionAccountPurged     = "account.purged"
+)
+
+// PrivacyService exports a user's data and deletes accounts.
+//
+// Deletion is two-step: RequestDeletion (confirmed with the username and password) schedules
+// the purge after a grace period and signs the user out everywhere. Until then the user can
+// sign in again and cancel. PurgeDue, run on a schedule, deletes accounts whose grace period ended.
+type PrivacyService struct {
+	userRepo  repository.UserRepository
+	dataRepo  repository.UserDataRepository
+	tokenRepo repository.APITokenRepository
+	sessions  *SessionService
+	mailer    mailer.Mailer
+	audit     audit.Recorder
+	grace     time.Duration
+}
+
+func NewPrivacyService(
+	userRepo repository.UserRepository,
+	dataRepo repository.UserDataRepository,
+	tokenRepo repository.APITokenRepository,
+	sessions *SessionService,
+	m mailer.Mailer,
+	recorder audit.Recorder,
+	grace time.Duration,
+) *PrivacyService {
+	return &PrivacyService{
+		userRepo:  userRepo,
+		dataRepo:  dataRepo,
+		tokenRepo: tokenRepo,
+		sessions:  sessions,
+		mailer:    m,
+		audit:     recorder,
+		grace:     grace,
+	}
+}
+
+// Export returns the user's data by section (profile, preferences, matches, notifications, ...)
+func (s *PrivacyService) Export(ctx context.Context, userID uuid.UUID) (map[string]json.RawMessage, error) {
+	return s.dataRepo.Export(ctx, userID)
+}
+
+// RequestDeletion schedules the account for deletion and returns when it will be purged.
+// confirmUsername must match the username; password is required when the account has one.
+func (s *PrivacyService) RequestDeletion(ctx context.Context, userID uuid.UUID, confirmUsername, password string, client ClientInfo) (time.Time, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if user == nil {
+		return time.Time{}, usererr.ErrUserNotFound
+	}
+	if user.DeletionScheduledAt != nil {
+		return time.Time{}, usererr.ErrDeletionScheduled
+	}
+
+	if !strings.EqualFold(strings.TrimSpace(confirmUsername), user.Username) {
+		return time.Time{}, usererr.ErrDeletionNotConfirmed
+	}
+	if user.PasswordHash != "" {
+		if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+			return time.Time{}, usererr.ErrDeletionNotConfirmed
+		}
+	}
+
+	now := time.Now()
+	deleteAt := now.Add(s.grace)
+	if _, err := s.userRepo.ScheduleDeletion(ctx, userID, &deleteAt); err != nil {
+		return time.Time{}, err
+	}
+
+	// Sign out everywhere; the user can sign in again to cancel
+	if _, err := s.sessions.LogoutAll(ctx, userID); err != nil {
+		return time.Time{}, err
+	}
+	if _, err := s.tokenRepo.RevokeAllForUser(ctx, userID, now); err != nil {
+		return time.Time{}, err
+	}
+
+	s.record(ctx, &audit.Entry{
+		ActorID:    &userID,
+		Action:     ActionDeletionScheduled,
+		TargetType: "user",
+		TargetID:   userID.String(),
+		IPAddress:  client.IPAddress,
+		UserAgent:  client.UserAgent,
+		Metadata:   map[string]interface{}{"delete_at": deleteAt.Format(time.RFC3339)},
+	})
+	s.notify(ctx, user, "Your JobPing account will be deleted", fmt.Sprintf(
+		"Hi %s,\n\nYour JobPing account and all of its data will be deleted on %s.\n\nChanged your mind? Sign in before then and cancel the deletion from your profile.\n",
+		user.Username, deleteAt.UTC().Format("January 2, 2006 15:04 MST"),
+	))
+	return deleteAt, nil
+}
+
+// CancelDeletion keeps an account that was scheduled for deletion
+func (s *PrivacyService) CancelDeletion(ctx context.Context, userID uuid.UUID, client ClientInfo) error {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return usererr.ErrUserNotFound
+	}
+	if user.DeletionScheduledAt == nil {
+		return usererr.ErrDeletionNotScheduled
+	}
+
+	if _, err := s.userRepo.ScheduleDeletion(ctx, userID, nil); err != nil {
+		return err
+	}
+	s.record(ctx, &audit.Entry{
+		ActorID:    &userID,
+		Action:     ActionDeletionCancelled,
+		TargetType: "user",
+		TargetID:   userID.String(),
+		IPAddress:  client.IPAddress,
+		UserAgent:  client.UserAgent,
+	})
+	return nil
+}
+
+// PurgeDue deletes the accounts whose grace period has ended and returns how many were deleted
+func (s *PrivacyService) PurgeDue(ctx context.Context) (int, error) {
+	users, err := s.userRepo.GetDueForDeletion(ctx, time.Now(), purgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, user := range users {
+		if err := s.dataRepo.Purge(ctx, user.ID, user.Username); err != nil {
+			return purged, fmt.Errorf("purge user %s: %w", user.ID, err)
+		}
+		// The entry keeps only the ID, which no longer resolves to anyone
+		s.record(ctx, &audit.Entry{
+			Action:     ActionAccountPurged,
+			TargetType: "user",
+			TargetID:   user.ID.String(),
+		})
+		purged++
+	}
+	return purged, nil
+}
+
+// RunPurges calls PurgeDue every interval until ctx is done. The local server uses it;
+// in AWS a scheduled event triggers the purge instead.
+func (s *PrivacyService) RunPurges(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if purged, err := s.PurgeDue(ctx); err != nil {
+			log.Printf("Failed to purge deleted accounts: %v", err)
+		} else if purged > 0 {
+			log.Printf("Purged %d deleted accounts", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// notify emails the user at their verified address, if they have one
+func (s *PrivacyService) notify(ctx context.Context, user *model.User, subject, body string) {
+	if user.Email == nil || user.EmailVerifiedAt == nil {
+		return
+	}
+	if err := s.mailer.Send(ctx, mailer.Message{To: *user.Email, Subject: subject, TextBody: body}); err != nil {
+		log.Printf("Failed to send account deletion email: %v", err)
+	}
+}
+
+func (s *PrivacyService) record(ctx context.Context, entry *audit.Entry) {
+	if err := s.audit.Record(ctx, entry); err != nil {
+		log.Printf("Failed to write audit entry %s: %v", entry.Action, err)
+	}
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	jobmodel "github.com/jobping/backend/internal/features/job/model"
+	jobrepo "github.com/jobping/backend/internal/features/job/repository"
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/repository"
+	"github.com/jobping/backend/internal/features/user/usererr"
+)
+
+const (
+	defaultRescoreDays = 7
+	maxRescoreDays     = 30
+	// maxRescoreJobs caps how many jobs one run queues: the newest that pass the search's filters
+	// and were not scored against its current prompt
+	maxRescoreJobs = 200
+	// rescoreScanBatch is how many jobs staleJobs reads at a time while looking for them
+	rescoreScanBatch = 500
+	// A run still unfinished after this long is treated as abandoned and no longer blocks a new one
+	rescoreStaleAfter = 24 * time.Hour
+	rescoreListLimit  = 20
+)
+
+// RescoreService re-queues recent jobs to user analysis after a saved search's prompt changes,
+// so their matches are scored against the current prompt
+type RescoreService struct {
+	searchRepo  repository.SavedSearchRepository
+	matchRepo   repository.UserJobMatchRepository
+	rescoreRepo repository.RescoreRepository
+	jobRepo     jobrepo.JobRepository
+	queue       AnalysisQueue
+}
+
+// NewRescoreService builds the service. queue may be nil, in which case Start fails with
+// usererr.ErrRescoreUnavailable.
+func NewRescoreService(
+	searchRepo repository.SavedSearchRepository,
+	matchRepo repository.UserJobMatchRepository,
+	rescoreRepo repository.RescoreRepository,
+	jobRepo jobrepo.JobRepository,
+	queue AnalysisQueue,
+) *RescoreService {
+	return &RescoreService{
+		searchRepo:  searchRepo,
+		matchRepo:   matchRepo,
+		rescoreRepo: rescoreRepo,
+		jobRepo:     jobRepo,
+		queue:       queue,
+	}
+}
+
+// Start queues the processed jobs of the last days days that pass the search's filters and have
+// no match scored against its current prompt. days of 0 means the default of 7.
+func (s *RescoreService) Start(ctx context.Context, userID, searchID uuid.UUID, days int) (*model.RescoreRun, error) {
+	if s.queue == nil {
+		return nil, usererr.ErrRescoreUnavailable
+	}
+	if days == 0 {
+		days = defaultRescoreDays
+	}
+	if days < 1 || days > maxRescoreDays {
+		return nil, usererr.ErrInvalidRescoreDays
+	}
+
+	search, err := s.searchRepo.GetByID(ctx, searchID)
+	if err != nil {
+		return nil, err
+	}
+	if search == nil || search.UserID != userID {
+		return nil, usererr.ErrSavedSearchNotFound
+	}
+	if !search.Active || search.AIPrompt == "" {
+		return nil, usererr.ErrSearchInactive
+	}
+
+	now := time.Now()
+	running, err := s.rescoreRepo.GetUnfinished(ctx, searchID, now.Add(-rescoreStaleAfter))
+	if err != nil {
+		return nil, err
+	}
+	if running != nil {
+		return nil, usererr.ErrRescoreInProgress
+	}
+
+	jobIDs, err := s.staleJobs(ctx, search, now.AddDate(0, 0, -days))
+	if err != nil {
+		return nil, err
+	}
+
+	run := &model.RescoreRun{
+		ID:               uuid.New(),
+		UserID:           userID,
+		SavedSearchID:    searchID,
+		PromptRevisionID: search.PromptRevisionID,
+		Days:             days,
+		Total:            len(jobIDs),
+		CreatedAt:        now,
+	}
+	if run.Total == 0 {
+		run.FinishedAt = &now
+	}
+	// The run must exist before the first message is picked up
+	if err := s.rescoreRepo.Create(ctx, run); err != nil {
+		return nil, err
+	}
+
+	for _, jobID := range jobIDs {
+		if err := s.queue.EnqueueUserAnalysis(ctx, jobID, userID, searchID, run.ID); err != nil {
+			log.Printf("Failed to enqueue job %s for rescore %s: %v", jobID, run.ID, err)
+			if err := s.rescoreRepo.RecordProgress(ctx, run.ID, true); err != nil {
+				log.Printf("Failed to record rescore progress for %s: %v", run.ID, err)
+			}
+		}
+	}
+
+	log.Printf("Started rescore %s of saved search %s: %d jobs from the last %d days", run.ID, searchID, run.Total, days)
+	return s.Get(ctx, userID, run.ID)
+}
+
+// Get returns one of the user's rescore runs with its current progress
+func (s *RescoreService) Get(ctx context.Context, userID, runID uuid.UUID) (*model.RescoreRun, error) {
+	run, err := s.rescoreRepo.GetByID(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil || run.UserID != userID {
+		return nil, usererr.ErrRescoreNotFound
+	}
+	return run, nil
+}
+
+// List returns the user's most recent rescore runs
+func (s *RescoreService) List(ctx context.Context, userID uuid.UUID) ([]model.RescoreRun, error) {
+	return s.rescoreRepo.GetByUserID(ctx, userID, rescoreListLimit)
+}
+
+// staleJobs returns up to maxRescoreJobs processed jobs created after since that the search would
+// be matched against, newest first, skipping those already scored against its current prompt
+func (s *RescoreService) staleJobs(ctx context.Context, search *model.SavedSearch, since time.Time) ([]uuid.UUID, error) {
+	matches, err := s.matchRepo.GetBySavedSearchID(ctx, search.UserID, search.ID)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[uuid.UUID]bool, len(matches))
+	for _, m := range matches {
+		current[m.JobID] = samePromptRevision(m.PromptRevisionID, search.PromptRevisionID)
+	}
+
+	// The cap applies after filtering, so read batches until enough jobs pass
+	var jobIDs []uuid.UUID
+	var after *jobmodel.Job
+	for {
+		jobs, err := s.jobRepo.GetProcessedSince(ctx, since, after, rescoreScanBatch)
+		if err != nil {
+			return nil, err
+		}
+		for _, job := range jobs {
+			if current[job.ID] {
+				continue
+			}
+			if !search.Filters.Match(job.Title, job.Company, job.Location, job.Description, job.IsRemote, job.Place.Coords) {
+				continue
+			}
+			jobIDs = append(jobIDs, job.ID)
+			if len(jobIDs) == maxRescoreJobs {
+				return jobIDs, nil
+			}
+		}
+		if len(jobs) < rescoreScanBatch {
+			return jobIDs, nil
+		}
+		after = &jobs[len(jobs)-1]
+	}
+}
+
+func samePromptRevision(a, b *uuid.UUID) bool {
+	return a != nil && b != nil && *a == *b
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// ResumeAnalyzer derives a structured profile and a suggested matching prompt from resume text
+type ResumeAnalyzer interface {
+	AnalyzeResume(ctx context.Context, text string) (*ResumeAnalysis, error)
+}
+
+// ResumeAnalysis is the analyzer's reading of a resume. Salaries are yearly amounts.
+type ResumeAnalysis struct {
+	Skills          []string `json:"skills"`
+	Seniority       string   `json:"seniority"`
+	Locations       []string `json:"locations"`
+	SalaryMin       *int     `json:"salary_min"`
+	SalaryMax       *int     `json:"salary_max"`
+	SalaryCurrency  string   `json:"salary_currency"`
+	SuggestedPrompt string   `json:"suggested_prompt"`
+}
+
+// resumePromptLength is how much of the resume text is sent to the model
+const resumePromptLength = 6000
+
+type openAIResumeAnalyzer struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewResumeAnalyzer returns an analyzer backed by OpenAI. Without OPENAI_API_KEY it returns
+// an empty profile and a prompt quoting the start of the resume.
+func NewResumeAnalyzer() ResumeAnalyzer {
+	return &openAIResumeAnalyzer{
+		apiKey:     os.Getenv("OPENAI_API_KEY"),
+		httpClient: &http.Client{Timeout: 20 * time.Second},
+	}
+}
+
+func (a *openAIResumeAnalyzer) AnalyzeResume(ctx context.Context, text string) (*ResumeAnalysis, error) {
+	if a.apiKey == "" {
+		// Mock result if no API key
+		return &ResumeAnalysis{
+			SuggestedPrompt: "I am looking for roles that fit this experience:\n" + truncateText(text, 500),
+		}, nil
+	}
+
+	prompt := fmt.Sprintf(`Read this resume and describe the candidate as JSON:
+{
+  "skills": ["technologies, tools and skills the candidate has used, most important first, at most 30"],
+  "seniority": "one of junior, mid, senior, lead, principal",
+  "locations": ["cities, countries or \"remote\" the candidate lives in or asks for"],
+  "salary_min": yearly salary expectation lower bound as a number, or null if not stated,
+  "salary_max": yearly salary expectation upper bound as a number, or null if not stated,
+  "salary_currency": "ISO 4217 code of the salary, or empty",
+  "suggested_prompt": "3-6 sentences in the first person describing the jobs this candidate should look for: roles, seniority, stack, domains, location and work style"
+}
+
+RESUME:
+%s`, truncateText(text, resumePromptLength))
+
+	reqBody, err := json.Marshal(map[string]interface{}{
+		"model": "gpt-3.5-turbo",
+		"messages": []map[string]string{
+			{"role": "system", "content": "You extract structured data from resumes. Reply with JSON only, and never invent facts the resume does not support."},
+			{"role": "user", "content": prompt},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+a.apiKey)
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openai api returned status %d", resp.StatusCode)
+	}
+
+	var completion struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		return nil, err
+	}
+	if len(completion.Choices) == 0 {
+		return nil, fmt.Errorf("no response from openai")
+	}
+
+	content := strings.TrimSpace(completion.Choices[0].Message.Content)
+	// Models sometimes wrap JSON in a code fence
+	content = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(content, "```json"), "```"), "```")
+
+	var analysis ResumeAnalysis
+	if err := json.Unmarshal([]byte(content), &analysis); err != nil {
+		return nil, fmt.Errorf("failed to parse resume analysis: %w", err)
+	}
+	return &analysis, nil
+}
+
+func truncateText(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return strings.ToValidUTF8(s[:maxLen], "") + "..."
+}
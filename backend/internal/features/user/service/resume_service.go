@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/repository"
+	"github.com/jobping/backend/internal/features/user/resume"
+	"github.com/jobping/backend/internal/features/user/usererr"
+)
+
+const (
+	// MaxResumeSize is the largest resume upload accepted, in bytes
+	MaxResumeSize = 4 << 20
+
+	maxResumeSkills    = 50
+	maxResumeLocations = 10
+	maxResumeFileName  = 255
+)
+
+// ResumeService turns an uploaded resume into a stored profile and a suggested prompt
+type ResumeService struct {
+	resumeRepo repository.ResumeRepository
+	analyzer   ResumeAnalyzer
+	users      *UserService
+}
+
+func NewResumeService(resumeRepo repository.ResumeRepository, analyzer ResumeAnalyzer, users *UserService) *ResumeService {
+	return &ResumeService{
+		resumeRepo: resumeRepo,
+		analyzer:   analyzer,
+		users:      users,
+	}
+}
+
+// Upload extracts the resume's text, derives the profile and stores it, replacing any earlier one.
+// With apply, the suggested prompt also becomes the prompt of the user's default saved search.
+func (s *ResumeService) Upload(ctx context.Context, userID uuid.UUID, fileName string, data []byte, apply bool) (*model.ResumeProfile, error) {
+	if len(data) == 0 {
+		return nil, usererr.ErrResumeMissing
+	}
+	if len(data) > MaxResumeSize {
+		return nil, usererr.ErrResumeTooLarge
+	}
+
+	format, text, err := resume.Extract(data)
+	switch {
+	case errors.Is(err, resume.ErrUnsupportedFormat):
+		return nil, usererr.ErrUnsupportedResume
+	case errors.Is(err, resume.ErrNoText):
+		return nil, usererr.ErrResumeUnreadable
+	case err != nil:
+		return nil, err
+	}
+
+	analysis, err := s.analyzer.AnalyzeResume(ctx, text)
+	if err != nil {
+		log.Printf("Failed to analyze resume for user %s: %v", userID, err)
+		return nil, usererr.ErrResumeAnalysisFailed
+	}
+
+	now := time.Now()
+	profile := &model.ResumeProfile{
+		UserID:          userID,
+		FileName:        cleanFileName(fileName),
+		Format:          format,
+		Text:            text,
+		Skills:          dedupeTerms(analysis.Skills, maxResumeSkills),
+		Seniority:       strings.ToLower(strings.TrimSpace(analysis.Seniority)),
+		Locations:       dedupeTerms(analysis.Locations, maxResumeLocations),
+		SalaryMin:       positive(analysis.SalaryMin),
+		SalaryMax:       positive(analysis.SalaryMax),
+		SalaryCurrency:  strings.ToUpper(strings.TrimSpace(analysis.SalaryCurrency)),
+		SuggestedPrompt: strings.TrimSpace(analysis.SuggestedPrompt),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if !model.ValidSeniority(profile.Seniority) {
+		profile.Seniority = ""
+	}
+	if len(profile.SalaryCurrency) != 3 {
+		profile.SalaryCurrency = ""
+	}
+	if profile.SalaryMin != nil && profile.SalaryMax != nil && *profile.SalaryMin > *profile.SalaryMax {
+		profile.SalaryMin, profile.SalaryMax = profile.SalaryMax, profile.SalaryMin
+	}
+
+	if err := s.resumeRepo.Upsert(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	if apply && profile.SuggestedPrompt != "" {
+		if err := s.users.UpdateAIPrompt(ctx, userID, profile.SuggestedPrompt); err != nil {
+			return nil, err
+		}
+	}
+	return profile, nil
+}
+
+func (s *ResumeService) Get(ctx context.Context, userID uuid.UUID) (*model.ResumeProfile, error) {
+	profile, err := s.resumeRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, usererr.ErrResumeNotFound
+	}
+	return profile, nil
+}
+
+// Delete removes the profile; matching no longer includes its skills. The prompt is kept.
+func (s *ResumeService) Delete(ctx context.Context, userID uuid.UUID) error {
+	deleted, err := s.resumeRepo.Delete(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return usererr.ErrResumeNotFound
+	}
+	return nil
+}
+
+// dedupeTerms trims terms, drops empty ones and case-insensitive duplicates, and keeps at most max
+func dedupeTerms(terms []string, max int) []string {
+	seen := make(map[string]bool, len(terms))
+	var out []string
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+		key := strings.ToLower(term)
+		if term == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, term)
+		if len(out) == max {
+			break
+		}
+	}
+	return out
+}
+
+func positive(n *int) *int {
+	if n == nil || *n <= 0 {
+		return nil
+	}
+	return n
+}
+
+// cleanFileName keeps only the base name of an uploaded file, for display
+func cleanFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+	return truncateText(name, maxResumeFileName-3)
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/repository"
+	"github.com/jobping/backend/internal/features/user/usererr"
+	"github.com/jobping/backend/internal/geo"
+)
+
+const (
+	maxSavedSearchesPerUser = 10
+	maxSearchNameLength     = 100
+	defaultSearchThreshold  = 70
+	maxPromptRevisions      = 100
+	defaultRadiusKm         = 50
+	maxRadiusKm             = 500
+)
+
+// SavedSearchInput holds the fields a user sets on a saved search. Nil fields keep their
+// current value on update and take the default on create.
+type SavedSearchInput struct {
+	Name            *string
+	AIPrompt        *string
+	Filters         *model.SearchFilters
+	NotifyThreshold *int
+	Channels        []string
+	Active          *bool
+}
+
+// SavedSearchService manages a user's saved searches. Changes to the default search are
+// mirrored to the legacy prompt and threshold on the user.
+type SavedSearchService struct {
+	searchRepo repository.SavedSearchRepository
+	userRepo   repository.UserRepository
+	matchRepo  repository.UserJobMatchRepository
+}
+
+func NewSavedSearchService(searchRepo repository.SavedSearchRepository, userRepo repository.UserRepository, matchRepo repository.UserJobMatchRepository) *SavedSearchService {
+	return &SavedSearchService{
+		searchRepo: searchRepo,
+		userRepo:   userRepo,
+		matchRepo:  matchRepo,
+	}
+}
+
+func (s *SavedSearchService) List(ctx context.Context, userID uuid.UUID) ([]model.SavedSearch, error) {
+	return s.searchRepo.GetByUserID(ctx, userID)
+}
+
+// Get returns one of the user's searches
+func (s *SavedSearchService) Get(ctx context.Context, userID, searchID uuid.UUID) (*model.SavedSearch, error) {
+	search, err := s.searchRepo.GetByID(ctx, searchID)
+	if err != nil {
+		return nil, err
+	}
+	if search == nil || search.UserID != userID {
+		return nil, usererr.ErrSavedSearchNotFound
+	}
+	return search, nil
+}
+
+func (s *SavedSearchService) Create(ctx context.Context, userID uuid.UUID, input SavedSearchInput) (*model.SavedSearch, error) {
+	existing, err := s.searchRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxSavedSearchesPerUser {
+		return nil, usererr.ErrTooManySavedSearches
+	}
+
+	now := time.Now()
+	search := &model.SavedSearch{
+		ID:              uuid.New(),
+		UserID:          userID,
+		NotifyThreshold: defaultSearchThreshold,
+		Channels:        []string{model.ChannelDiscord},
+		Active:          true,
+		// The first search a user creates stands in for their legacy prompt
+		IsDefault: len(existing) == 0,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if input.Name == nil {
+		return nil, usererr.ErrInvalidSearchName
+	}
+	if input.AIPrompt == nil {
+		return nil, usererr.ErrInvalidSearchPrompt
+	}
+	if err := applySearchInput(search, input, existing); err != nil {
+		return nil, err
+	}
+
+	if err := s.searchRepo.Create(ctx, search); err != nil {
+		return nil, err
+	}
+	if err := s.mirrorDefault(ctx, search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+func (s *SavedSearchService) Update(ctx context.Context, userID, searchID uuid.UUID, input SavedSearchInput) (*model.SavedSearch, error) {
+	existing, err := s.searchRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var search *model.SavedSearch
+	others := make([]model.SavedSearch, 0, len(existing))
+	for i := range existing {
+		if existing[i].ID == searchID {
+			search = &existing[i]
+			continue
+		}
+		others = append(others, existing[i])
+	}
+	if search == nil {
+		return nil, usererr.ErrSavedSearchNotFound
+	}
+
+	if err := applySearchInput(search, input, others); err != nil {
+		return nil, err
+	}
+	search.UpdatedAt = time.Now()
+
+	updated, err := s.searchRepo.Update(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, usererr.ErrSavedSearchNotFound
+	}
+	if err := s.mirrorDefault(ctx, search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+// Delete removes a search along with its matches. Deleting the default search clears the legacy prompt.
+func (s *SavedSearchService) Delete(ctx context.Context, userID, searchID uuid.UUID) error {
+	search, err := s.Get(ctx, userID, searchID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.searchRepo.Delete(ctx, userID, searchID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return usererr.ErrSavedSearchNotFound
+	}
+	if search.IsDefault {
+		return s.userRepo.UpdateAIPrompt(ctx, userID, "")
+	}
+	return nil
+}
+
+// GetMatches returns the matches of one of the user's searches, best first
+func (s *SavedSearchService) GetMatches(ctx context.Context, userID, searchID uuid.UUID) ([]model.UserJobMatch, error) {
+	if _, err := s.Get(ctx, userID, searchID); err != nil {
+		return nil, err
+	}
+	return s.matchRepo.GetBySavedSearchID(ctx, userID, searchID)
+}
+
+// GetPromptHistory returns the prompts one of the user's searches has had, newest first
+func (s *SavedSearchService) GetPromptHistory(ctx context.Context, userID, searchID uuid.UUID) ([]model.PromptRevision, error) {
+	if _, err := s.Get(ctx, userID, searchID); err != nil {
+		return nil, err
+	}
+	return s.searchRepo.GetRevisions(ctx, searchID, maxPromptRevisions)
+}
+
+// mirrorDefault keeps GET /me and the legacy prompt endpoints in step with the default search
+func (s *SavedSearchService) mirrorDefault(ctx context.Context, search *model.SavedSearch) error {
+	if !search.IsDefault {
+		return nil
+	}
+	if err := s.userRepo.UpdateAIPrompt(ctx, search.UserID, search.AIPrompt); err != nil {
+		return err
+	}
+	return s.userRepo.UpdateNotifyThreshold(ctx, search.UserID, search.NotifyThreshold)
+}
+
+// applySearchInput validates input and copies it onto search. others are the user's other searches,
+// whose names must stay unique.
+func applySearchInput(search *model.SavedSearch, input SavedSearchInput, others []model.SavedSearch) error {
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" || utf8.RuneCountInString(name) > maxSearchNameLength {
+			return usererr.ErrInvalidSearchName
+		}
+		for _, other := range others {
+			if strings.EqualFold(other.Name, name) {
+				return usererr.ErrSearchNameTaken
+			}
+		}
+		search.Name = name
+	}
+	if input.AIPrompt != nil {
+		prompt := strings.TrimSpace(*input.AIPrompt)
+		if prompt == "" {
+			return usererr.ErrInvalidSearchPrompt
+		}
+		search.AIPrompt = prompt
+	}
+	if input.Filters != nil {
+		filters, err := normalizeFilters(*input.Filters)
+		if err != nil {
+			return err
+		}
+		search.Filters = filters
+	}
+	if input.NotifyThreshold != nil {
+		if *input.NotifyThreshold < 0 || *input.NotifyThreshold > 100 {
+			return usererr.ErrInvalidThreshold
+		}
+		search.NotifyThreshold = *input.NotifyThreshold
+	}
+	if input.Channels != nil {
+		channels, err := normalizeChannels(input.Channels)
+		if err != nil {
+			return err
+		}
+		search.Channels = channels
+	}
+	if input.Active != nil {
+		search.Active = *input.Active
+	}
+	return nil
+}
+
+// normalizeChannels validates channels and removes duplicates. An empty list keeps
+// notifications in the app only.
+func normalizeChannels(channels []string) ([]string, error) {
+	seen := make(map[string]bool, len(channels))
+	normalized := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		if !model.ValidChannel(channel) {
+			return nil, usererr.ErrInvalidChannel
+		}
+		if !seen[channel] {
+			seen[channel] = true
+			normalized = append(normalized, channel)
+		}
+	}
+	return normalized, nil
+}
+
+// normalizeFilters trims terms, drops empty ones and geocodes the radius filter's place
+func normalizeFilters(f model.SearchFilters) (model.SearchFilters, error) {
+	normalized := model.SearchFilters{
+		Keywords:         trimTerms(f.Keywords),
+		ExcludeKeywords:  trimTerms(f.ExcludeKeywords),
+		Companies:        trimTerms(f.Companies),
+		ExcludeCompanies: trimTerms(f.ExcludeCompanies),
+		Locations:        trimTerms(f.Locations),
+		RemoteOnly:       f.RemoteOnly,
+	}
+
+	near := strings.TrimSpace(f.Near)
+	if near == "" {
+		return normalized, nil
+	}
+	point, ok := geo.Geocode(near)
+	if !ok {
+		return normalized, usererr.ErrUnknownLocation
+	}
+	radius := f.RadiusKm
+	if radius == 0 {
+		radius = defaultRadiusKm
+	}
+	if radius < 0 || radius > maxRadiusKm {
+		return normalized, usererr.ErrInvalidRadius
+	}
+	normalized.Near, normalized.NearPoint = near, &point
+	normalized.RadiusKm, normalized.IncludeRemote = radius, f.IncludeRemote
+	return normalized, nil
+}
+
+func trimTerms(terms []string) []string {
+	var trimmed []string
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term != "" {
+			trimmed = append(trimmed, term)
+		}
+	}
+	return trimmed
+}
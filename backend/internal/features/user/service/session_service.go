@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jobping/backend/internal/features/user/model"
+	"github.com/jobping/backend/internal/features/user/repository"
+	"github.com/jobping/backend/internal/features/user/usererr"
+)
+
+// ClientInfo describes the device a session was started or refreshed from
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// SessionService issues and rotates refresh tokens. Only a SHA-256 hash of each token is stored.
+type SessionService struct {
+	sessionRepo repository.SessionRepository
+	refreshTTL  time.Duration
+}
+
+func NewSessionService(sessionRepo repository.SessionRepository, refreshTTL time.Duration) *SessionService {
+	return &SessionService{
+		sessionRepo: sessionRepo,
+		refreshTTL:  refreshTTL,
+	}
+}
+
+// Start begins a new session family for a fresh login and returns it with its raw refresh token
+func (s *SessionService) Start(ctx context.Context, userID uuid.UUID, client ClientInfo) (*model.Session, string, error) {
+	now := time.Now()
+	return s.issue(ctx, userID, uuid.New(), now, now, client)
+}
+
+// Refresh exchanges a refresh token for a new one in the same family. Presenting a token that was
+// already rotated means it leaked or was replayed, so the whole family is revoked.
+func (s *SessionService) Refresh(ctx context.Context, rawToken string, client ClientInfo) (*model.Session, string, error) {
+	tokenHash := hashToken(rawToken)
+	now := time.Now()
+
+	current, err := s.sessionRepo.MarkRotated(ctx, tokenHash, now)
+	if err != nil {
+		return nil, "", err
+	}
+	if current == nil {
+		return nil, "", s.rejectRefresh(ctx, tokenHash)
+	}
+
+	return s.issue(ctx, current.UserID, current.FamilyID, current.StartedAt, now, client)
+}
+
+func (s *SessionService) rejectRefresh(ctx context.Context, tokenHash string) error {
+	session, err := s.sessionRepo.GetByTokenHash(ctx, tokenHash)
+	if err != nil {
+		return err
+	}
+	if session == nil || session.RevokedAt != nil || session.RotatedAt == nil {
+		return usererr.ErrInvalidRefreshToken
+	}
+
+	log.Printf("Refresh token reuse detected for user %s, revoking session family %s", session.UserID, session.FamilyID)
+	if _, err := s.sessionRepo.RevokeFamily(ctx, session.UserID, session.FamilyID); err != nil {
+		return err
+	}
+	return usererr.ErrRefreshTokenReused
+}
+
+// Logout revokes the session the refresh token belongs to. Unknown tokens are ignored.
+func (s *SessionService) Logout(ctx context.Context, rawToken string) error {
+	session, err := s.sessionRepo.GetByTokenHash(ctx, hashToken(rawToken))
+	if err != nil || session == nil {
+		return err
+	}
+	_, err = s.sessionRepo.RevokeFamily(ctx, session.UserID, session.FamilyID)
+	return err
+}
+
+// LogoutAll revokes every session of the user and returns how many were active
+func (s *SessionService) LogoutAll(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.sessionRepo.RevokeAllForUser(ctx, userID)
+}
+
+// GetSessions returns the user's active sessions, one per signed-in device
+func (s *SessionService) GetSessions(ctx context.Context, userID uuid.UUID) ([]model.Session, error) {
+	return s.sessionRepo.GetActiveByUserID(ctx, userID)
+}
+
+// RevokeSession ends one of the user's sessions by family ID
+func (s *SessionService) RevokeSession(ctx context.Context, userID, familyID uuid.UUID) error {
+	revoked, err := s.sessionRepo.RevokeFamily(ctx, userID, familyID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return usererr.ErrSessionNotFound
+	}
+	return nil
+}
+
+func (s *SessionService) issue(ctx context.Context, userID, familyID uuid.UUID, startedAt, now time.Time, client ClientInfo) (*model.Session, string, error) {
+	rawToken, err := newToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	session := &model.Session{
+		ID:        uuid.New(),
+		FamilyID:  familyID,
+		UserID:    userID,
+		TokenHash: hashToken(rawToken),
+		UserAgent: client.UserAgent,
+		IPAddress: client.IPAddress,
+		StartedAt: startedAt,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.refreshTTL),
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, "", err
+	}
+	return session, rawToken, nil
+}
+
+// newToken returns 32 random bytes, base64url encoded, for refresh and emailed tokens
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(rawToken string) string {
+	sum := sha256.Sum256([]byte(rawToken))
+	return hex.EncodeToString(sum[:])
+}
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"sync"
 	"time"
+	_ "time/tzdata" // validate timezones without relying on host zoneinfo
 
 	"github.com/google/uuid"
 	"github.com/jobping/backend/internal/features/user/model"
@@ -11,21 +13,52 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// dummyHash is compared against when a login has no password hash to check, so unknown
+// usernames take as long to reject as wrong passwords
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, err := bcrypt.GenerateFromPassword([]byte("timing-equalization"), bcrypt.DefaultCost)
+	if err != nil {
+		panic(err)
+	}
+	return hash
+})
+
 type UserService struct {
-	userRepo  repository.UserRepository
-	prefRepo  repository.PreferenceRepository
-	matchRepo repository.UserJobMatchRepository
+	userRepo   repository.UserRepository
+	prefRepo   repository.PreferenceRepository
+	matchRepo  repository.UserJobMatchRepository
+	searchRepo repository.SavedSearchRepository
+	guard      *LoginGuard
+	policy     *PasswordPolicy
 }
 
-func NewUserService(userRepo repository.UserRepository, prefRepo repository.PreferenceRepository, matchRepo repository.UserJobMatchRepository) *UserService {
+func NewUserService(
+	userRepo repository.UserRepository,
+	prefRepo repository.PreferenceRepository,
+	matchRepo repository.UserJobMatchRepository,
+	searchRepo repository.SavedSearchRepository,
+	guard *LoginGuard,
+	policy *PasswordPolicy,
+) *UserService {
 	return &UserService{
-		userRepo:  userRepo,
-		prefRepo:  prefRepo,
-		matchRepo: matchRepo,
+		userRepo:   userRepo,
+		prefRepo:   prefRepo,
+		matchRepo:  matchRepo,
+		searchRepo: searchRepo,
+		guard:      guard,
+		policy:     policy,
 	}
 }
 
-func (s *UserService) Register(ctx context.Context, username, password string) (*model.User, error) {
+// Register creates an account. email is optional; when given it is stored unverified.
+func (s *UserService) Register(ctx context.Context, username, password, email string, client ClientInfo) (*model.User, error) {
+	if err := s.guard.AllowRegister(ctx, username, client); err != nil {
+		return nil, err
+	}
+	if err := s.policy.Validate(password); err != nil {
+		return nil, err
+	}
+
 	existing, err := s.userRepo.GetUserByUsername(ctx, username)
 	if err != nil {
 		return nil, err
@@ -34,6 +67,22 @@ func (s *UserService) Register(ctx context.Context, username, password string) (
 		return nil, usererr.ErrUserAlreadyExists
 	}
 
+	var emailPtr *string
+	if email != "" {
+		normalized, err := NormalizeEmail(email)
+		if err != nil {
+			return nil, err
+		}
+		taken, err := s.userRepo.GetUserByEmail(ctx, normalized)
+		if err != nil {
+			return nil, err
+		}
+		if taken != nil {
+			return nil, usererr.ErrEmailAlreadyExists
+		}
+		emailPtr = &normalized
+	}
+
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, err
@@ -44,6 +93,7 @@ func (s *UserService) Register(ctx context.Context, username, password string) (
 		ID:           uuid.New(),
 		Username:     username,
 		PasswordHash: string(hash),
+		Email:        emailPtr,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
@@ -55,19 +105,40 @@ func (s *UserService) Register(ctx context.Context, username, password string) (
 	return newUser, nil
 }
 
-func (s *UserService) Authenticate(ctx context.Context, username, password string) (*model.User, error) {
+// Authenticate checks a username and password. It returns a *usererr.LockedError while the
+// username or client IP is locked out after repeated failures.
+func (s *UserService) Authenticate(ctx context.Context, username, password string, client ClientInfo) (*model.User, error) {
+	if err := s.guard.CheckLogin(ctx, username, client); err != nil {
+		return nil, err
+	}
+
 	userEntity, err := s.userRepo.GetUserByUsername(ctx, username)
 	if err != nil {
 		return nil, err
 	}
-	if userEntity == nil {
+	if userEntity == nil || userEntity.PasswordHash == "" {
+		// Spend the same bcrypt work as a real comparison so response times don't reveal the account
+		bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+		if userEntity == nil {
+			s.guard.LoginFailed(ctx, username, nil, "unknown_user", client)
+		} else {
+			s.guard.LoginFailed(ctx, username, &userEntity.ID, "no_password", client)
+		}
 		return nil, usererr.ErrInvalidCredentials
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(userEntity.PasswordHash), []byte(password)); err != nil {
+		s.guard.LoginFailed(ctx, username, &userEntity.ID, "wrong_password", client)
 		return nil, usererr.ErrInvalidCredentials
 	}
 
+	if err := s.guard.LoginSucceeded(ctx, username); err != nil {
+		return nil, err
+	}
+	// Checked only after the password so the disabled state isn't revealed to guessers
+	if userEntity.DisabledAt != nil {
+		return nil, usererr.ErrAccountDisabled
+	}
 	return userEntity, nil
 }
 
@@ -136,16 +207,66 @@ func (s *UserService) GetUserByID(ctx context.Context, userID uuid.UUID) (*model
 	return s.userRepo.GetUserByID(ctx, userID)
 }
 
+// GetActiveUser returns the user unless they were deleted or disabled
+func (s *UserService) GetActiveUser(ctx context.Context, userID uuid.UUID) (*model.User, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, usererr.ErrUserNotFound
+	}
+	if user.DisabledAt != nil {
+		return nil, usererr.ErrAccountDisabled
+	}
+	return user, nil
+}
+
+// UpdateAIPrompt sets the prompt of the user's default saved search
 func (s *UserService) UpdateAIPrompt(ctx context.Context, userID uuid.UUID, prompt string) error {
-	return s.userRepo.UpdateAIPrompt(ctx, userID, prompt)
+	if err := s.userRepo.UpdateAIPrompt(ctx, userID, prompt); err != nil {
+		return err
+	}
+	return s.searchRepo.SyncDefault(ctx, userID)
 }
 
 func (s *UserService) UpdateDiscordWebhook(ctx context.Context, userID uuid.UUID, webhook string) error {
 	return s.userRepo.UpdateDiscordWebhook(ctx, userID, webhook)
 }
 
+func (s *UserService) UpdateSlackWebhook(ctx context.Context, userID uuid.UUID, webhook string) error {
+	return s.userRepo.UpdateSlackWebhook(ctx, userID, webhook)
+}
+
+// UpdateNotifyThreshold sets the threshold of the user's default saved search
 func (s *UserService) UpdateNotifyThreshold(ctx context.Context, userID uuid.UUID, threshold int) error {
-	return s.userRepo.UpdateNotifyThreshold(ctx, userID, threshold)
+	if err := s.userRepo.UpdateNotifyThreshold(ctx, userID, threshold); err != nil {
+		return err
+	}
+	return s.searchRepo.SyncDefault(ctx, userID)
+}
+
+// UpdateNotificationSettings validates and stores the user's timezone, quiet hours and hourly limit
+func (s *UserService) UpdateNotificationSettings(ctx context.Context, userID uuid.UUID, settings *model.NotificationSettings) error {
+	if settings.Timezone == "" {
+		settings.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(settings.Timezone); err != nil {
+		return usererr.ErrInvalidTimezone
+	}
+
+	if (settings.QuietHoursStart == nil) != (settings.QuietHoursEnd == nil) {
+		return usererr.ErrInvalidQuietHours
+	}
+	if settings.QuietHoursStart != nil && (!validHour(*settings.QuietHoursStart) || !validHour(*settings.QuietHoursEnd)) {
+		return usererr.ErrInvalidQuietHours
+	}
+
+	return s.userRepo.UpdateNotificationSettings(ctx, userID, settings)
+}
+
+func validHour(h int) bool {
+	return h >= 0 && h <= 23
 }
 
 func (s *UserService) GetUserMatches(ctx context.Context, userID uuid.UUID) ([]model.UserJobMatch, error) {
@@ -1,12 +1,90 @@
 package usererr
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
 	ErrUserNotFound       = errors.New("user not found")
 	ErrUserAlreadyExists  = errors.New("username already exists")
 	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrAccountDisabled    = errors.New("account is disabled")
 	ErrPreferenceNotFound = errors.New("preference not found")
 	ErrPreferenceExists   = errors.New("preference with this key already exists")
+	ErrInvalidTimezone    = errors.New("invalid timezone")
+	ErrInvalidQuietHours  = errors.New("quiet hours must both be set to an hour between 0 and 23, or both be empty")
+
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used; the session has been revoked")
+	ErrSessionNotFound     = errors.New("session not found")
+
+	ErrInvalidEmail         = errors.New("invalid email address")
+	ErrEmailAlreadyExists   = errors.New("email already in use")
+	ErrEmailNotSet          = errors.New("no email address on this account")
+	ErrEmailAlreadyVerified = errors.New("email address is already verified")
+	ErrInvalidToken         = errors.New("invalid or expired token")
+
+	ErrUnknownProvider   = errors.New("unknown login provider")
+	ErrInvalidLoginState = errors.New("invalid or expired login state")
+	ErrIdentityLinked    = errors.New("this external account is linked to another user")
+	ErrIdentityNotFound  = errors.New("linked identity not found")
+	ErrLastLoginMethod   = errors.New("cannot unlink the only way to sign in; set a password first")
+	ErrEmailNeedsLinking = errors.New("an account with this email exists; sign in and link this provider from your profile")
+
+	ErrWeakPassword    = errors.New("password does not meet the password policy")
+	ErrTooManyAttempts = errors.New("too many attempts, try again later")
+
+	ErrInvalidTokenName = errors.New("token name must be 1-100 characters")
+	ErrInvalidScope     = errors.New("unknown or missing token scope")
+	ErrInvalidExpiry    = errors.New("token expiry must be between 1 and 365 days")
+	ErrTooManyAPITokens = errors.New("too many active API tokens; revoke one first")
+	ErrAPITokenNotFound = errors.New("API token not found")
+
+	ErrDeletionNotConfirmed = errors.New("confirm deletion with your username and, if the account has one, your password")
+	ErrDeletionScheduled    = errors.New("account deletion is already scheduled")
+	ErrDeletionNotScheduled = errors.New("account deletion is not scheduled")
+
+	ErrSavedSearchNotFound  = errors.New("saved search not found")
+	ErrInvalidSearchName    = errors.New("saved search name must be 1-100 characters")
+	ErrSearchNameTaken      = errors.New("a saved search with this name already exists")
+	ErrInvalidSearchPrompt  = errors.New("saved search prompt is required")
+	ErrInvalidThreshold     = errors.New("threshold must be between 0 and 100")
+	ErrInvalidChannel       = errors.New("unknown notification channel")
+	ErrTooManySavedSearches = errors.New("too many saved searches; delete one first")
+	ErrUnknownLocation      = errors.New("unknown location; try a city such as \"Austin, TX\"")
+	ErrInvalidRadius        = errors.New("radius_km must be between 0 and 500")
+
+	ErrInvalidRescoreDays = errors.New("days must be between 1 and 30")
+	ErrSearchInactive     = errors.New("saved search is inactive")
+	ErrRescoreInProgress  = errors.New("a rescore of this saved search is already running")
+	ErrRescoreNotFound    = errors.New("rescore not found")
+	ErrRescoreUnavailable = errors.New("rescoring is not available: the user analysis queue is not configured")
+
+	ErrResumeMissing        = errors.New("no resume file in the request")
+	ErrResumeTooLarge       = errors.New("resume is larger than 4 MB")
+	ErrUnsupportedResume    = errors.New("unsupported resume format; upload a PDF, DOCX or plain text file")
+	ErrResumeUnreadable     = errors.New("no readable text found in the resume; scanned PDFs are not supported")
+	ErrResumeNotFound       = errors.New("no resume uploaded")
+	ErrResumeAnalysisFailed = errors.New("could not analyze the resume, try again later")
+
+	ErrEvaluationInputMissing = errors.New("provide a job_url, or a title and description")
+	ErrInvalidJobURL          = errors.New("job_url must be an absolute http or https URL")
+	ErrJobPageUnreadable      = errors.New("could not read a job posting from the URL; paste the title and description instead")
+	ErrEvaluationTimeout      = errors.New("evaluation timed out, try again later")
+	ErrEvaluationFailed       = errors.New("could not evaluate the job, try again later")
 )
 
+// LockedError is returned while login, registration or job evaluation is rate limited. It matches
+// ErrTooManyAttempts.
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return ErrTooManyAttempts.Error()
+}
+
+func (e *LockedError) Unwrap() error {
+	return ErrTooManyAttempts
+}
@@ -18,14 +18,17 @@ func NewSQSHandler(svc *service.UserAnalysisService) *SQSHandler {
 	return &SQSHandler{service: svc}
 }
 
-// HandleSQSEvent processes SQS messages containing job_id and user_id
+// HandleSQSEvent processes SQS messages containing job_id, user_id and saved_search_id.
+// Messages queued by a rescore also carry rescore_id, and count towards that run's progress.
 func (h *SQSHandler) HandleSQSEvent(ctx context.Context, sqsEvent events.SQSEvent) error {
 	for _, record := range sqsEvent.Records {
 		log.Printf("Processing SQS message: %s", record.MessageId)
 
 		var message struct {
-			JobID  string `json:"job_id"`
-			UserID string `json:"user_id"`
+			JobID         string `json:"job_id"`
+			UserID        string `json:"user_id"`
+			SavedSearchID string `json:"saved_search_id"`
+			RescoreID     string `json:"rescore_id"`
 		}
 		if err := json.Unmarshal([]byte(record.Body), &message); err != nil {
 			log.Printf("Failed to parse SQS message: %v", err)
@@ -44,7 +47,29 @@ func (h *SQSHandler) HandleSQSEvent(ctx context.Context, sqsEvent events.SQSEven
 			continue
 		}
 
-		if err := h.service.AnalyzeUserMatch(ctx, jobID, userID); err != nil {
+		// Messages queued before saved searches have no saved_search_id
+		var searchID uuid.UUID
+		if message.SavedSearchID != "" {
+			searchID, err = uuid.Parse(message.SavedSearchID)
+			if err != nil {
+				log.Printf("Invalid saved_search_id in message: %v", err)
+				continue
+			}
+		}
+
+		var rescoreID uuid.UUID
+		if message.RescoreID != "" {
+			rescoreID, err = uuid.Parse(message.RescoreID)
+			if err != nil {
+				log.Printf("Invalid rescore_id in message: %v", err)
+			}
+		}
+
+		err = h.service.AnalyzeUserMatch(ctx, jobID, userID, searchID)
+		if rescoreID != uuid.Nil {
+			h.service.RecordRescoreProgress(ctx, rescoreID, err)
+		}
+		if err != nil {
 			log.Printf("Failed to analyze user match: %v", err)
 			continue
 		}
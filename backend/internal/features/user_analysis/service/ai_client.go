@@ -7,11 +7,12 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 )
 
 // AIClient interface for AI matching
 type AIClient interface {
-	MatchJobToUser(ctx context.Context, job *JobMatchInput, userPrompt string) (*UserMatchResult, error)
+	MatchJobToUser(ctx context.Context, job *JobMatchInput, user *UserMatchInput) (*UserMatchResult, error)
 }
 
 type JobMatchInput struct {
@@ -21,6 +22,14 @@ type JobMatchInput struct {
 	CompanyInfo map[string]interface{}
 }
 
+// UserMatchInput is what a job is matched against: the saved search's prompt, plus the
+// skills and seniority from the user's resume when they uploaded one
+type UserMatchInput struct {
+	Prompt    string
+	Skills    []string
+	Seniority string
+}
+
 type UserMatchResult struct {
 	Score       int                    `json:"score"`
 	Explanation string                 `json:"explanation"`
@@ -59,7 +68,7 @@ type openAIResponse struct {
 	} `json:"choices"`
 }
 
-func (c *openAIClient) MatchJobToUser(ctx context.Context, job *JobMatchInput, userPrompt string) (*UserMatchResult, error) {
+func (c *openAIClient) MatchJobToUser(ctx context.Context, job *JobMatchInput, user *UserMatchInput) (*UserMatchResult, error) {
 	if c.apiKey == "" {
 		// Return mock result if no API key
 		return &UserMatchResult{
@@ -79,11 +88,22 @@ func (c *openAIClient) MatchJobToUser(ctx context.Context, job *JobMatchInput, u
 		companyInfoStr = string(infoBytes)
 	}
 
+	profileStr := ""
+	if len(user.Skills) > 0 || user.Seniority != "" {
+		profileStr = "\nCANDIDATE PROFILE (from their resume):\n"
+		if user.Seniority != "" {
+			profileStr += "Seniority: " + user.Seniority + "\n"
+		}
+		if len(user.Skills) > 0 {
+			profileStr += "Skills: " + strings.Join(user.Skills, ", ") + "\n"
+		}
+	}
+
 	prompt := fmt.Sprintf(`Match this job to a user's preferences and provide a compatibility score.
 
 USER'S PREFERENCES/IDEAL JOB:
 %s
-
+%s
 JOB DETAILS:
 Title: %s
 Company: %s
@@ -99,7 +119,7 @@ Analyze how well this job matches the user's preferences. Provide a JSON respons
   "pros": ["reasons this job is a good fit"],
   "cons": ["reasons this job might not be ideal"],
   "key_match_factors": ["specific factors from user preferences that match"]
-}`, userPrompt, job.Title, job.Company, truncate(job.Description, 800), companyInfoStr)
+}`, user.Prompt, profileStr, job.Title, job.Company, truncate(job.Description, 800), companyInfoStr)
 
 	result, err := c.callOpenAI(ctx, prompt, "You are a job matching assistant. Be honest and balanced in your analysis.")
 	if err != nil {
@@ -11,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/google/uuid"
+	jobmodel "github.com/jobping/backend/internal/features/job/model"
 	"github.com/jobping/backend/internal/features/job/repository"
 	usermodel "github.com/jobping/backend/internal/features/user/model"
 	userrepo "github.com/jobping/backend/internal/features/user/repository"
@@ -18,14 +19,18 @@ import (
 
 type UserAnalysisService struct {
 	jobRepo          repository.JobRepository
+	events           repository.PipelineEventRepository
 	userRepo         userrepo.UserRepository
 	matchRepo        userrepo.UserJobMatchRepository
+	searchRepo       userrepo.SavedSearchRepository
+	rescoreRepo      userrepo.RescoreRepository
+	resumeRepo       userrepo.ResumeRepository
 	aiClient         AIClient
 	notificationQueueURL string
 	sqsClient        *sqs.Client
 }
 
-func NewUserAnalysisService(jobRepo repository.JobRepository, userRepo userrepo.UserRepository, matchRepo userrepo.UserJobMatchRepository, aiClient AIClient) *UserAnalysisService {
+func NewUserAnalysisService(jobRepo repository.JobRepository, events repository.PipelineEventRepository, userRepo userrepo.UserRepository, matchRepo userrepo.UserJobMatchRepository, searchRepo userrepo.SavedSearchRepository, rescoreRepo userrepo.RescoreRepository, resumeRepo userrepo.ResumeRepository, aiClient AIClient) *UserAnalysisService {
 	notificationQueueURL := os.Getenv("NOTIFICATION_QUEUE_URL")
 	
 	var sqsClient *sqs.Client
@@ -38,16 +43,22 @@ func NewUserAnalysisService(jobRepo repository.JobRepository, userRepo userrepo.
 
 	return &UserAnalysisService{
 		jobRepo:              jobRepo,
+		events:               events,
 		userRepo:             userRepo,
 		matchRepo:            matchRepo,
+		searchRepo:           searchRepo,
+		rescoreRepo:          rescoreRepo,
+		resumeRepo:           resumeRepo,
 		aiClient:             aiClient,
 		notificationQueueURL: notificationQueueURL,
 		sqsClient:            sqsClient,
 	}
 }
 
-// AnalyzeUserMatch analyzes if a job matches a user and creates a match record if positive
-func (s *UserAnalysisService) AnalyzeUserMatch(ctx context.Context, jobID, userID uuid.UUID) error {
+// AnalyzeUserMatch analyzes if a job matches one of a user's saved searches and creates a match record.
+// A nil searchID (messages queued before saved searches) uses the user's default search.
+// An existing match scored against an older prompt revision is re-scored in place.
+func (s *UserAnalysisService) AnalyzeUserMatch(ctx context.Context, jobID, userID, searchID uuid.UUID) error {
 	// Fetch job
 	job, err := s.jobRepo.GetByID(ctx, jobID)
 	if err != nil {
@@ -68,22 +79,31 @@ func (s *UserAnalysisService) AnalyzeUserMatch(ctx context.Context, jobID, userI
 		return nil
 	}
 
-	if user.AIPrompt == nil || *user.AIPrompt == "" {
-		log.Printf("User %s has no AI prompt, skipping", userID)
+	// Fetch saved search
+	search, err := s.savedSearch(ctx, userID, searchID)
+	if err != nil {
+		return err
+	}
+	if search == nil || !search.Active || search.AIPrompt == "" {
+		log.Printf("User %s has no active saved search %s, skipping", userID, searchID)
+		s.events.RecordBestEffort(ctx, pipelineEvent(jobID, userID, searchID, jobmodel.PipelineOutcomeSkipped,
+			map[string]interface{}{"reason": "search_inactive"}))
 		return nil
 	}
 
 	// Check if already matched
-	existing, err := s.matchRepo.GetByUserAndJob(ctx, userID, jobID)
+	existing, err := s.matchRepo.GetBySearchAndJob(ctx, search.ID, jobID)
 	if err != nil {
 		log.Printf("Failed to check existing match: %v", err)
 		return err
 	}
-	if existing != nil {
-		log.Printf("Match already exists for user %s and job %s", userID, jobID)
+	if existing != nil && !promptChanged(existing, search) {
+		log.Printf("Match already exists for saved search %s and job %s", search.ID, jobID)
+		s.events.RecordBestEffort(ctx, pipelineEvent(jobID, userID, search.ID, jobmodel.PipelineOutcomeSkipped,
+			map[string]interface{}{"reason": "already_matched", "score": existing.Score}))
 		// Still enqueue to notification if not notified and score >= threshold
-		if !existing.Notified && existing.Score >= user.NotifyThreshold {
-			return s.enqueueToNotification(ctx, jobID, userID)
+		if !existing.Notified && existing.Score >= search.NotifyThreshold {
+			return s.enqueueToNotification(ctx, jobID, userID, search.ID)
 		}
 		return nil
 	}
@@ -96,47 +116,120 @@ func (s *UserAnalysisService) AnalyzeUserMatch(ctx context.Context, jobID, userI
 		CompanyInfo: job.CompanyInfo,
 	}
 
-	matchResult, err := s.aiClient.MatchJobToUser(ctx, matchInput, *user.AIPrompt)
+	userInput := &UserMatchInput{Prompt: search.AIPrompt}
+	profile, err := s.resumeRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if profile != nil {
+		userInput.Skills = profile.Skills
+		userInput.Seniority = profile.Seniority
+	}
+
+	matchResult, err := s.aiClient.MatchJobToUser(ctx, matchInput, userInput)
 	if err != nil {
 		log.Printf("Failed to match job to user %s: %v", user.Username, err)
+		event := pipelineEvent(jobID, userID, search.ID, jobmodel.PipelineOutcomeFailed, nil)
+		event.Error = err.Error()
+		s.events.RecordBestEffort(ctx, event)
 		return err
 	}
 
 	// Store match result
 	match := &usermodel.UserJobMatch{
-		ID:        uuid.New(),
-		UserID:    userID,
-		JobID:     jobID,
-		Score:     matchResult.Score,
-		Analysis:  matchResult.Analysis,
-		Notified:  false,
-		CreatedAt: time.Now(),
+		ID:               uuid.New(),
+		UserID:           userID,
+		SavedSearchID:    search.ID,
+		JobID:            jobID,
+		Score:            matchResult.Score,
+		Analysis:         matchResult.Analysis,
+		Notified:         false,
+		PromptRevisionID: search.PromptRevisionID,
+		CreatedAt:        time.Now(),
+	}
+	if existing != nil {
+		// Re-scored: the upsert keeps the row, so keep its notified state too
+		match.Notified = existing.Notified
 	}
 
 	if err := s.matchRepo.Create(ctx, match); err != nil {
 		log.Printf("Failed to save match: %v", err)
+		event := pipelineEvent(jobID, userID, search.ID, jobmodel.PipelineOutcomeFailed, map[string]interface{}{"score": matchResult.Score})
+		event.Error = err.Error()
+		s.events.RecordBestEffort(ctx, event)
 		return err
 	}
 
-	log.Printf("Matched job %s to user %s with score %d", job.Title, user.Username, matchResult.Score)
+	log.Printf("Matched job %s to user %s (search %q) with score %d", job.Title, user.Username, search.Name, matchResult.Score)
 
 	// If match score >= threshold, enqueue to notification
-	if matchResult.Score >= user.NotifyThreshold {
-		return s.enqueueToNotification(ctx, jobID, userID)
+	notify := !match.Notified && matchResult.Score >= search.NotifyThreshold
+	s.events.RecordBestEffort(ctx, pipelineEvent(jobID, userID, search.ID, jobmodel.PipelineOutcomeAnalyzed, map[string]interface{}{
+		"score":     matchResult.Score,
+		"threshold": search.NotifyThreshold,
+		"rescored":  existing != nil,
+		"notify":    notify,
+	}))
+	if notify {
+		return s.enqueueToNotification(ctx, jobID, userID, search.ID)
 	}
 
 	return nil
 }
 
-func (s *UserAnalysisService) enqueueToNotification(ctx context.Context, jobID, userID uuid.UUID) error {
+// pipelineEvent builds a user analysis event for the job's timeline
+func pipelineEvent(jobID, userID, searchID uuid.UUID, outcome string, details map[string]interface{}) jobmodel.PipelineEvent {
+	event := jobmodel.PipelineEvent{
+		JobID:   jobID,
+		UserID:  &userID,
+		Stage:   jobmodel.PipelineStageUserAnalysis,
+		Outcome: outcome,
+		Details: details,
+	}
+	if searchID != uuid.Nil {
+		event.SavedSearchID = &searchID
+	}
+	return event
+}
+
+// RecordRescoreProgress counts one job of a rescore run as analyzed, or as failed if err is set
+func (s *UserAnalysisService) RecordRescoreProgress(ctx context.Context, rescoreID uuid.UUID, err error) {
+	if recordErr := s.rescoreRepo.RecordProgress(ctx, rescoreID, err != nil); recordErr != nil {
+		log.Printf("Failed to record progress of rescore %s: %v", rescoreID, recordErr)
+	}
+}
+
+// promptChanged reports whether the match was scored against a different prompt than the
+// search has now. Searches without a revision (empty prompt) never trigger a re-score.
+func promptChanged(match *usermodel.UserJobMatch, search *usermodel.SavedSearch) bool {
+	if search.PromptRevisionID == nil {
+		return false
+	}
+	return match.PromptRevisionID == nil || *match.PromptRevisionID != *search.PromptRevisionID
+}
+
+// savedSearch returns the user's search with searchID, or their default search when searchID is nil
+func (s *UserAnalysisService) savedSearch(ctx context.Context, userID, searchID uuid.UUID) (*usermodel.SavedSearch, error) {
+	if searchID == uuid.Nil {
+		return s.searchRepo.GetDefault(ctx, userID)
+	}
+	search, err := s.searchRepo.GetByID(ctx, searchID)
+	if err != nil || search == nil || search.UserID != userID {
+		return nil, err
+	}
+	return search, nil
+}
+
+func (s *UserAnalysisService) enqueueToNotification(ctx context.Context, jobID, userID, searchID uuid.UUID) error {
 	if s.sqsClient == nil || s.notificationQueueURL == "" {
 		log.Printf("SQS not configured, skipping notification enqueue")
 		return nil
 	}
 
 	message := map[string]string{
-		"job_id":  jobID.String(),
-		"user_id": userID.String(),
+		"job_id":          jobID.String(),
+		"user_id":         userID.String(),
+		"saved_search_id": searchID.String(),
 	}
 	body, err := json.Marshal(message)
 	if err != nil {
@@ -10,16 +10,20 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/google/uuid"
+	jobmodel "github.com/jobping/backend/internal/features/job/model"
+	jobrepo "github.com/jobping/backend/internal/features/job/repository"
 	userrepo "github.com/jobping/backend/internal/features/user/repository"
 )
 
 type FanoutService struct {
-	userRepo         userrepo.UserRepository
+	searchRepo       userrepo.SavedSearchRepository
+	jobRepo          jobrepo.JobRepository
+	events           jobrepo.PipelineEventRepository
 	analysisQueueURL string
 	sqsClient        *sqs.Client
 }
 
-func NewFanoutService(userRepo userrepo.UserRepository) *FanoutService {
+func NewFanoutService(searchRepo userrepo.SavedSearchRepository, jobRepo jobrepo.JobRepository, events jobrepo.PipelineEventRepository) *FanoutService {
 	analysisQueueURL := os.Getenv("USER_ANALYSIS_QUEUE_URL")
 	
 	var sqsClient *sqs.Client
@@ -31,49 +35,92 @@ func NewFanoutService(userRepo userrepo.UserRepository) *FanoutService {
 	}
 
 	return &FanoutService{
-		userRepo:         userRepo,
+		searchRepo:       searchRepo,
+		jobRepo:          jobRepo,
+		events:           events,
 		analysisQueueURL: analysisQueueURL,
 		sqsClient:        sqsClient,
 	}
 }
 
-// FanoutToUsers fetches all users with AI prompts and enqueues job_id+user_id to user-analysis-queue
+// FanoutToUsers enqueues job_id+user_id+saved_search_id to user-analysis-queue for every
+// active saved search whose filters the job passes
 func (s *FanoutService) FanoutToUsers(ctx context.Context, jobID uuid.UUID) error {
-	// Fetch all users with AI prompts
-	users, err := s.userRepo.GetUsersWithPrompts(ctx)
+	job, err := s.jobRepo.GetByID(ctx, jobID)
 	if err != nil {
 		return err
 	}
+	if job == nil {
+		log.Printf("Job not found: %s", jobID)
+		return nil
+	}
+	if job.Lifecycle != jobmodel.JobLifecycleActive {
+		// Expired or closed while queued; nobody should be notified about it
+		log.Printf("Skipping fanout of %s job %s", job.Lifecycle, jobID)
+		s.events.RecordBestEffort(ctx, jobmodel.PipelineEvent{
+			JobID:   jobID,
+			Stage:   jobmodel.PipelineStageFanout,
+			Outcome: jobmodel.PipelineOutcomeSkipped,
+			Details: map[string]interface{}{"reason": "job_" + string(job.Lifecycle)},
+		})
+		return nil
+	}
 
-	log.Printf("Fanning out job %s to %d users", jobID, len(users))
+	// Fetch all active saved searches
+	searches, err := s.searchRepo.GetActive(ctx)
+	if err != nil {
+		s.events.RecordBestEffort(ctx, jobmodel.PipelineEvent{
+			JobID:   jobID,
+			Stage:   jobmodel.PipelineStageFanout,
+			Outcome: jobmodel.PipelineOutcomeFailed,
+			Error:   err.Error(),
+		})
+		return err
+	}
 
-	// Enqueue each user to user-analysis-queue
-	enqueued := 0
-	for _, user := range users {
-		if user.AIPrompt == nil || *user.AIPrompt == "" {
+	log.Printf("Fanning out job %s to %d saved searches", jobID, len(searches))
+
+	// Enqueue each search to user-analysis-queue; filters are checked here so filtered-out jobs cost no AI call
+	enqueued, filtered, failed := 0, 0, 0
+	for _, search := range searches {
+		if !search.Filters.Match(job.Title, job.Company, job.Location, job.Description, job.IsRemote, job.Place.Coords) {
+			filtered++
 			continue
 		}
 
-		if err := s.enqueueToAnalysis(ctx, jobID, user.ID); err != nil {
-			log.Printf("Failed to enqueue user %s: %v", user.ID, err)
+		if err := s.enqueueToAnalysis(ctx, jobID, search.UserID, search.ID); err != nil {
+			log.Printf("Failed to enqueue saved search %s: %v", search.ID, err)
+			failed++
 			continue
 		}
 		enqueued++
 	}
 
-	log.Printf("Enqueued job %s to %d users", jobID, enqueued)
+	log.Printf("Enqueued job %s to %d saved searches", jobID, enqueued)
+	s.events.RecordBestEffort(ctx, jobmodel.PipelineEvent{
+		JobID:   jobID,
+		Stage:   jobmodel.PipelineStageFanout,
+		Outcome: jobmodel.PipelineOutcomeFannedOut,
+		Details: map[string]interface{}{
+			"searches": len(searches),
+			"filtered": filtered,
+			"enqueued": enqueued,
+			"failed":   failed,
+		},
+	})
 	return nil
 }
 
-func (s *FanoutService) enqueueToAnalysis(ctx context.Context, jobID, userID uuid.UUID) error {
+func (s *FanoutService) enqueueToAnalysis(ctx context.Context, jobID, userID, searchID uuid.UUID) error {
 	if s.sqsClient == nil || s.analysisQueueURL == "" {
 		log.Printf("SQS not configured, skipping analysis enqueue")
 		return nil
 	}
 
 	message := map[string]string{
-		"job_id":  jobID.String(),
-		"user_id": userID.String(),
+		"job_id":          jobID.String(),
+		"user_id":         userID.String(),
+		"saved_search_id": searchID.String(),
 	}
 	body, err := json.Marshal(message)
 	if err != nil {
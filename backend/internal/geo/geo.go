@@ -0,0 +1,298 @@
+// Package geo parses free-text job locations such as "San Francisco, CA" or "Remote - US" into
+// city, region, country and workplace type, and geocodes cities against an embedded gazetteer,
+// so jobs can be searched by distance without calling a geocoding service.
+package geo
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Workplace types. Empty means the location does not say.
+const (
+	WorkplaceRemote = "remote"
+	WorkplaceHybrid = "hybrid"
+	WorkplaceOnsite = "onsite"
+)
+
+// ValidWorkplace reports whether w is one of the workplace types
+func ValidWorkplace(w string) bool {
+	switch w {
+	case WorkplaceRemote, WorkplaceHybrid, WorkplaceOnsite:
+		return true
+	}
+	return false
+}
+
+const earthRadiusKm = 6371.0
+
+// Point is a position in decimal degrees
+type Point struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// DistanceKm returns the great-circle distance between a and b
+func DistanceKm(a, b Point) float64 {
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+// Location is a parsed job location. Country is an ISO 3166-1 alpha-2 code; Region is a postal
+// code for US states and Canadian provinces. Coords is set only when the city is in the gazetteer.
+type Location struct {
+	City      string
+	Region    string
+	Country   string
+	Workplace string
+	Coords    *Point
+}
+
+var (
+	remotePattern = regexp.MustCompile(`(?i)\b(remote|work from home|wfh|anywhere|distributed)\b`)
+	hybridPattern = regexp.MustCompile(`(?i)\bhybrid\b`)
+	onsitePattern = regexp.MustCompile(`(?i)\b(on-?site|in-?office|in office)\b`)
+
+	// separatorPattern splits "Remote - US", "Hybrid (Austin, TX)" and "NYC / Boston" like commas
+	separatorPattern = regexp.MustCompile(`\s+[-–—]\s+|[()|/;•]`)
+	// noisePattern removes the words around a place name once the workplace type is known
+	noisePattern = regexp.MustCompile(`(?i)\b(remote|work from home|wfh|anywhere|distributed|hybrid|on-?site|in-?office|in office|based|only|first|friendly)\b`)
+	leadingIn    = regexp.MustCompile(`(?i)^(in|from|within)\s+`)
+	// metroPattern strips LinkedIn's "Greater Seattle Area" and "Los Angeles Metropolitan Area"
+	metroPattern = regexp.MustCompile(`(?i)^greater\s+|\s+(metropolitan|metro)?\s*area$`)
+)
+
+// Parse splits a raw location into its parts. It never fails: what it cannot place is left empty,
+// and an unknown city keeps its name without coordinates. A location naming a city or region is
+// taken as onsite unless it says otherwise; a country alone says nothing about the workplace.
+func Parse(raw string) Location {
+	var loc Location
+	switch {
+	case hybridPattern.MatchString(raw):
+		loc.Workplace = WorkplaceHybrid
+	case remotePattern.MatchString(raw):
+		loc.Workplace = WorkplaceRemote
+	case onsitePattern.MatchString(raw):
+		loc.Workplace = WorkplaceOnsite
+	}
+
+	tokens := placeTokens(raw)
+	if len(tokens) == 0 {
+		return loc
+	}
+	single := len(tokens) == 1
+
+	// The country comes last: "Austin, TX, US", "Berlin, Germany". A two-letter code that is also
+	// a region ("CA", "DE") is only a country in a three-part location.
+	countryToken := ""
+	if c, ok := lookupCountry(tokens[len(tokens)-1], len(tokens) >= 3); ok {
+		loc.Country = c.code
+		countryToken = tokens[len(tokens)-1]
+		tokens = tokens[:len(tokens)-1]
+	}
+
+	// Then the region, which also settles the country
+	regionToken := ""
+	if len(tokens) > 0 {
+		if r, ok := lookupRegion(tokens[len(tokens)-1], loc.Country); ok {
+			loc.Region, loc.Country = r.code, r.country
+			regionToken = tokens[len(tokens)-1]
+			tokens = tokens[:len(tokens)-1]
+		}
+	}
+
+	// What is left starts with the city. A lone region or country may also name a city:
+	// "New York", "Singapore".
+	cityToken := ""
+	switch {
+	case len(tokens) > 0:
+		cityToken = tokens[0]
+	case single && regionToken != "":
+		cityToken = regionToken
+	case single && countryToken != "":
+		cityToken = countryToken
+	}
+	c, ok := lookupCity(cityToken, loc.Region, loc.Country)
+	if !ok && regionToken != "" && len(tokens) > 0 {
+		// "Berlin, DE": the region was really a country code
+		if country, isCountry := lookupCountry(regionToken, true); isCountry {
+			if c, ok = lookupCity(cityToken, "", country.code); ok {
+				loc.Region = ""
+			}
+		}
+	}
+	switch {
+	case ok:
+		loc.City, loc.Country = c.name, c.country
+		if c.region != "" {
+			loc.Region = c.region
+		}
+		loc.Coords = &Point{Latitude: c.lat, Longitude: c.lon}
+	case len(tokens) > 0:
+		loc.City = cityToken
+	}
+	if loc.Workplace == "" && (loc.City != "" || loc.Region != "") {
+		loc.Workplace = WorkplaceOnsite
+	}
+	return loc
+}
+
+// Geocode resolves a place typed by a user, such as "Austin, TX" or "Berlin", to its coordinates
+func Geocode(place string) (Point, bool) {
+	loc := Parse(place)
+	if loc.Coords == nil {
+		return Point{}, false
+	}
+	return *loc.Coords, true
+}
+
+// placeTokens splits raw into comma-separated parts with the workplace words removed
+func placeTokens(raw string) []string {
+	raw = separatorPattern.ReplaceAllString(raw, ",")
+	var tokens []string
+	for _, part := range strings.Split(raw, ",") {
+		part = noisePattern.ReplaceAllString(part, " ")
+		part = strings.Join(strings.Fields(part), " ")
+		part = strings.Trim(leadingIn.ReplaceAllString(part, ""), " .-:")
+		if part != "" {
+			tokens = append(tokens, part)
+		}
+	}
+	return tokens
+}
+
+type country struct {
+	code string
+	name string
+}
+
+type region struct {
+	code    string
+	name    string
+	country string
+}
+
+type city struct {
+	name    string
+	region  string
+	country string
+	lat     float64
+	lon     float64
+}
+
+//go:embed gazetteer.csv
+var gazetteerCSV string
+
+var (
+	countries = map[string]country{}
+	// regionCodes holds only the postal codes, to tell "CA" the state from "CA" the country
+	regionCodes = map[string]bool{}
+	regions     = map[string][]region{}
+	cities      = map[string][]city{}
+)
+
+func init() {
+	if err := loadGazetteer(gazetteerCSV); err != nil {
+		panic(err)
+	}
+}
+
+func loadGazetteer(data string) error {
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 7
+	records, err := reader.ReadAll()
+	if err != nil {
+		return fmt.Errorf("geo: reading gazetteer: %w", err)
+	}
+
+	for _, rec := range records {
+		kind, name, code, countryCode := rec[0], rec[1], rec[2], rec[3]
+		keys := []string{key(name)}
+		for _, alias := range strings.Split(rec[6], "|") {
+			if alias != "" {
+				keys = append(keys, key(alias))
+			}
+		}
+
+		switch kind {
+		case "country":
+			c := country{code: code, name: name}
+			countries[key(code)] = c
+			for _, k := range keys {
+				countries[k] = c
+			}
+		case "region":
+			r := region{code: code, name: name, country: countryCode}
+			regionCodes[key(code)] = true
+			for _, k := range append(keys, key(code)) {
+				regions[k] = append(regions[k], r)
+			}
+		case "city":
+			lat, latErr := strconv.ParseFloat(rec[4], 64)
+			lon, lonErr := strconv.ParseFloat(rec[5], 64)
+			if latErr != nil || lonErr != nil {
+				return fmt.Errorf("geo: bad coordinates for %s", name)
+			}
+			c := city{name: name, region: code, country: countryCode, lat: lat, lon: lon}
+			for _, k := range keys {
+				cities[k] = append(cities[k], c)
+			}
+		default:
+			return fmt.Errorf("geo: unknown gazetteer entry kind %q", kind)
+		}
+	}
+	return nil
+}
+
+// lookupCountry matches country names and aliases, and two-letter codes unless they are also a
+// region code and allowAmbiguous is false
+func lookupCountry(token string, allowAmbiguous bool) (country, bool) {
+	k := key(token)
+	c, ok := countries[k]
+	if !ok || (k == key(c.code) && regionCodes[k] && !allowAmbiguous) {
+		return country{}, false
+	}
+	return c, true
+}
+
+// lookupRegion matches region names and codes, within countryCode when it is known
+func lookupRegion(token, countryCode string) (region, bool) {
+	for _, r := range regions[key(token)] {
+		if countryCode == "" || r.country == countryCode {
+			return r, true
+		}
+	}
+	return region{}, false
+}
+
+// lookupCity finds a city by name or alias. A known region or country must match; without
+// either, the first (most prominent) city of that name wins.
+func lookupCity(token, regionCode, countryCode string) (city, bool) {
+	candidates, ok := cities[key(token)]
+	if !ok {
+		candidates = cities[key(metroPattern.ReplaceAllString(token, ""))]
+	}
+	for _, c := range candidates {
+		if regionCode != "" && c.region != regionCode {
+			continue
+		}
+		if countryCode != "" && c.country != countryCode {
+			continue
+		}
+		return c, true
+	}
+	return city{}, false
+}
+
+func key(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
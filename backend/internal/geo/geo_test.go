@@ -0,0 +1,91 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		raw       string
+		city      string
+		region    string
+		country   string
+		workplace string
+		coords    bool
+	}{
+		{"San Francisco, CA", "San Francisco", "CA", "US", WorkplaceOnsite, true},
+		{"Austin, TX, US", "Austin", "TX", "US", WorkplaceOnsite, true},
+		{"Portland, ME", "Portland", "ME", "US", WorkplaceOnsite, true},
+		{"Portland", "Portland", "OR", "US", WorkplaceOnsite, true},
+		{"Toronto, ON, CA", "Toronto", "ON", "CA", WorkplaceOnsite, true},
+		{"Berlin, Germany", "Berlin", "", "DE", WorkplaceOnsite, true},
+		// A two-letter region code that is really a country code
+		{"Berlin, DE", "Berlin", "", "DE", WorkplaceOnsite, true},
+		{"NYC", "New York", "NY", "US", WorkplaceOnsite, true},
+		{"Greater Seattle Area", "Seattle", "WA", "US", WorkplaceOnsite, true},
+		{"Singapore", "Singapore", "", "SG", WorkplaceOnsite, true},
+		// Workplace types and the words around the place
+		{"Remote - US", "", "", "US", WorkplaceRemote, false},
+		{"Remote", "", "", "", WorkplaceRemote, false},
+		{"Hybrid (Austin, TX)", "Austin", "TX", "US", WorkplaceHybrid, true},
+		{"On-site in London, UK", "London", "", "GB", WorkplaceOnsite, true},
+		{"California", "", "CA", "US", WorkplaceOnsite, false},
+		// A country alone says nothing about the workplace
+		{"Germany", "", "", "DE", "", false},
+		// Unknown cities keep their name without coordinates
+		{"Springfield, Nowhere", "Springfield", "", "", WorkplaceOnsite, false},
+		{"", "", "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			loc := Parse(tt.raw)
+			if loc.City != tt.city || loc.Region != tt.region || loc.Country != tt.country || loc.Workplace != tt.workplace {
+				t.Errorf("Parse = %q/%q/%q/%q, want %q/%q/%q/%q",
+					loc.City, loc.Region, loc.Country, loc.Workplace, tt.city, tt.region, tt.country, tt.workplace)
+			}
+			if (loc.Coords != nil) != tt.coords {
+				t.Errorf("coords = %v, want coords %v", loc.Coords, tt.coords)
+			}
+		})
+	}
+}
+
+func TestDistanceKm(t *testing.T) {
+	sf, _ := Geocode("San Francisco, CA")
+	la, _ := Geocode("Los Angeles, CA")
+	london, _ := Geocode("London, UK")
+	paris, _ := Geocode("Paris, France")
+
+	tests := []struct {
+		name string
+		a, b Point
+		want float64
+	}{
+		{"same point", sf, sf, 0},
+		{"San Francisco to Los Angeles", sf, la, 559},
+		{"London to Paris", london, paris, 344},
+		{"quarter of the equator", Point{0, 0}, Point{0, 90}, math.Pi * earthRadiusKm / 2},
+		{"pole to pole", Point{90, 0}, Point{-90, 0}, math.Pi * earthRadiusKm},
+		{"across the antimeridian", Point{0, 179.5}, Point{0, -179.5}, 111.2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DistanceKm(tt.a, tt.b)
+			if math.Abs(got-tt.want) > 1 {
+				t.Errorf("DistanceKm = %.1f, want %.1f", got, tt.want)
+			}
+			if back := DistanceKm(tt.b, tt.a); math.Abs(back-got) > 1e-9 {
+				t.Errorf("DistanceKm is not symmetric: %.3f and %.3f", got, back)
+			}
+		})
+	}
+}
+
+func TestGeocodeUnknownPlace(t *testing.T) {
+	if p, ok := Geocode("Atlantis"); ok {
+		t.Errorf("Geocode(Atlantis) = %+v, want not found", p)
+	}
+}
@@ -0,0 +1,30 @@
+// Package mailer sends transactional email. SMTPMailer is used when SMTP_HOST is set;
+// otherwise MemoryMailer keeps messages in memory and logs them so links can be copied
+// during local development.
+package mailer
+
+import (
+	"context"
+
+	"github.com/jobping/backend/internal/config"
+)
+
+// Message is a single email. HTMLBody is optional; TextBody is always sent.
+type Message struct {
+	To       string
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New returns an SMTP mailer when SMTP is configured and a logging in-memory mailer otherwise
+func New(cfg *config.Config) Mailer {
+	if cfg.SMTPHost == "" {
+		return NewMemoryMailer(true)
+	}
+	return NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+}
@@ -0,0 +1,57 @@
+package mailer
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// MemoryMailer records messages instead of sending them, for local development and tests
+type MemoryMailer struct {
+	logMessages bool
+
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewMemoryMailer returns an in-memory mailer. With logMessages set, each message is also logged.
+func NewMemoryMailer(logMessages bool) *MemoryMailer {
+	return &MemoryMailer{logMessages: logMessages}
+}
+
+func (m *MemoryMailer) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	m.messages = append(m.messages, msg)
+	m.mu.Unlock()
+
+	if m.logMessages {
+		log.Printf("Email to %s: %s\n%s", msg.To, msg.Subject, msg.TextBody)
+	}
+	return nil
+}
+
+// Messages returns a copy of all messages sent so far
+func (m *MemoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.messages...)
+}
+
+// Last returns the most recent message sent to the address, if any
+func (m *MemoryMailer) Last(to string) (Message, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if m.messages[i].To == to {
+			return m.messages[i], true
+		}
+	}
+	return Message{}, false
+}
+
+// Reset discards recorded messages
+func (m *MemoryMailer) Reset() {
+	m.mu.Lock()
+	m.messages = nil
+	m.mu.Unlock()
+}
@@ -0,0 +1,136 @@
+// Package salary annualizes posted pay and converts it to one base currency, so salaries
+// scraped as "$60/hour" and "€90k/year" can be compared, filtered and sorted.
+// Rates are a static table, not live exchange rates: close enough to rank jobs.
+package salary
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Pay intervals as reported by the job boards
+const (
+	IntervalHourly  = "hourly"
+	IntervalDaily   = "daily"
+	IntervalWeekly  = "weekly"
+	IntervalMonthly = "monthly"
+	IntervalYearly  = "yearly"
+)
+
+// periodsPerYear converts an amount per interval to a yearly amount, assuming full-time work
+var periodsPerYear = map[string]float64{
+	IntervalHourly:  2080,
+	IntervalDaily:   260,
+	IntervalWeekly:  52,
+	IntervalMonthly: 12,
+	IntervalYearly:  1,
+}
+
+// defaultUSDRates is the value of one unit of each currency in US dollars
+var defaultUSDRates = map[string]float64{
+	"USD": 1,
+	"EUR": 1.08,
+	"GBP": 1.27,
+	"CHF": 1.13,
+	"CAD": 0.73,
+	"AUD": 0.66,
+	"NZD": 0.60,
+	"SGD": 0.74,
+	"JPY": 0.0067,
+	"INR": 0.012,
+	"SEK": 0.095,
+	"NOK": 0.093,
+	"DKK": 0.145,
+	"PLN": 0.25,
+	"BRL": 0.18,
+	"MXN": 0.055,
+}
+
+// Normalizer converts salaries to yearly amounts in Base
+type Normalizer struct {
+	Base string
+	// usdRates holds the US dollar value of one unit of each known currency
+	usdRates map[string]float64
+}
+
+// NewNormalizer builds a normalizer for base (default USD). overrides are "CODE=rate" entries
+// giving the US dollar value of one unit of a currency; they replace or extend the built-in table.
+func NewNormalizer(base string, overrides []string) (*Normalizer, error) {
+	rates := make(map[string]float64, len(defaultUSDRates)+len(overrides))
+	for code, rate := range defaultUSDRates {
+		rates[code] = rate
+	}
+	for _, entry := range overrides {
+		code, value, ok := strings.Cut(entry, "=")
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if !ok || err != nil || rate <= 0 {
+			return nil, fmt.Errorf("invalid salary rate %q, want CODE=rate", entry)
+		}
+		rates[normalizeCurrency(code)] = rate
+	}
+
+	base = normalizeCurrency(base)
+	if base == "" {
+		base = "USD"
+	}
+	if _, ok := rates[base]; !ok {
+		return nil, fmt.Errorf("no salary rate for base currency %s", base)
+	}
+	return &Normalizer{Base: base, usdRates: rates}, nil
+}
+
+// Annualize returns amount as a yearly amount in the base currency. An empty currency is taken as
+// the base currency and an empty interval as yearly. It returns nil for a missing amount or an
+// unknown currency or interval.
+func (n *Normalizer) Annualize(amount *float64, currency, interval string) *float64 {
+	if amount == nil || *amount <= 0 {
+		return nil
+	}
+
+	currency = normalizeCurrency(currency)
+	if currency == "" {
+		currency = n.Base
+	}
+	rate, ok := n.usdRates[currency]
+	if !ok {
+		return nil
+	}
+
+	interval = NormalizeInterval(interval)
+	if interval == "" {
+		interval = IntervalYearly
+	}
+	periods, ok := periodsPerYear[interval]
+	if !ok {
+		return nil
+	}
+
+	yearly := *amount * periods * rate / n.usdRates[n.Base]
+	// Whole currency units are plenty for comparisons
+	yearly = float64(int64(yearly + 0.5))
+	return &yearly
+}
+
+// NormalizeInterval maps the interval spellings seen on job boards to the Interval constants.
+// Unknown values are returned lower-cased, so Annualize rejects them.
+func NormalizeInterval(interval string) string {
+	interval = strings.ToLower(strings.TrimSpace(interval))
+	switch interval {
+	case "hour", "hr", "per hour":
+		return IntervalHourly
+	case "day", "per day":
+		return IntervalDaily
+	case "week", "per week":
+		return IntervalWeekly
+	case "month", "per month":
+		return IntervalMonthly
+	case "year", "annual", "annually", "per year":
+		return IntervalYearly
+	}
+	return interval
+}
+
+func normalizeCurrency(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,81 @@
+package salary
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAnnualize(t *testing.T) {
+	usd, err := NewNormalizer("", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eur, err := NewNormalizer("eur", []string{"XYZ=2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		n        *Normalizer
+		amount   float64
+		currency string
+		interval string
+		want     float64 // 0 means nil
+	}{
+		{usd, 100000, "USD", "yearly", 100000},
+		{usd, 60, "usd", "hourly", 124800},
+		{usd, 400, "USD", "day", 104000},
+		{usd, 2000, "USD", "per week", 104000},
+		{usd, 8000, "USD", "Monthly", 96000},
+		// Empty currency is the base, empty interval is yearly
+		{usd, 90000, "", "", 90000},
+		{usd, 90000, "EUR", "annual", 97200},
+		{usd, 1000000, "JPY", "yearly", 6700},
+		// Converting to a non-USD base goes through the dollar rates
+		{eur, 108000, "USD", "yearly", 100000},
+		{eur, 90000, "", "", 90000},
+		{eur, 1000, "XYZ", "yearly", 1852},
+		// Unknown currency or interval, or no positive amount
+		{usd, 50000, "ABC", "yearly", 0},
+		{usd, 50000, "USD", "fortnightly", 0},
+		{usd, 0, "USD", "yearly", 0},
+		{usd, -5, "USD", "yearly", 0},
+	}
+
+	for _, tt := range tests {
+		name := fmt.Sprintf("%s %v %s %s", tt.n.Base, tt.amount, tt.currency, tt.interval)
+		t.Run(name, func(t *testing.T) {
+			amount := tt.amount
+			got := tt.n.Annualize(&amount, tt.currency, tt.interval)
+			switch {
+			case tt.want == 0 && got != nil:
+				t.Errorf("Annualize = %v, want nil", *got)
+			case tt.want != 0 && got == nil:
+				t.Errorf("Annualize = nil, want %v", tt.want)
+			case got != nil && *got != tt.want:
+				t.Errorf("Annualize = %v, want %v", *got, tt.want)
+			}
+		})
+	}
+
+	if got := usd.Annualize(nil, "USD", "yearly"); got != nil {
+		t.Errorf("Annualize(nil) = %v, want nil", *got)
+	}
+}
+
+func TestNewNormalizerRejectsBadRates(t *testing.T) {
+	tests := [][]string{
+		{"EUR"},
+		{"EUR=abc"},
+		{"EUR=0"},
+		{"EUR=-1"},
+	}
+	for _, overrides := range tests {
+		if _, err := NewNormalizer("USD", overrides); err == nil {
+			t.Errorf("NewNormalizer(%q) accepted the rates", overrides)
+		}
+	}
+	if _, err := NewNormalizer("ABC", nil); err == nil {
+		t.Error("NewNormalizer accepted a base currency without a rate")
+	}
+}
@@ -6,22 +6,30 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
+	"github.com/jobping/backend/internal/clientip"
+	"github.com/jobping/backend/internal/features/admin"
+	adminhandler "github.com/jobping/backend/internal/features/admin/handler"
+	"github.com/jobping/backend/internal/features/event"
+	eventhandler "github.com/jobping/backend/internal/features/event/handler"
 	"github.com/jobping/backend/internal/features/job"
 	jobhandler "github.com/jobping/backend/internal/features/job/handler"
+	"github.com/jobping/backend/internal/features/notification"
 	notificationhandler "github.com/jobping/backend/internal/features/notification/handler"
 	"github.com/jobping/backend/internal/features/user"
 	userhandler "github.com/jobping/backend/internal/features/user/handler"
+	usermodel "github.com/jobping/backend/internal/features/user/model"
 )
 
-func NewRouter(userHandler *userhandler.UserHandler, auth *userhandler.AuthMiddleware, jobHandler *jobhandler.JobHandler) *chi.Mux {
-	return NewRouterWithNotification(userHandler, auth, jobHandler, nil)
+func NewRouter(userHandler *userhandler.UserHandler, auth *userhandler.AuthMiddleware, clientIPs *clientip.Resolver, jobHandler *jobhandler.JobHandler) *chi.Mux {
+	return NewRouterWithNotification(userHandler, auth, clientIPs, nil, jobHandler, nil, nil)
 }
 
-func NewRouterWithNotification(userHandler *userhandler.UserHandler, auth *userhandler.AuthMiddleware, jobHandler *jobhandler.JobHandler, notificationHandler *notificationhandler.HTTPHandler) *chi.Mux {
+func NewRouterWithNotification(userHandler *userhandler.UserHandler, auth *userhandler.AuthMiddleware, clientIPs *clientip.Resolver, adminHandler *adminhandler.HTTPHandler, jobHandler *jobhandler.JobHandler, notificationHandler *notificationhandler.HTTPHandler, sseHandler *eventhandler.SSEHandler) *chi.Mux {
 	r := chi.NewRouter()
 
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
+	r.Use(clientIPs.Middleware)
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:5173", "http://localhost:3000", "*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
@@ -34,23 +42,33 @@ func NewRouterWithNotification(userHandler *userhandler.UserHandler, auth *userh
 		w.Header().Set("Content-Type", "application/json")
 		w.Write([]byte(`{"status":"ok"}`))
 	})
+	r.Get("/.well-known/jwks.json", auth.JWKS)
+
+	requireAdmin := auth.RequireRole(usermodel.RoleAdmin)
 
 	r.Route("/api", func(r chi.Router) {
 		user.RegisterRoutes(r, userHandler, auth)
-		job.RegisterRoutes(r, jobHandler)
+		job.RegisterRoutes(r, jobHandler, auth.OptionalAuthenticate, auth.Authenticate, requireAdmin)
+		if adminHandler != nil {
+			admin.RegisterRoutes(r, adminHandler, auth.Authenticate, requireAdmin)
+		}
 		if notificationHandler != nil {
-			r.Get("/notifications", notificationHandler.GetNotifications)
+			notification.RegisterRoutes(r, notificationHandler, auth.Authenticate, requireAdmin, auth.RequireScope)
+		}
+		if sseHandler != nil {
+			event.RegisterRoutes(r, sseHandler, auth.Authenticate, auth.RequireScope(usermodel.ScopeReadMatches))
 		}
 	})
 
 	return r
 }
 
-func NewUserRouter(userHandler *userhandler.UserHandler, auth *userhandler.AuthMiddleware) *chi.Mux {
+func NewUserRouter(userHandler *userhandler.UserHandler, auth *userhandler.AuthMiddleware, clientIPs *clientip.Resolver, adminHandler *adminhandler.HTTPHandler) *chi.Mux {
 	r := chi.NewRouter()
 
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
+	r.Use(clientIPs.Middleware)
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:5173", "http://localhost:3000", "*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
@@ -63,15 +81,17 @@ func NewUserRouter(userHandler *userhandler.UserHandler, auth *userhandler.AuthM
 		w.Header().Set("Content-Type", "application/json")
 		w.Write([]byte(`{"status":"ok"}`))
 	})
+	r.Get("/.well-known/jwks.json", auth.JWKS)
 
 	r.Route("/api", func(r chi.Router) {
 		user.RegisterRoutes(r, userHandler, auth)
+		admin.RegisterRoutes(r, adminHandler, auth.Authenticate, auth.RequireRole(usermodel.RoleAdmin))
 	})
 
 	return r
 }
 
-func NewJobsRouter(jobHandler *jobhandler.JobHandler, notificationHandler *notificationhandler.HTTPHandler) *chi.Mux {
+func NewJobsRouter(jobHandler *jobhandler.JobHandler, notificationHandler *notificationhandler.HTTPHandler, auth *userhandler.AuthMiddleware) *chi.Mux {
 	r := chi.NewRouter()
 
 	r.Use(middleware.Logger)
@@ -91,7 +111,9 @@ func NewJobsRouter(jobHandler *jobhandler.JobHandler, notificationHandler *notif
 
 	r.Route("/api", func(r chi.Router) {
 		r.Get("/jobs", jobHandler.GetJobs)
-		r.Get("/notifications", notificationHandler.GetNotifications)
+		r.With(auth.OptionalAuthenticate).Get("/jobs/{id}", jobHandler.GetJob)
+		r.With(auth.Authenticate, auth.RequireRole(usermodel.RoleAdmin)).Get("/jobs/{id}/timeline", jobHandler.GetTimeline)
+		notification.RegisterRoutes(r, notificationHandler, auth.Authenticate, auth.RequireRole(usermodel.RoleAdmin), auth.RequireScope)
 	})
 
 	return r
@@ -44,15 +44,18 @@ function App() {
   }
 
   const handleDeleteAllJobs = async () => {
-    if (!confirm('Are you sure you want to delete all jobs? This cannot be undone.')) {
-      return
-    }
+    // Every job created before now; count first so the confirmation says what goes
+    const filter = { older_than: '0d' }
     try {
       setDeleting(true)
       setError(null)
       setMessage(null)
-      await api.deleteAllJobs()
-      setMessage('All jobs deleted successfully')
+      const preview = await api.deleteJobs(filter, true)
+      if (!confirm(`Delete ${preview.deleted} jobs, ${preview.matches_deleted} matches and ${preview.notifications_deleted} notifications? This cannot be undone.`)) {
+        return
+      }
+      const result = await api.deleteJobs(filter)
+      setMessage(`Deleted ${result.deleted} jobs`)
       setJobs([])
     } catch (err) {
       setError(err instanceof Error ? err.message : 'Failed to delete jobs')
@@ -1,7 +1,12 @@
 const API_URL = import.meta.env.VITE_API_URL || 'http://localhost:8080';
 
-interface AuthResponse {
+interface TokenResponse {
   token: string;
+  refresh_token: string;
+  expires_in: number;
+}
+
+interface AuthResponse extends TokenResponse {
   user: {
     id: string;
     username: string;
@@ -37,6 +42,20 @@ interface JobsResponse {
   jobs: Job[];
 }
 
+interface DeleteJobsFilter {
+  status?: string;
+  company?: string;
+  source?: string;
+  older_than?: string;
+}
+
+interface DeleteJobsResponse {
+  deleted: number;
+  matches_deleted: number;
+  notifications_deleted: number;
+  dry_run: boolean;
+}
+
 interface FetchJobsResponse {
   message: string;
   jobs_found: number;
@@ -45,12 +64,15 @@ interface FetchJobsResponse {
 
 class ApiService {
   private token: string | null = null;
+  private refreshToken: string | null = null;
+  private refreshing: Promise<boolean> | null = null;
 
   constructor() {
     this.token = localStorage.getItem('token');
+    this.refreshToken = localStorage.getItem('refresh_token');
   }
 
-  private async request<T>(path: string, options: RequestInit = {}): Promise<T> {
+  private async request<T>(path: string, options: RequestInit = {}, retry = true): Promise<T> {
     const headers: Record<string, string> = {
       'Content-Type': 'application/json',
       ...((options.headers as Record<string, string>) || {}),
@@ -65,6 +87,13 @@ class ApiService {
       headers,
     });
 
+    // Access tokens are short-lived; renew once with the refresh token and replay the request
+    if (response.status === 401 && retry && this.refreshToken && !path.startsWith('/api/auth/')) {
+      if (await this.refreshSession()) {
+        return this.request<T>(path, options, false);
+      }
+    }
+
     if (!response.ok) {
       const error = await response.json().catch(() => ({ message: 'Request failed' }));
       throw new Error(error.message || `HTTP ${response.status}`);
@@ -77,6 +106,30 @@ class ApiService {
     return response.json();
   }
 
+  // Concurrent 401s share one refresh, since a refresh token can only be used once
+  private refreshSession(): Promise<boolean> {
+    if (!this.refreshing) {
+      this.refreshing = fetch(`${API_URL}/api/auth/refresh`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ refresh_token: this.refreshToken }),
+      })
+        .then(async (response) => {
+          if (!response.ok) {
+            this.setTokens(null);
+            return false;
+          }
+          this.setTokens(await response.json());
+          return true;
+        })
+        .catch(() => false)
+        .finally(() => {
+          this.refreshing = null;
+        });
+    }
+    return this.refreshing;
+  }
+
   setToken(token: string | null) {
     this.token = token;
     if (token) {
@@ -86,6 +139,16 @@ class ApiService {
     }
   }
 
+  private setTokens(tokens: TokenResponse | null) {
+    this.setToken(tokens?.token ?? null);
+    this.refreshToken = tokens?.refresh_token ?? null;
+    if (this.refreshToken) {
+      localStorage.setItem('refresh_token', this.refreshToken);
+    } else {
+      localStorage.removeItem('refresh_token');
+    }
+  }
+
   getToken() {
     return this.token;
   }
@@ -100,7 +163,7 @@ class ApiService {
       method: 'POST',
       body: JSON.stringify({ username, password }),
     });
-    this.setToken(response.token);
+    this.setTokens(response);
     return response;
   }
 
@@ -109,12 +172,18 @@ class ApiService {
       method: 'POST',
       body: JSON.stringify({ username, password }),
     });
-    this.setToken(response.token);
+    this.setTokens(response);
     return response;
   }
 
   logout() {
-    this.setToken(null);
+    if (this.refreshToken) {
+      this.request('/api/auth/logout', {
+        method: 'POST',
+        body: JSON.stringify({ refresh_token: this.refreshToken }),
+      }).catch(() => {});
+    }
+    this.setTokens(null);
   }
 
   // Preferences
@@ -160,13 +229,19 @@ class ApiService {
     return response.jobs || [];
   }
 
-  async deleteAllJobs(): Promise<void> {
-    await this.request<void>('/api/jobs', {
+  // Admin only. With dryRun, returns what would be deleted without deleting it.
+  async deleteJobs(filter: DeleteJobsFilter, dryRun = false): Promise<DeleteJobsResponse> {
+    const params = new URLSearchParams();
+    Object.entries(filter).forEach(([key, value]) => {
+      if (value) params.set(key, value);
+    });
+    if (dryRun) params.set('dry_run', 'true');
+    return this.request<DeleteJobsResponse>(`/api/admin/jobs?${params}`, {
       method: 'DELETE',
     });
   }
 }
 
 export const api = new ApiService();
-export type { Preference, AuthResponse, Job, FetchJobsResponse };
+export type { Preference, AuthResponse, Job, FetchJobsResponse, DeleteJobsFilter, DeleteJobsResponse };
 
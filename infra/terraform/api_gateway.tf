@@ -55,12 +55,54 @@ resource "aws_apigatewayv2_route" "jobs" {
   target    = "integrations/${aws_apigatewayv2_integration.jobs_api.id}"
 }
 
+resource "aws_apigatewayv2_route" "job_detail" {
+  api_id    = aws_apigatewayv2_api.api.id
+  route_key = "GET /api/jobs/{id}"
+  target    = "integrations/${aws_apigatewayv2_integration.jobs_api.id}"
+}
+
+resource "aws_apigatewayv2_route" "job_timeline" {
+  api_id    = aws_apigatewayv2_api.api.id
+  route_key = "GET /api/jobs/{id}/timeline"
+  target    = "integrations/${aws_apigatewayv2_integration.jobs_api.id}"
+}
+
 resource "aws_apigatewayv2_route" "notifications" {
   api_id    = aws_apigatewayv2_api.api.id
   route_key = "GET /api/notifications"
   target    = "integrations/${aws_apigatewayv2_integration.jobs_api.id}"
 }
 
+resource "aws_apigatewayv2_route" "user_notifications_list" {
+  api_id    = aws_apigatewayv2_api.api.id
+  route_key = "GET /api/users/me/notifications"
+  target    = "integrations/${aws_apigatewayv2_integration.jobs_api.id}"
+}
+
+resource "aws_apigatewayv2_route" "user_notifications" {
+  api_id    = aws_apigatewayv2_api.api.id
+  route_key = "ANY /api/users/me/notifications/{proxy+}"
+  target    = "integrations/${aws_apigatewayv2_integration.jobs_api.id}"
+}
+
+resource "aws_apigatewayv2_route" "notification_templates" {
+  api_id    = aws_apigatewayv2_api.api.id
+  route_key = "GET /api/users/me/notification-templates"
+  target    = "integrations/${aws_apigatewayv2_integration.jobs_api.id}"
+}
+
+resource "aws_apigatewayv2_route" "notification_template" {
+  api_id    = aws_apigatewayv2_api.api.id
+  route_key = "ANY /api/users/me/notification-templates/{channel}"
+  target    = "integrations/${aws_apigatewayv2_integration.jobs_api.id}"
+}
+
+resource "aws_apigatewayv2_route" "admin_notifications" {
+  api_id    = aws_apigatewayv2_api.api.id
+  route_key = "GET /api/admin/notifications"
+  target    = "integrations/${aws_apigatewayv2_integration.jobs_api.id}"
+}
+
 # JobSpy route
 resource "aws_apigatewayv2_route" "jobspy" {
   api_id    = aws_apigatewayv2_api.api.id
@@ -41,3 +41,110 @@ output "eventbridge_rule_arn" {
 }
 
 
+
+# EventBridge rule for notification digests (deferred by quiet hours / rate limits)
+resource "aws_cloudwatch_event_rule" "notification_digest_cron" {
+  name                = "jobping-notification-digest"
+  description         = "Deliver deferred notifications every hour"
+  schedule_expression = "rate(1 hour)"
+
+  tags = {
+    Environment = "production"
+    Project     = "jobping"
+  }
+}
+
+# Target: notification queue (processed by the notifier worker)
+resource "aws_cloudwatch_event_target" "notification_digest" {
+  rule      = aws_cloudwatch_event_rule.notification_digest_cron.name
+  target_id = "NotificationDigest"
+  arn       = aws_sqs_queue.notification.arn
+
+  input = jsonencode({
+    "type" : "digest"
+  })
+}
+
+# Permission for EventBridge to send to the notification queue
+resource "aws_sqs_queue_policy" "notification_eventbridge" {
+  queue_url = aws_sqs_queue.notification.id
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect    = "Allow"
+        Principal = { Service = "events.amazonaws.com" }
+        Action    = "sqs:SendMessage"
+        Resource  = aws_sqs_queue.notification.arn
+        Condition = {
+          ArnEquals = { "aws:SourceArn" = aws_cloudwatch_event_rule.notification_digest_cron.arn }
+        }
+      }
+    ]
+  })
+}
+
+# EventBridge rule for purging accounts whose deletion grace period has ended
+resource "aws_cloudwatch_event_rule" "account_purge_cron" {
+  name                = "jobping-account-purge"
+  description         = "Delete accounts scheduled for deletion once a day"
+  schedule_expression = "rate(1 day)"
+
+  tags = {
+    Environment = "production"
+    Project     = "jobping"
+  }
+}
+
+# Target: API Lambda (handles the scheduled event outside the router)
+resource "aws_cloudwatch_event_target" "account_purge" {
+  rule      = aws_cloudwatch_event_rule.account_purge_cron.name
+  target_id = "AccountPurge"
+  arn       = aws_lambda_function.api.arn
+
+  input = jsonencode({
+    "type" : "purge_accounts"
+  })
+}
+
+# Permission for EventBridge to invoke the API Lambda
+resource "aws_lambda_permission" "eventbridge_invoke_api" {
+  statement_id  = "AllowEventBridgeInvokePurge"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.api.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.account_purge_cron.arn
+}
+
+# EventBridge rule for the job lifecycle sweep (expiry, closed postings, archival)
+resource "aws_cloudwatch_event_rule" "job_lifecycle_cron" {
+  name                = "jobping-job-lifecycle"
+  description         = "Expire, close and archive jobs every hour"
+  schedule_expression = "rate(1 hour)"
+
+  tags = {
+    Environment = "production"
+    Project     = "jobping"
+  }
+}
+
+# Target: Jobs API Lambda (handles the scheduled event outside the router)
+resource "aws_cloudwatch_event_target" "job_lifecycle" {
+  rule      = aws_cloudwatch_event_rule.job_lifecycle_cron.name
+  target_id = "JobLifecycle"
+  arn       = aws_lambda_function.jobs_api.arn
+
+  input = jsonencode({
+    "type" : "sweep_jobs"
+  })
+}
+
+# Permission for EventBridge to invoke the Jobs API Lambda
+resource "aws_lambda_permission" "eventbridge_invoke_jobs_api" {
+  statement_id  = "AllowEventBridgeInvokeSweep"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.jobs_api.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.job_lifecycle_cron.arn
+}
@@ -35,9 +35,33 @@ resource "aws_lambda_function" "api" {
 
   environment {
     variables = {
-      ENVIRONMENT  = "production"
-      DATABASE_URL = "postgres://jobscanner:${var.db_password}@${aws_db_instance.postgres.endpoint}/jobscanner?sslmode=require"
-      JWT_SECRET   = var.jwt_secret
+      ENVIRONMENT     = "production"
+      DATABASE_URL    = "postgres://jobscanner:${var.db_password}@${aws_db_instance.postgres.endpoint}/jobscanner?sslmode=require"
+      JWT_SECRET      = var.jwt_secret
+      JWT_SIGNING_KEY = var.jwt_signing_key
+      JWT_VERIFY_KEYS = var.jwt_public_keys
+      SMTP_HOST       = var.smtp_host
+      SMTP_USERNAME   = var.smtp_username
+      SMTP_PASSWORD   = var.smtp_password
+      MAIL_FROM       = var.mail_from
+      APP_BASE_URL    = var.app_base_url
+
+      GITHUB_CLIENT_ID     = var.github_client_id
+      GITHUB_CLIENT_SECRET = var.github_client_secret
+      GOOGLE_CLIENT_ID     = var.google_client_id
+      GOOGLE_CLIENT_SECRET = var.google_client_secret
+      OIDC_ISSUER          = var.oidc_issuer
+      OIDC_CLIENT_ID       = var.oidc_client_id
+      OIDC_CLIENT_SECRET   = var.oidc_client_secret
+
+      # Resume analysis and on-demand job evaluation (company research and matching)
+      OPENAI_API_KEY = var.openai_api_key
+
+      # Admin pipeline controls (the user analysis queue also takes prompt rescores)
+      JOB_ANALYSIS_QUEUE_URL  = aws_sqs_queue.job_analysis.url
+      USER_FANOUT_QUEUE_URL   = aws_sqs_queue.user_fanout.url
+      USER_ANALYSIS_QUEUE_URL = aws_sqs_queue.user_analysis.url
+      NOTIFICATION_QUEUE_URL  = aws_sqs_queue.notification.url
     }
   }
 
@@ -62,9 +86,10 @@ resource "aws_lambda_function" "jobs_api" {
 
   environment {
     variables = {
-      ENVIRONMENT  = "production"
-      DATABASE_URL = "postgres://jobscanner:${var.db_password}@${aws_db_instance.postgres.endpoint}/jobscanner?sslmode=require"
-      JWT_SECRET   = var.jwt_secret
+      ENVIRONMENT     = "production"
+      DATABASE_URL    = "postgres://jobscanner:${var.db_password}@${aws_db_instance.postgres.endpoint}/jobscanner?sslmode=require"
+      JWT_SECRET      = var.jwt_secret
+      JWT_VERIFY_KEYS = var.jwt_public_keys
     }
   }
 
@@ -170,10 +195,15 @@ resource "aws_lambda_function" "notifier_worker" {
   filename         = "${path.module}/../../build/notifier_worker.zip"
   source_code_hash = fileexists("${path.module}/../../build/notifier_worker.zip") ? filebase64sha256("${path.module}/../../build/notifier_worker.zip") : null
 
+  # Sends the email channel; Discord and Slack go to the users' webhooks
   environment {
     variables = {
-      ENVIRONMENT  = "production"
-      DATABASE_URL = "postgres://jobscanner:${var.db_password}@${aws_db_instance.postgres.endpoint}/jobscanner?sslmode=require"
+      ENVIRONMENT   = "production"
+      DATABASE_URL  = "postgres://jobscanner:${var.db_password}@${aws_db_instance.postgres.endpoint}/jobscanner?sslmode=require"
+      SMTP_HOST     = var.smtp_host
+      SMTP_USERNAME = var.smtp_username
+      SMTP_PASSWORD = var.smtp_password
+      MAIL_FROM     = var.mail_from
     }
   }
 
@@ -30,6 +30,95 @@ variable "jwt_secret" {
   sensitive   = true
 }
 
+variable "jwt_signing_key" {
+  description = "PEM private key (RSA or Ed25519) used by the api Lambda to sign access tokens"
+  type        = string
+  sensitive   = true
+  default     = ""
+}
+
+variable "jwt_public_keys" {
+  description = "PEM public keys accepted for access tokens: the current signing key plus any being rotated out"
+  type        = string
+  default     = ""
+}
+
+variable "smtp_host" {
+  description = "SMTP relay for account emails (verification, password reset). Empty logs emails instead."
+  type        = string
+  default     = ""
+}
+
+variable "smtp_username" {
+  description = "SMTP username"
+  type        = string
+  default     = ""
+}
+
+variable "smtp_password" {
+  description = "SMTP password"
+  type        = string
+  sensitive   = true
+  default     = ""
+}
+
+variable "mail_from" {
+  description = "From address for account emails"
+  type        = string
+  default     = "JobPing <no-reply@jobping.app>"
+}
+
+variable "app_base_url" {
+  description = "Frontend URL used in links sent by email"
+  type        = string
+  default     = ""
+}
+
+variable "github_client_id" {
+  description = "GitHub OAuth app client ID; empty disables GitHub login"
+  type        = string
+  default     = ""
+}
+
+variable "github_client_secret" {
+  description = "GitHub OAuth app client secret"
+  type        = string
+  sensitive   = true
+  default     = ""
+}
+
+variable "google_client_id" {
+  description = "Google OAuth client ID; empty disables Google login"
+  type        = string
+  default     = ""
+}
+
+variable "google_client_secret" {
+  description = "Google OAuth client secret"
+  type        = string
+  sensitive   = true
+  default     = ""
+}
+
+variable "oidc_issuer" {
+  description = "Issuer URL of an additional OpenID Connect provider; empty disables it"
+  type        = string
+  default     = ""
+}
+
+variable "oidc_client_id" {
+  description = "Client ID at the additional OpenID Connect provider"
+  type        = string
+  default     = ""
+}
+
+variable "oidc_client_secret" {
+  description = "Client secret at the additional OpenID Connect provider"
+  type        = string
+  sensitive   = true
+  default     = ""
+}
+
 variable "openai_api_key" {
   description = "OpenAI API key for AI job analysis"
   type        = string
@@ -132,6 +132,24 @@ resource "aws_iam_role_policy" "lambda_sqs" {
           aws_sqs_queue.user_analysis.arn,
           aws_sqs_queue.notification.arn
         ]
+      },
+      {
+        # Admin re-drive: move dead letters back to their source queues
+        Effect = "Allow"
+        Action = [
+          "sqs:GetQueueUrl",
+          "sqs:GetQueueAttributes",
+          "sqs:ReceiveMessage",
+          "sqs:DeleteMessage",
+          "sqs:StartMessageMoveTask",
+          "sqs:ListMessageMoveTasks"
+        ]
+        Resource = [
+          aws_sqs_queue.job_analysis_dlq.arn,
+          aws_sqs_queue.user_fanout_dlq.arn,
+          aws_sqs_queue.user_analysis_dlq.arn,
+          aws_sqs_queue.notification_dlq.arn
+        ]
       }
     ]
   })
@@ -41,6 +41,13 @@ def get_db_connection():
         return None
 
 
+def optional_text(value) -> str:
+    """Return a dataframe cell as a string, with missing values (None/NaN) as ''."""
+    if value is None or value != value:
+        return ""
+    return str(value).strip()
+
+
 def parse_event(event: dict) -> dict:
     """
     Parse event from either API Gateway or EventBridge.
@@ -141,51 +148,81 @@ def handler(event: dict, context: Any) -> dict:
                     "company": str(job_row.get("company", "")),
                     "location": str(job_row.get("location", "")),
                     "job_url": job_url,
+                    "source": optional_text(job_row.get("site")).lower()[:50],
                     "job_type": str(job_row.get("job_type", "")),
                     "is_remote": bool(job_row.get("is_remote", False)),
                     "min_salary": float(job_row.get("min_amount")) if job_row.get("min_amount") else None,
                     "max_salary": float(job_row.get("max_amount")) if job_row.get("max_amount") else None,
+                    "salary_currency": optional_text(job_row.get("currency")).upper()[:3],
+                    "salary_interval": optional_text(job_row.get("interval")).lower()[:10],
                     "date_posted": str(job_row.get("date_posted", "")),
                     "status": "existing",  # Default to existing, will change if created
                 }
                 
                 # Check if job already exists
-                cur.execute("SELECT id FROM jobs WHERE job_url = %s", (job_url,))
+                cur.execute("SELECT id, source FROM jobs WHERE job_url = %s", (job_url,))
                 existing = cur.fetchone()
                 
-                if existing:
+                if existing and existing[1] != "user":
                     logger.info(f"Job already exists: {job_url}, skipping")
                     all_jobs.append(job_data)
                     continue
                 
-                # Insert job into database
-                cur.execute("""
-                    INSERT INTO jobs (
-                        id, title, company, location, job_url, description, job_type,
-                        is_remote, min_salary, max_salary, date_posted, status,
-                        created_at, updated_at
-                    ) VALUES (
-                        %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s
-                    )
-                """, (
-                    str(job_id),
+                fields = (
                     str(job_row.get("title", "")),
                     str(job_row.get("company", "")),
                     str(job_row.get("location", "")),
-                    job_url,
+                    job_data["source"],
                     str(job_row.get("description", ""))[:5000],  # Truncate if too long
                     str(job_row.get("job_type", "")),
                     bool(job_row.get("is_remote", False)),
                     float(job_row.get("min_amount")) if job_row.get("min_amount") else None,
                     float(job_row.get("max_amount")) if job_row.get("max_amount") else None,
+                    job_data["salary_currency"],
+                    job_data["salary_interval"],
                     str(job_row.get("date_posted", "")),
-                    "pending",
-                    now,
-                    now,
-                ))
+                )
+                
+                if existing:
+                    # A user evaluated this posting before it was scraped. The scraped job takes
+                    # over the row, keeping its ID so the evaluation's match stays attached, and
+                    # goes through analysis and fanout like a new one.
+                    job_id = existing[0]
+                    job_data["id"] = str(job_id)
+                    cur.execute("""
+                        UPDATE jobs SET
+                            title = %s, company = %s, location = %s, source = %s, description = %s,
+                            job_type = %s, is_remote = %s, min_salary = %s, max_salary = %s,
+                            salary_currency = %s, salary_interval = %s, date_posted = %s,
+                            status = 'pending', updated_at = %s
+                        WHERE id = %s AND source = 'user'
+                    """, fields + (now, str(job_id)))
+                    if cur.rowcount == 0:
+                        logger.info(f"Job already exists: {job_url}, skipping")
+                        all_jobs.append(job_data)
+                        continue
+                    outcome = "promoted"
+                else:
+                    # Insert job into database
+                    cur.execute("""
+                        INSERT INTO jobs (
+                            id, title, company, location, source, description, job_type,
+                            is_remote, min_salary, max_salary, salary_currency, salary_interval,
+                            date_posted, job_url, status, created_at, updated_at
+                        ) VALUES (
+                            %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s
+                        )
+                    """, (str(job_id),) + fields + (job_url, "pending", now, now))
+                    jobs_created += 1
+                    outcome = "created"
+                
+                # Ingestion entry of the job's pipeline timeline (GET /api/jobs/{id}/timeline)
+                cur.execute("""
+                    INSERT INTO job_pipeline_events (job_id, stage, outcome, details, created_at)
+                    VALUES (%s, 'ingested', %s, %s, %s)
+                """, (str(job_id), outcome, json.dumps({"source": job_data["source"]}), now))
                 
-                jobs_created += 1
-                job_data["status"] = "created"  # Mark as newly created
+                job_data["status"] = outcome  # "created", or "promoted" from a user's evaluation
                 all_jobs.append(job_data)
                 
                 # Send job_id to SQS